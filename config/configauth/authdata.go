@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+)
+
+type authDataCtxKey struct{}
+
+// AuthData is the identity of a client, as established by a ServerAuthenticator.
+// It is placed in the request context by the authenticator and travels with the
+// data through the pipeline, allowing components to rely on the authenticated
+// identity rather than on identity attributes supplied by the client.
+type AuthData struct {
+	// Subject is the authenticated principal, such as a username or a tenant ID.
+	Subject string
+
+	// Groups the subject is a member of, if known to the authenticator.
+	Groups []string
+}
+
+// NewContextWithAuthData returns a new context derived from ctx carrying the given AuthData.
+func NewContextWithAuthData(ctx context.Context, ad *AuthData) context.Context {
+	return context.WithValue(ctx, authDataCtxKey{}, ad)
+}
+
+// AuthDataFromContext returns the AuthData stored in ctx, if any.
+func AuthDataFromContext(ctx context.Context) (*AuthData, bool) {
+	ad, ok := ctx.Value(authDataCtxKey{}).(*AuthData)
+	return ad, ok && ad != nil
+}
@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthDataContext(t *testing.T) {
+	ad, ok := AuthDataFromContext(context.Background())
+	assert.False(t, ok)
+	assert.Nil(t, ad)
+
+	ad, ok = AuthDataFromContext(NewContextWithAuthData(context.Background(), nil))
+	assert.False(t, ok)
+	assert.Nil(t, ad)
+
+	expected := &AuthData{Subject: "tenant-1", Groups: []string{"team-a"}}
+	ad, ok = AuthDataFromContext(NewContextWithAuthData(context.Background(), expected))
+	assert.True(t, ok)
+	assert.Equal(t, expected, ad)
+}
@@ -25,13 +25,13 @@ func TestAuthenticateFunc(t *testing.T) {
 	// prepare
 	m := &MockAuthenticator{}
 	called := false
-	m.AuthenticateFunc = func(c context.Context, m map[string][]string) error {
+	m.AuthenticateFunc = func(c context.Context, m map[string][]string) (context.Context, error) {
 		called = true
-		return nil
+		return c, nil
 	}
 
 	// test
-	err := m.Authenticate(context.Background(), nil)
+	_, err := m.Authenticate(context.Background(), nil)
 
 	// verify
 	assert.NoError(t, err)
@@ -46,8 +46,9 @@ func TestNilOperations(t *testing.T) {
 	origCtx := context.Background()
 
 	{
-		err := m.Authenticate(origCtx, nil)
+		ctx, err := m.Authenticate(origCtx, nil)
 		assert.NoError(t, err)
+		assert.Equal(t, origCtx, ctx)
 	}
 
 	{
@@ -36,11 +36,13 @@ var (
 type ServerAuthenticator interface {
 	component.Extension
 
-	// Authenticate checks whether the given headers map contains valid auth data. Successfully authenticated calls will always return a nil error.
+	// Authenticate checks whether the given headers map contains valid auth data. Successfully authenticated calls will always return a nil error
+	// and a context derived from the given one, which should carry the identity of the client as AuthData (see NewContextWithAuthData).
 	// When the authentication fails, an error must be returned and the caller must not retry. This function is typically called from interceptors,
 	// on behalf of receivers, but receivers can still call this directly if the usage of interceptors isn't suitable.
 	// The deadline and cancellation given to this function must be respected, but note that authentication data has to be part of the map, not context.
-	Authenticate(ctx context.Context, headers map[string][]string) error
+	// Header names in the map are expected to be lower case, as they are for gRPC metadata.
+	Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error)
 
 	// GRPCUnaryServerInterceptor is a helper method to provide a gRPC-compatible UnaryServerInterceptor, typically calling the authenticator's Authenticate method.
 	// While the context is the typical source of authentication data, the interceptor is free to determine where the auth data should come from. For instance, some
@@ -59,7 +61,7 @@ type ServerAuthenticator interface {
 
 // AuthenticateFunc defines the signature for the function responsible for performing the authentication based on the given headers map.
 // See ServerAuthenticator.Authenticate.
-type AuthenticateFunc func(ctx context.Context, headers map[string][]string) error
+type AuthenticateFunc func(ctx context.Context, headers map[string][]string) (context.Context, error)
 
 // GRPCUnaryInterceptorFunc defines the signature for the function intercepting unary gRPC calls, useful for authenticators to use as
 // types for internal structs, making it easier to mock them in tests.
@@ -79,7 +81,8 @@ func DefaultGRPCUnaryServerInterceptor(ctx context.Context, req interface{}, _ *
 		return nil, errMetadataNotFound
 	}
 
-	if err := authenticate(ctx, headers); err != nil {
+	ctx, err := authenticate(ctx, headers)
+	if err != nil {
 		return nil, err
 	}
 
@@ -95,9 +98,21 @@ func DefaultGRPCStreamServerInterceptor(srv interface{}, stream grpc.ServerStrea
 		return errMetadataNotFound
 	}
 
-	if err := authenticate(ctx, headers); err != nil {
+	ctx, err := authenticate(ctx, headers)
+	if err != nil {
 		return err
 	}
 
-	return handler(srv, stream)
+	return handler(srv, &wrappedServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// wrappedServerStream is a grpc.ServerStream using the context returned by the authenticator.
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the auth data.
+func (ws *wrappedServerStream) Context() context.Context {
+	return ws.ctx
 }
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+
+	"go.opentelemetry.io/collector/internal/middleware"
+)
+
+// The compressors are registered with gRPC, so that both the clients and the servers
+// created from this package support them. gzip is registered by grpc itself.
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+	encoding.RegisterCompressor(&snappyCompressor{})
+}
+
+// zstdMaxDecodedSize is the maximum decoded size of the zstd messages. They are decoded as a whole, before
+// the maximum message size of the server applies, so that the frames claiming a larger content or window size
+// are refused before their buffers are allocated. It is the bound of the zstd HTTP request bodies.
+const zstdMaxDecodedSize = middleware.MaxZstdDecoderMemory
+
+// zstdCompressor implements encoding.Compressor with zstd. The messages are compressed and
+// decompressed as a whole, with a shared encoder and decoder, so that no stream goroutines are
+// left behind by encoders or decoders that are not closed.
+type zstdCompressor struct {
+	once    sync.Once
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	err     error
+}
+
+func (c *zstdCompressor) init() error {
+	c.once.Do(func() {
+		if c.encoder, c.err = zstd.NewWriter(nil); c.err != nil {
+			return
+		}
+		c.decoder, c.err = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(zstdMaxDecodedSize))
+	})
+	return c.err
+}
+
+type zstdWriter struct {
+	w       io.Writer
+	encoder *zstd.Encoder
+	buf     bytes.Buffer
+}
+
+func (z *zstdWriter) Write(p []byte) (int, error) {
+	return z.buf.Write(p)
+}
+
+func (z *zstdWriter) Close() error {
+	_, err := z.w.Write(z.encoder.EncodeAll(z.buf.Bytes(), nil))
+	return err
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	return &zstdWriter{w: w, encoder: c.encoder}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	compressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := c.decoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(msg), nil
+}
+
+func (c *zstdCompressor) Name() string {
+	return CompressionZstd
+}
+
+// snappyCompressor implements encoding.Compressor with the snappy framing format, reusing the writers and readers.
+type snappyCompressor struct {
+	writerPool sync.Pool
+	readerPool sync.Pool
+}
+
+type snappyWriter struct {
+	*snappy.Writer
+	pool *sync.Pool
+}
+
+func (s *snappyWriter) Close() error {
+	defer s.pool.Put(s)
+	return s.Writer.Close()
+}
+
+type snappyReader struct {
+	*snappy.Reader
+	pool *sync.Pool
+}
+
+func (s *snappyReader) Read(p []byte) (int, error) {
+	n, err := s.Reader.Read(p)
+	if err == io.EOF {
+		s.pool.Put(s)
+	}
+	return n, err
+}
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if s, ok := c.writerPool.Get().(*snappyWriter); ok {
+		s.Writer.Reset(w)
+		return s, nil
+	}
+	return &snappyWriter{Writer: snappy.NewBufferedWriter(w), pool: &c.writerPool}, nil
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	if s, ok := c.readerPool.Get().(*snappyReader); ok {
+		s.Reader.Reset(r)
+		return s, nil
+	}
+	return &snappyReader{Reader: snappy.NewReader(r), pool: &c.readerPool}, nil
+}
+
+func (c *snappyCompressor) Name() string {
+	return CompressionSnappy
+}
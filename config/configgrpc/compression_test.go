@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+
+	"go.opentelemetry.io/collector/internal/goldendataset"
+)
+
+var compressionTypes = []string{CompressionGzip, CompressionZstd, CompressionSnappy}
+
+func compress(t testing.TB, c encoding.Compressor, data []byte) []byte {
+	var buf bytes.Buffer
+	w, err := c.Compress(&buf)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func decompress(t testing.TB, c encoding.Compressor, data []byte) []byte {
+	r, err := c.Decompress(bytes.NewReader(data))
+	require.NoError(t, err)
+	out, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	return out
+}
+
+func TestCompressors(t *testing.T) {
+	msg := []byte("uncompressed message, uncompressed message, uncompressed message")
+	for _, compressionType := range compressionTypes {
+		t.Run(compressionType, func(t *testing.T) {
+			c := encoding.GetCompressor(GetGRPCCompressionKey(compressionType))
+			require.NotNil(t, c)
+			assert.Equal(t, compressionType, c.Name())
+			// Run several times, to exercise the reuse of writers and readers.
+			for i := 0; i < 3; i++ {
+				compressed := compress(t, c, msg)
+				assert.NotEqual(t, msg, compressed)
+				assert.Equal(t, msg, decompress(t, c, compressed))
+			}
+			// Invalid input is detected either when creating the reader or when reading from it.
+			r, err := c.Decompress(bytes.NewReader(msg))
+			if err == nil {
+				_, err = ioutil.ReadAll(r)
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestZstdCompressorMaxDecodedSize(t *testing.T) {
+	c := encoding.GetCompressor(CompressionZstd)
+	require.NotNil(t, c)
+
+	// Highly compressible messages over the maximum decoded size are refused.
+	_, err := c.Decompress(bytes.NewReader(compress(t, c, make([]byte, zstdMaxDecodedSize+1))))
+	assert.Error(t, err)
+
+	msg := make([]byte, zstdMaxDecodedSize)
+	assert.Equal(t, msg, decompress(t, c, compress(t, c, msg)))
+}
+
+func BenchmarkCompressors(b *testing.B) {
+	payloads, err := goldendataset.GenerateOTLPPayloads(filepath.Join("..", "..", "internal", "goldendataset", "testdata"))
+	require.NoError(b, err)
+	for _, compressionType := range compressionTypes {
+		c := encoding.GetCompressor(GetGRPCCompressionKey(compressionType))
+		for _, p := range payloads {
+			payload := p.Data
+			b.Run(compressionType+"/"+p.Name+"/compress", func(b *testing.B) {
+				b.SetBytes(int64(len(payload)))
+				b.ReportAllocs()
+				var compressedSize int
+				for i := 0; i < b.N; i++ {
+					compressedSize = len(compress(b, c, payload))
+				}
+				b.ReportMetric(float64(len(payload))/float64(compressedSize), "ratio")
+			})
+			b.Run(compressionType+"/"+p.Name+"/decompress", func(b *testing.B) {
+				compressed := compress(b, c, payload)
+				b.SetBytes(int64(len(payload)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					decompress(b, c, compressed)
+				}
+			})
+		}
+	}
+}
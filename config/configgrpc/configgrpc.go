@@ -32,12 +32,15 @@ import (
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/obsreport"
 )
 
 // Compression gRPC keys for supported compression types within collector.
 const (
 	CompressionUnsupported = ""
 	CompressionGzip        = "gzip"
+	CompressionZstd        = "zstd"
+	CompressionSnappy      = "snappy"
 
 	PerRPCAuthTypeBearer = "bearer"
 )
@@ -45,7 +48,9 @@ const (
 var (
 	// Map of opentelemetry compression types to grpc registered compression types.
 	gRPCCompressionKeyMap = map[string]string{
-		CompressionGzip: gzip.Name,
+		CompressionGzip:   gzip.Name,
+		CompressionZstd:   CompressionZstd,
+		CompressionSnappy: CompressionSnappy,
 	}
 )
 
@@ -69,7 +74,7 @@ type GRPCClientSettings struct {
 	Endpoint string `mapstructure:"endpoint"`
 
 	// The compression key for supported compression types within
-	// collector. Currently the only supported mode is `gzip`.
+	// collector, one of `gzip`, `zstd` and `snappy`.
 	Compression string `mapstructure:"compression"`
 
 	// TLSSetting struct exposes TLS client configuration.
@@ -156,6 +161,32 @@ type GRPCServerSettings struct {
 
 	// Auth for this receiver
 	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
+
+	// RateLimit sets the rate limits applied to each client.
+	// The default value is nil, which will cause the server to not limit the clients.
+	RateLimit *RateLimitSettings `mapstructure:"rate_limit,omitempty"`
+
+	// MaxConnectionsPerPeer sets the maximum number of connections open at once by each client IP address.
+	// Additional connections are closed as soon as accepted. 0 means no limit.
+	MaxConnectionsPerPeer int `mapstructure:"max_connections_per_peer"`
+
+	// throttled tracks the clients refused by the limits of the server created with these settings.
+	throttled *throttledPeers `mapstructure:"-"`
+}
+
+// Validate checks the gRPC client settings.
+func (gcs *GRPCClientSettings) Validate() error {
+	return gcs.TLSSetting.Validate()
+}
+
+// endpointHost returns the host of the gRPC target, e.g. "localhost" for "dns:///localhost:4317".
+func endpointHost(endpoint string) string {
+	endpoint = endpoint[strings.LastIndex(endpoint, "/")+1:]
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return host
 }
 
 // ToDialOptions maps configgrpc.GRPCClientSettings to a slice of dial options for gRPC.
@@ -169,7 +200,7 @@ func (gcs *GRPCClientSettings) ToDialOptions(ext map[config.ComponentID]componen
 		}
 	}
 
-	tlsCfg, err := gcs.TLSSetting.LoadTLSConfig()
+	tlsCfg, err := gcs.TLSSetting.LoadTLSConfigForHost(endpointHost(gcs.Endpoint))
 	if err != nil {
 		return nil, err
 	}
@@ -238,14 +269,76 @@ func validateBalancerName(balancerName string) bool {
 	return false
 }
 
+// Validate checks the gRPC server settings.
+func (gss *GRPCServerSettings) Validate() error {
+	if gss.MaxConnectionsPerPeer < 0 {
+		return fmt.Errorf("max_connections_per_peer must not be negative")
+	}
+	if gss.RateLimit != nil {
+		if err := gss.RateLimit.Validate(); err != nil {
+			return err
+		}
+	}
+	if gss.TLSSetting != nil {
+		return gss.TLSSetting.Validate()
+	}
+	return nil
+}
+
 // ToListener returns the net.Listener constructed from the settings.
 func (gss *GRPCServerSettings) ToListener() (net.Listener, error) {
-	return gss.NetAddr.Listen()
+	ln, err := gss.NetAddr.Listen()
+	if err != nil {
+		return nil, err
+	}
+	return gss.LimitListener(ln), nil
+}
+
+// LimitListener returns a net.Listener enforcing MaxConnectionsPerPeer on the given one,
+// for the servers that don't create their listener with ToListener. The clients throttled
+// by the server are listed by ThrottledPeers until the returned listener is closed.
+func (gss *GRPCServerSettings) LimitListener(ln net.Listener) net.Listener {
+	if gss.MaxConnectionsPerPeer <= 0 && gss.RateLimit == nil {
+		return ln
+	}
+	return newPeerLimitListener(ln, gss.MaxConnectionsPerPeer, gss.throttledPeers())
+}
+
+// throttledPeers returns the throttled clients of the server created with the settings.
+func (gss *GRPCServerSettings) throttledPeers() *throttledPeers {
+	throttledPeersMu.Lock()
+	defer throttledPeersMu.Unlock()
+	if gss.throttled == nil {
+		gss.throttled = newThrottledPeers(gss.NetAddr.Endpoint)
+	}
+	return gss.throttled
+}
+
+// toServerOptions has options that change the behavior of the gRPC server options
+// returned by GRPCServerSettings.ToServerOption().
+type toServerOptions struct {
+	obsrecv *obsreport.Receiver
+}
+
+// ToServerOption is an option to change the behavior of the gRPC server options
+// returned by GRPCServerSettings.ToServerOption().
+type ToServerOption func(opts *toServerOptions)
+
+// WithObsReport sets the obsreport.Receiver used to record the requests
+// refused because of the rate limits.
+func WithObsReport(obsrecv *obsreport.Receiver) ToServerOption {
+	return func(opts *toServerOptions) {
+		opts.obsrecv = obsrecv
+	}
 }
 
 // ToServerOption maps configgrpc.GRPCServerSettings to a slice of server options for gRPC.
-func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]component.Extension) ([]grpc.ServerOption, error) {
+func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]component.Extension, serverOpts ...ToServerOption) ([]grpc.ServerOption, error) {
 	var opts []grpc.ServerOption
+	toOpts := &toServerOptions{}
+	for _, o := range serverOpts {
+		o(toOpts)
+	}
 
 	if gss.TLSSetting != nil {
 		tlsCfg, err := gss.TLSSetting.LoadTLSConfig()
@@ -299,6 +392,25 @@ func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]compone
 		}
 	}
 
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+
+	var rl *rateLimiter
+	if gss.RateLimit != nil {
+		maxRecvMsgSize := defaultMaxRecvMsgSize
+		if gss.MaxRecvMsgSizeMiB > 0 {
+			maxRecvMsgSize = int(gss.MaxRecvMsgSizeMiB * 1024 * 1024)
+		}
+		rl = newRateLimiter(gss.RateLimit, maxRecvMsgSize, gss.throttledPeers(), toOpts.obsrecv)
+	}
+
+	// The clients limited by IP address are limited before authenticating them, so that
+	// unauthenticated clients are limited as well.
+	if rl != nil && rl.key != RateLimitKeyAuthSubject {
+		unaryInterceptors = append(unaryInterceptors, rl.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, rl.streamInterceptor)
+	}
+
 	if gss.Auth != nil {
 		componentID, cperr := config.NewIDFromString(gss.Auth.AuthenticatorName)
 		if cperr != nil {
@@ -310,9 +422,19 @@ func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]compone
 			return nil, err
 		}
 
+		unaryInterceptors = append(unaryInterceptors, authenticator.GRPCUnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, authenticator.GRPCStreamServerInterceptor)
+	}
+
+	if rl != nil && rl.key == RateLimitKeyAuthSubject {
+		unaryInterceptors = append(unaryInterceptors, rl.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, rl.streamInterceptor)
+	}
+
+	if len(unaryInterceptors) > 0 {
 		opts = append(opts,
-			grpc.UnaryInterceptor(authenticator.GRPCUnaryServerInterceptor),
-			grpc.StreamInterceptor(authenticator.GRPCStreamServerInterceptor),
+			grpc.ChainUnaryInterceptor(unaryInterceptors...),
+			grpc.ChainStreamInterceptor(streamInterceptors...),
 		)
 	}
 
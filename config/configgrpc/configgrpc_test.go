@@ -249,6 +249,14 @@ func TestUseSecure(t *testing.T) {
 	assert.Equal(t, len(dialOpts), 1)
 }
 
+func TestEndpointHost(t *testing.T) {
+	assert.Equal(t, "localhost", endpointHost("localhost:4317"))
+	assert.Equal(t, "127.0.0.1", endpointHost("127.0.0.1:4317"))
+	assert.Equal(t, "::1", endpointHost("[::1]:4317"))
+	assert.Equal(t, "localhost", endpointHost("dns:///localhost:4317"))
+	assert.Equal(t, "localhost", endpointHost("localhost"))
+}
+
 func TestGRPCServerSettingsError(t *testing.T) {
 	tests := []struct {
 		settings GRPCServerSettings
@@ -303,6 +311,34 @@ func TestGRPCServerSettingsError(t *testing.T) {
 	}
 }
 
+func TestGRPCSettingsValidate(t *testing.T) {
+	assert.NoError(t, (&GRPCClientSettings{}).Validate())
+	assert.NoError(t, (&GRPCServerSettings{}).Validate())
+
+	clientSettings := &GRPCClientSettings{
+		TLSSetting: configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{CipherSuites: []string{"TLS_UNKNOWN"}},
+		},
+	}
+	assert.Error(t, clientSettings.Validate())
+	_, err := clientSettings.ToDialOptions(nil)
+	assert.Error(t, err)
+
+	serverSettings := &GRPCServerSettings{
+		TLSSetting: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{CurvePreferences: []string{"P224"}},
+		},
+	}
+	assert.Error(t, serverSettings.Validate())
+	_, err = serverSettings.ToServerOption(nil)
+	assert.Error(t, err)
+
+	assert.EqualError(t, (&GRPCServerSettings{MaxConnectionsPerPeer: -1}).Validate(), "max_connections_per_peer must not be negative")
+	assert.EqualError(t, (&GRPCServerSettings{RateLimit: &RateLimitSettings{Key: "header"}}).Validate(),
+		`unsupported rate_limit key "header", must be one of "peer_ip" or "auth_subject"`)
+	assert.EqualError(t, (&GRPCServerSettings{RateLimit: &RateLimitSettings{BytesPerSecond: -1}}).Validate(), "rate_limit values must not be negative")
+}
+
 func TestGRPCServerSettings_ToListener_Error(t *testing.T) {
 	settings := GRPCServerSettings{
 		NetAddr: confignet.NetAddr{
@@ -329,6 +365,14 @@ func TestGetGRPCCompressionKey(t *testing.T) {
 		t.Error("Capitalization of CompressionGzip should not matter")
 	}
 
+	if GetGRPCCompressionKey("zstd") != CompressionZstd {
+		t.Error("zstd is marked as supported but returned unsupported")
+	}
+
+	if GetGRPCCompressionKey("snappy") != CompressionSnappy {
+		t.Error("snappy is marked as supported but returned unsupported")
+	}
+
 	if GetGRPCCompressionKey("badType") != CompressionUnsupported {
 		t.Error("badType is not supported but was returned as supported")
 	}
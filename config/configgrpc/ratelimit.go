@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto" //nolint:staticcheck
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	// RateLimitKeyPeerIP applies the rate limits to each client IP address.
+	RateLimitKeyPeerIP = "peer_ip"
+	// RateLimitKeyAuthSubject applies the rate limits to each authenticated subject, see configauth.AuthData.
+	// The requests without an authenticated subject are limited by client IP address.
+	RateLimitKeyAuthSubject = "auth_subject"
+
+	// RefusedReasonRateLimited is the reason recorded for the requests refused by the rate limits.
+	RefusedReasonRateLimited = "rate_limited"
+
+	// defaultMaxRecvMsgSize is the default maximum message size of gRPC servers, see grpc.MaxRecvMsgSize.
+	defaultMaxRecvMsgSize = 4 * 1024 * 1024
+
+	// peerIdleTimeout is the time after which the limits of a client that didn't send any request are discarded.
+	peerIdleTimeout = 5 * time.Minute
+
+	// maxRateLimitedPeers is the number of clients whose limits are kept, the limits of the least recently
+	// seen ones are discarded first.
+	maxRateLimitedPeers = 10000
+)
+
+// RateLimitSettings defines the rate limits applied to each client of a gRPC server.
+// The limits are enforced on unary requests, and on each message received on streams.
+type RateLimitSettings struct {
+	// Key identifies the clients the limits apply to, either "peer_ip" (default) or "auth_subject".
+	Key string `mapstructure:"key"`
+
+	// RequestsPerSecond is the number of requests allowed per second for each client. 0 means no limit.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+
+	// RequestsBurst is the number of requests allowed at once for each client.
+	// It defaults to RequestsPerSecond, rounded up.
+	RequestsBurst int `mapstructure:"requests_burst"`
+
+	// BytesPerSecond is the number of message bytes allowed per second for each client. 0 means no limit.
+	BytesPerSecond int `mapstructure:"bytes_per_second"`
+
+	// BytesBurst is the number of message bytes allowed at once for each client, messages larger than it
+	// are always refused. It defaults to the largest of BytesPerSecond and the maximum message size.
+	BytesBurst int `mapstructure:"bytes_burst"`
+}
+
+// Validate checks the rate limit settings.
+func (rls *RateLimitSettings) Validate() error {
+	switch rls.Key {
+	case "", RateLimitKeyPeerIP, RateLimitKeyAuthSubject:
+	default:
+		return fmt.Errorf("unsupported rate_limit key %q, must be one of %q or %q", rls.Key, RateLimitKeyPeerIP, RateLimitKeyAuthSubject)
+	}
+	if rls.RequestsPerSecond < 0 || rls.RequestsBurst < 0 || rls.BytesPerSecond < 0 || rls.BytesBurst < 0 {
+		return fmt.Errorf("rate_limit values must not be negative")
+	}
+	return nil
+}
+
+// peerLimiters holds the token buckets of a client.
+type peerLimiters struct {
+	requests *rate.Limiter
+	bytes    *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter enforces the rate limits of a gRPC server through its interceptors.
+type rateLimiter struct {
+	key           string
+	requestsLimit rate.Limit
+	requestsBurst int
+	bytesLimit    rate.Limit
+	bytesBurst    int
+	throttled     *throttledPeers
+	obsrecv       *obsreport.Receiver
+
+	mu          sync.Mutex
+	peers       map[string]*peerLimiters
+	lastCleanup time.Time
+}
+
+func newRateLimiter(rls *RateLimitSettings, maxRecvMsgSize int, throttled *throttledPeers, obsrecv *obsreport.Receiver) *rateLimiter {
+	rl := &rateLimiter{
+		key:           rls.Key,
+		requestsLimit: rate.Inf,
+		requestsBurst: rls.RequestsBurst,
+		bytesLimit:    rate.Inf,
+		bytesBurst:    rls.BytesBurst,
+		throttled:     throttled,
+		obsrecv:       obsrecv,
+		peers:         map[string]*peerLimiters{},
+		lastCleanup:   time.Now(),
+	}
+	if rls.RequestsPerSecond > 0 {
+		rl.requestsLimit = rate.Limit(rls.RequestsPerSecond)
+		if rl.requestsBurst == 0 {
+			rl.requestsBurst = int(math.Ceil(rls.RequestsPerSecond))
+		}
+	}
+	if rls.BytesPerSecond > 0 {
+		rl.bytesLimit = rate.Limit(rls.BytesPerSecond)
+		if rl.bytesBurst == 0 {
+			rl.bytesBurst = rls.BytesPerSecond
+			if maxRecvMsgSize > rl.bytesBurst {
+				rl.bytesBurst = maxRecvMsgSize
+			}
+		}
+	}
+	return rl
+}
+
+// limiters returns the token buckets of the given client, and discards those of the idle clients, or of the
+// least recently seen one when the limits of maxRateLimitedPeers clients are kept.
+func (rl *rateLimiter) limiters(peer string, now time.Time) *peerLimiters {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastCleanup) > peerIdleTimeout {
+		for p, pl := range rl.peers {
+			if now.Sub(pl.lastSeen) > peerIdleTimeout {
+				delete(rl.peers, p)
+			}
+		}
+		rl.lastCleanup = now
+	}
+
+	pl, ok := rl.peers[peer]
+	if !ok {
+		if len(rl.peers) >= maxRateLimitedPeers {
+			rl.evictOldest()
+		}
+		pl = &peerLimiters{
+			requests: rate.NewLimiter(rl.requestsLimit, rl.requestsBurst),
+			bytes:    rate.NewLimiter(rl.bytesLimit, rl.bytesBurst),
+		}
+		rl.peers[peer] = pl
+	}
+	pl.lastSeen = now
+	return pl
+}
+
+func (rl *rateLimiter) evictOldest() {
+	var oldest string
+	var oldestSeen time.Time
+	for p, pl := range rl.peers {
+		if oldest == "" || pl.lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = p, pl.lastSeen
+		}
+	}
+	delete(rl.peers, oldest)
+}
+
+// allow takes a request and the message bytes from the client buckets, or returns a RESOURCE_EXHAUSTED
+// error if any of them doesn't have enough tokens. Nothing is taken from the buckets of refused requests.
+func (rl *rateLimiter) allow(ctx context.Context, msg interface{}) error {
+	now := time.Now()
+	peer := rl.peerKey(ctx)
+	pl := rl.limiters(peer, now)
+
+	requests := pl.requests.ReserveN(now, 1)
+	if requests.OK() && requests.DelayFrom(now) == 0 {
+		bytes := pl.bytes.ReserveN(now, messageSize(msg))
+		if bytes.OK() && bytes.DelayFrom(now) == 0 {
+			return nil
+		}
+		bytes.CancelAt(now)
+	}
+	requests.CancelAt(now)
+
+	rl.throttled.recordRefusedRequest(peer, now)
+	if rl.obsrecv != nil {
+		rl.obsrecv.RecordRefusedRequest(ctx, RefusedReasonRateLimited)
+	}
+	return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+}
+
+// peerKey returns the key identifying the client of the request.
+func (rl *rateLimiter) peerKey(ctx context.Context) string {
+	if rl.key == RateLimitKeyAuthSubject {
+		if ad, ok := configauth.AuthDataFromContext(ctx); ok && ad.Subject != "" {
+			return ad.Subject
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return addrHost(p.Addr)
+	}
+	return "unknown"
+}
+
+func (rl *rateLimiter) unaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := rl.allow(ctx, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (rl *rateLimiter) streamInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &rateLimitedServerStream{ServerStream: stream, rl: rl})
+}
+
+// rateLimitedServerStream is a grpc.ServerStream enforcing the rate limits on the received messages.
+type rateLimitedServerStream struct {
+	grpc.ServerStream
+	rl *rateLimiter
+}
+
+// RecvMsg receives a message, and returns an error if the client exceeded its limits.
+func (s *rateLimitedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.rl.allow(s.Context(), m)
+}
+
+// messageSize returns the encoded size of the given protobuf message.
+func messageSize(msg interface{}) int {
+	switch m := msg.(type) {
+	case interface{ Size() int }:
+		return m.Size()
+	case proto.Message:
+		return proto.Size(m)
+	}
+	return 0
+}
+
+// addrHost returns the host of the given address, or the whole address if it has no port.
+func addrHost(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
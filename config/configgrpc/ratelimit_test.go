@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+)
+
+// startTracesServer starts an OTLP traces server with the given settings, and returns a client connected to it.
+func startTracesServer(t *testing.T, gss *GRPCServerSettings, opts ...ToServerOption) otlpgrpc.TracesClient {
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	serverOpts, err := gss.ToServerOption(nil, opts...)
+	require.NoError(t, err)
+	s := grpc.NewServer(serverOpts...)
+	otlpgrpc.RegisterTracesServer(s, &grpcTraceServer{})
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	t.Cleanup(s.Stop)
+
+	gcs := &GRPCClientSettings{
+		Endpoint:   ln.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{Insecure: true},
+	}
+	dialOpts, err := gcs.ToDialOptions(nil)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(gcs.Endpoint, dialOpts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return otlpgrpc.NewTracesClient(conn)
+}
+
+func TestRateLimitRequests(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	receiverID := config.NewIDWithName("otlp", "rate_limit")
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{Endpoint: "127.0.0.1:0", Transport: "tcp"},
+		RateLimit: &RateLimitSettings{
+			RequestsPerSecond: 0.001,
+			RequestsBurst:     2,
+		},
+	}
+	client := startTracesServer(t, gss,
+		WithObsReport(obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: receiverID, Transport: "grpc"})))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		_, err = client.Export(ctx, pdata.NewTraces(), grpc.WaitForReady(true))
+		require.NoError(t, err)
+	}
+	_, err = client.Export(ctx, pdata.NewTraces())
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	obsreporttest.CheckReceiverRefusedRequests(t, receiverID, "grpc", RefusedReasonRateLimited, 1)
+	peers := ThrottledPeers()["127.0.0.1:0"]
+	require.Len(t, peers, 1)
+	assert.Equal(t, "127.0.0.1", peers[0].Peer)
+	assert.EqualValues(t, 1, peers[0].RefusedRequests)
+}
+
+func TestRateLimitBytes(t *testing.T) {
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{Endpoint: "localhost:0", Transport: "tcp"},
+		RateLimit: &RateLimitSettings{
+			BytesPerSecond: 1,
+			BytesBurst:     10,
+		},
+	}
+	client := startTracesServer(t, gss)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Export(ctx, pdata.NewTraces(), grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName("a span larger than the burst")
+	_, err = client.Export(ctx, td)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimitKey(t *testing.T) {
+	settings := &RateLimitSettings{
+		Key:               RateLimitKeyAuthSubject,
+		RequestsPerSecond: 0.001,
+		RequestsBurst:     1,
+	}
+	rl := newRateLimiter(settings, defaultMaxRecvMsgSize, newThrottledPeers("localhost:0"), nil)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}})
+	aliceCtx := configauth.NewContextWithAuthData(ctx, &configauth.AuthData{Subject: "alice"})
+	bobCtx := configauth.NewContextWithAuthData(ctx, &configauth.AuthData{Subject: "bob"})
+
+	assert.NoError(t, rl.allow(aliceCtx, nil))
+	assert.Error(t, rl.allow(aliceCtx, nil))
+	// The other subjects, and the unauthenticated requests from the same address, have their own limits.
+	assert.NoError(t, rl.allow(bobCtx, nil))
+	assert.NoError(t, rl.allow(ctx, nil))
+	assert.Error(t, rl.allow(ctx, nil))
+
+	peers := rl.throttled.list()
+	require.Len(t, peers, 2)
+	assert.Equal(t, "10.0.0.1", peers[0].Peer)
+	assert.Equal(t, "alice", peers[1].Peer)
+}
+
+func TestRateLimitIdlePeers(t *testing.T) {
+	rl := newRateLimiter(&RateLimitSettings{RequestsPerSecond: 1}, defaultMaxRecvMsgSize, newThrottledPeers("localhost:0"), nil)
+	now := time.Now()
+	rl.lastCleanup = now
+	rl.limiters("10.0.0.1", now)
+	rl.limiters("10.0.0.2", now.Add(peerIdleTimeout))
+	assert.Len(t, rl.peers, 2)
+	rl.limiters("10.0.0.2", now.Add(peerIdleTimeout+time.Second))
+	assert.Len(t, rl.peers, 1)
+	assert.Contains(t, rl.peers, "10.0.0.2")
+}
+
+func TestRateLimitMaxPeers(t *testing.T) {
+	rl := newRateLimiter(&RateLimitSettings{RequestsPerSecond: 1}, defaultMaxRecvMsgSize, newThrottledPeers("localhost:0"), nil)
+	now := time.Now()
+	rl.lastCleanup = now
+	for i := 0; i < maxRateLimitedPeers; i++ {
+		rl.limiters(fmt.Sprintf("peer-%d", i), now.Add(time.Duration(i)))
+	}
+	assert.Len(t, rl.peers, maxRateLimitedPeers)
+
+	// The least recently seen client is discarded.
+	rl.limiters("peer-0", now.Add(maxRateLimitedPeers))
+	rl.limiters("10.0.0.1", now.Add(maxRateLimitedPeers+1))
+	assert.Len(t, rl.peers, maxRateLimitedPeers)
+	assert.Contains(t, rl.peers, "peer-0")
+	assert.NotContains(t, rl.peers, "peer-1")
+	assert.Contains(t, rl.peers, "10.0.0.1")
+}
+
+func TestThrottledPeersOfListeningServers(t *testing.T) {
+	gss := &GRPCServerSettings{
+		NetAddr:   confignet.NetAddr{Endpoint: "localhost:0", Transport: "tcp"},
+		RateLimit: &RateLimitSettings{RequestsPerSecond: 1},
+	}
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	_, err = gss.ToServerOption(nil)
+	require.NoError(t, err)
+
+	gss.throttled.recordRefusedRequest("10.0.0.1", time.Now())
+	peers := ThrottledPeers()["localhost:0"]
+	require.Len(t, peers, 1)
+	assert.Equal(t, "10.0.0.1", peers[0].Peer)
+
+	// The clients of another server on the same endpoint are tracked separately.
+	other := &GRPCServerSettings{NetAddr: gss.NetAddr, RateLimit: gss.RateLimit}
+	assert.NotSame(t, gss.throttledPeers(), other.throttledPeers())
+
+	require.NoError(t, ln.Close())
+	assert.NotContains(t, ThrottledPeers(), "localhost:0")
+}
+
+func TestMaxConnectionsPerPeer(t *testing.T) {
+	gss := &GRPCServerSettings{
+		NetAddr:               confignet.NetAddr{Endpoint: "localhost:0", Transport: "tcp"},
+		MaxConnectionsPerPeer: 1,
+	}
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	conn1, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn1.Close()
+	server1 := <-accepted
+
+	// The second connection of the same client is closed by the server.
+	conn2, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn2.Close()
+	require.NoError(t, conn2.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn2.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	// Closing the first connection allows a new one.
+	require.NoError(t, server1.Close())
+	conn3, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn3.Close()
+	select {
+	case server3 := <-accepted:
+		assert.NoError(t, server3.Close())
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "connection not accepted")
+	}
+}
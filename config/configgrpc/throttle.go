@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxThrottledPeers is the number of throttled clients tracked per server, the least recently
+// throttled ones are forgotten first.
+const maxThrottledPeers = 1000
+
+// ThrottledPeer describes a client of a gRPC server whose requests or connections were refused
+// because of the rate or connection limits.
+type ThrottledPeer struct {
+	// Peer is the client IP address, or its authenticated subject.
+	Peer string
+	// RefusedRequests is the number of requests refused by the rate limits.
+	RefusedRequests uint64
+	// RefusedConnections is the number of connections refused by max_connections_per_peer.
+	RefusedConnections uint64
+	// LastRefused is the time of the last refused request or connection.
+	LastRefused time.Time
+}
+
+// throttledPeers tracks the throttled clients of a server.
+type throttledPeers struct {
+	endpoint string
+
+	mu    sync.Mutex
+	peers map[string]*ThrottledPeer
+}
+
+var (
+	throttledPeersMu sync.Mutex
+	// listeningThrottledPeers holds the throttled clients of the servers whose listener is open.
+	listeningThrottledPeers = map[*throttledPeers]struct{}{}
+)
+
+func newThrottledPeers(endpoint string) *throttledPeers {
+	return &throttledPeers{endpoint: endpoint, peers: map[string]*ThrottledPeer{}}
+}
+
+// ThrottledPeers returns, by server endpoint, the clients whose requests or connections were
+// refused because of the limits of the listening servers, the most recently throttled first.
+func ThrottledPeers() map[string][]ThrottledPeer {
+	throttledPeersMu.Lock()
+	defer throttledPeersMu.Unlock()
+	res := make(map[string][]ThrottledPeer, len(listeningThrottledPeers))
+	for tp := range listeningThrottledPeers {
+		if peers := tp.list(); len(peers) > 0 {
+			res[tp.endpoint] = append(res[tp.endpoint], peers...)
+		}
+	}
+	return res
+}
+
+// register lists the throttled clients in ThrottledPeers, until unregister is called.
+func (tp *throttledPeers) register() {
+	throttledPeersMu.Lock()
+	defer throttledPeersMu.Unlock()
+	listeningThrottledPeers[tp] = struct{}{}
+}
+
+func (tp *throttledPeers) unregister() {
+	throttledPeersMu.Lock()
+	defer throttledPeersMu.Unlock()
+	delete(listeningThrottledPeers, tp)
+}
+
+func (tp *throttledPeers) list() []ThrottledPeer {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	peers := make([]ThrottledPeer, 0, len(tp.peers))
+	for _, p := range tp.peers {
+		peers = append(peers, *p)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].LastRefused.After(peers[j].LastRefused)
+	})
+	return peers
+}
+
+func (tp *throttledPeers) recordRefusedRequest(peer string, now time.Time) {
+	tp.record(peer, now, func(p *ThrottledPeer) { p.RefusedRequests++ })
+}
+
+func (tp *throttledPeers) recordRefusedConnection(peer string, now time.Time) {
+	tp.record(peer, now, func(p *ThrottledPeer) { p.RefusedConnections++ })
+}
+
+func (tp *throttledPeers) record(peer string, now time.Time, update func(p *ThrottledPeer)) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	p, ok := tp.peers[peer]
+	if !ok {
+		if len(tp.peers) >= maxThrottledPeers {
+			tp.evictOldest()
+		}
+		p = &ThrottledPeer{Peer: peer}
+		tp.peers[peer] = p
+	}
+	p.LastRefused = now
+	update(p)
+}
+
+func (tp *throttledPeers) evictOldest() {
+	var oldest *ThrottledPeer
+	for _, p := range tp.peers {
+		if oldest == nil || p.LastRefused.Before(oldest.LastRefused) {
+			oldest = p
+		}
+	}
+	if oldest != nil {
+		delete(tp.peers, oldest.Peer)
+	}
+}
+
+// peerLimitListener is a net.Listener closing the connections of the clients, by IP address,
+// that already have the maximum number of connections open. 0 means no limit.
+type peerLimitListener struct {
+	net.Listener
+	max       int
+	throttled *throttledPeers
+
+	mu    sync.Mutex
+	conns map[string]int
+}
+
+func newPeerLimitListener(ln net.Listener, max int, throttled *throttledPeers) *peerLimitListener {
+	throttled.register()
+	return &peerLimitListener{
+		Listener:  ln,
+		max:       max,
+		throttled: throttled,
+		conns:     map[string]int{},
+	}
+}
+
+// Accept waits for and returns the next connection of a client below its limit.
+func (l *peerLimitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		peer := addrHost(conn.RemoteAddr())
+		if l.acquire(peer) {
+			return &peerLimitConn{Conn: conn, release: func() { l.release(peer) }}, nil
+		}
+		l.throttled.recordRefusedConnection(peer, time.Now())
+		_ = conn.Close()
+	}
+}
+
+// Close closes the listener, and stops listing the clients throttled by the server.
+func (l *peerLimitListener) Close() error {
+	l.throttled.unregister()
+	return l.Listener.Close()
+}
+
+func (l *peerLimitListener) acquire(peer string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.max > 0 && l.conns[peer] >= l.max {
+		return false
+	}
+	l.conns[peer]++
+	return true
+}
+
+func (l *peerLimitListener) release(peer string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.conns[peer]--
+	if l.conns[peer] <= 0 {
+		delete(l.conns, peer)
+	}
+}
+
+// peerLimitConn is a net.Conn releasing its slot in the client limit when closed.
+type peerLimitConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+// Close closes the connection.
+func (c *peerLimitConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
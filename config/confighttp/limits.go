@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	// RefusedReasonRequestBodyTooLarge is the reason recorded for the requests refused
+	// because their body is larger than max_request_body_size.
+	RefusedReasonRequestBodyTooLarge = "request_body_too_large"
+	// RefusedReasonTooManyRequests is the reason recorded for the requests refused
+	// because max_concurrent_requests requests are already being handled.
+	RefusedReasonTooManyRequests = "too_many_requests"
+)
+
+// refuse responds to a request refused because of the server limits, and records it.
+func refuse(w http.ResponseWriter, r *http.Request, opts *toServerOptions, errMsg string, statusCode int, reason string) {
+	if opts.obsrecv != nil {
+		opts.obsrecv.RecordRefusedRequest(r.Context(), reason)
+	}
+	opts.errorHandler(w, r, errMsg, statusCode)
+}
+
+// maxRequestBodySizeInterceptor reads the whole request body, refusing the request if the body
+// is larger than maxSize, before passing it to next.
+func maxRequestBodySizeInterceptor(next http.Handler, maxSize int64, opts *toServerOptions) http.Handler {
+	errMsg := fmt.Sprintf("request body larger than the maximum of %d bytes", maxSize)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxSize {
+			refuse(w, r, opts, errMsg, http.StatusRequestEntityTooLarge, RefusedReasonRequestBodyTooLarge)
+			return
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSize+1))
+		if err != nil {
+			opts.errorHandler(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) > maxSize {
+			refuse(w, r, opts, errMsg, http.StatusRequestEntityTooLarge, RefusedReasonRequestBodyTooLarge)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// maxConcurrentRequestsInterceptor passes at most maxRequests concurrent requests to next,
+// refusing the other ones.
+func maxConcurrentRequestsInterceptor(next http.Handler, maxRequests int, opts *toServerOptions) http.Handler {
+	sem := make(chan struct{}, maxRequests)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+			next.ServeHTTP(w, r)
+		default:
+			refuse(w, r, opts, "too many concurrent requests", http.StatusServiceUnavailable, RefusedReasonTooManyRequests)
+		}
+	})
+}
+
+// defaultErrorHandler writes the error message in plain text.
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, errMsg string, statusCode int) {
+	http.Error(w, errMsg, statusCode)
+}
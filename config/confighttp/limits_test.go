@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+)
+
+func TestMaxRequestBodySize(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	receiverID := config.NewIDWithName("otlp", "body_size")
+	hss := &HTTPServerSettings{MaxRequestBodySize: 10}
+	s, err := hss.ToServer(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.EqualValues(t, len(body), r.ContentLength)
+		_, _ = w.Write(body)
+	}), WithObsReport(obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: receiverID, Transport: "http"})))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		body     []byte
+		encoding string
+		code     int
+	}{
+		{
+			name: "under the limit",
+			body: []byte("0123456789"),
+			code: http.StatusOK,
+		},
+		{
+			name: "over the limit",
+			body: []byte("0123456789a"),
+			code: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "compressed under the limit",
+			body:     gzipBytes(t, []byte("0123456789")),
+			encoding: "gzip",
+			code:     http.StatusOK,
+		},
+		{
+			name:     "compressed over the limit once decompressed",
+			body:     gzipBytes(t, bytes.Repeat([]byte("0"), 1000)),
+			encoding: "gzip",
+			code:     http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "snappy claiming a decoded size over the limit",
+			body:     snappyHeader(1000),
+			encoding: "snappy",
+			code:     http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "invalid compressed body",
+			body:     []byte("not gzip"),
+			encoding: "gzip",
+			code:     http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Encoding", tt.encoding)
+			rec := httptest.NewRecorder()
+			s.Handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+
+	// The body isn't read when the content length is already too large.
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789abcdef"))
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), "request body larger than the maximum of 10 bytes")
+
+	obsreporttest.CheckReceiverRefusedRequests(t, receiverID, "http", RefusedReasonRequestBodyTooLarge, 4)
+}
+
+// snappyHeader returns a snappy block header claiming decodedSize bytes, without the data.
+func snappyHeader(decodedSize uint64) []byte {
+	header := make([]byte, binary.MaxVarintLen64)
+	return header[:binary.PutUvarint(header, decodedSize)]
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	receiverID := config.NewIDWithName("otlp", "concurrency")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	hss := &HTTPServerSettings{MaxConcurrentRequests: 2}
+	var errorHandlerCalled bool
+	s, err := hss.ToServer(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}), WithObsReport(obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: receiverID, Transport: "http"})),
+		WithErrorHandler(func(w http.ResponseWriter, r *http.Request, errMsg string, statusCode int) {
+			errorHandlerCalled = true
+			http.Error(w, errMsg, statusCode)
+		}))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}()
+		<-started
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.True(t, errorHandlerCalled)
+
+	close(release)
+	wg.Wait()
+
+	// Requests are accepted again once the previous ones completed.
+	go func() { <-started }()
+	rec = httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	obsreporttest.CheckReceiverRefusedRequests(t, receiverID, "http", RefusedReasonTooManyRequests, 1)
+}
+
+func TestServerTimeouts(t *testing.T) {
+	hss := &HTTPServerSettings{
+		ReadHeaderTimeout: 1 * time.Second,
+		ReadTimeout:       2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+	}
+	s, err := hss.ToServer(nil, http.NotFoundHandler())
+	require.NoError(t, err)
+	assert.Equal(t, 1*time.Second, s.ReadHeaderTimeout)
+	assert.Equal(t, 2*time.Second, s.ReadTimeout)
+	assert.Equal(t, 3*time.Second, s.WriteTimeout)
+	assert.Equal(t, 4*time.Second, s.IdleTimeout)
+}
+
+func TestHTTPServerSettingsValidateLimits(t *testing.T) {
+	assert.Error(t, (&HTTPServerSettings{MaxRequestBodySize: -1}).Validate())
+	assert.Error(t, (&HTTPServerSettings{MaxConcurrentRequests: -1}).Validate())
+	assert.Error(t, (&HTTPServerSettings{ReadHeaderTimeout: -time.Second}).Validate())
+	assert.NoError(t, (&HTTPServerSettings{MaxRequestBodySize: 1, MaxConcurrentRequests: 1, IdleTimeout: time.Second}).Validate())
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
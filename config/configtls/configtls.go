@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"time"
 )
 
 // TLSSetting exposes the common client and server TLS configurations.
@@ -34,6 +35,23 @@ type TLSSetting struct {
 	CertFile string `mapstructure:"cert_file"`
 	// Path to the TLS key to use for TLS required connections. (optional)
 	KeyFile string `mapstructure:"key_file"`
+	// ReloadInterval specifies how often the certificate, key and CA files are checked for changes.
+	// Changed files are loaded again, without restarting the component, and used for the new connections.
+	// If zero, the files are only loaded once. (optional)
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+	// MinVersion is the minimum TLS version accepted, one of "1.0", "1.1", "1.2" or "1.3".
+	// If empty, the crypto/tls default is used. (optional)
+	MinVersion string `mapstructure:"min_version"`
+	// MaxVersion is the maximum TLS version accepted, one of "1.0", "1.1", "1.2" or "1.3".
+	// If empty, the crypto/tls default is used. (optional)
+	MaxVersion string `mapstructure:"max_version"`
+	// CipherSuites is the list of cipher suites allowed for TLS 1.0 to 1.2, using their IANA names,
+	// e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256". TLS 1.3 cipher suites are not configurable.
+	// If empty, the crypto/tls defaults are used. (optional)
+	CipherSuites []string `mapstructure:"cipher_suites"`
+	// CurvePreferences is the list of elliptic curves used in ECDHE handshakes, in preference order,
+	// any of "X25519", "P256", "P384" and "P521". If empty, the crypto/tls defaults are used. (optional)
+	CurvePreferences []string `mapstructure:"curve_preferences"`
 }
 
 // TLSClientSetting contains TLS configurations that are specific to client
@@ -73,18 +91,80 @@ type TLSServerSetting struct {
 	// This sets the ClientCAs and ClientAuth to RequireAndVerifyClientCert in the TLSConfig. Please refer to
 	// https://godoc.org/crypto/tls#Config for more information. (optional)
 	ClientCAFile string `mapstructure:"client_ca_file"`
+
+	// ClientAuth is the policy for the client certificates, one of "none", "request", "require",
+	// "verify_if_given" and "require_and_verify". The verifying policies require ClientCAFile.
+	// If empty, it is "require_and_verify" when ClientCAFile is set, "none" otherwise. (optional)
+	ClientAuth string `mapstructure:"client_auth"`
+}
+
+// Validate checks that the TLS versions, cipher suites and curves are supported.
+func (c TLSSetting) Validate() error {
+	minVersion, err := convertVersion(c.MinVersion)
+	if err != nil {
+		return fmt.Errorf("invalid min_version: %w", err)
+	}
+	maxVersion, err := convertVersion(c.MaxVersion)
+	if err != nil {
+		return fmt.Errorf("invalid max_version: %w", err)
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return fmt.Errorf("min_version %q is greater than max_version %q", c.MinVersion, c.MaxVersion)
+	}
+	if _, err = convertCipherSuites(c.CipherSuites); err != nil {
+		return fmt.Errorf("invalid cipher_suites: %w", err)
+	}
+	if _, err = convertCurvePreferences(c.CurvePreferences); err != nil {
+		return fmt.Errorf("invalid curve_preferences: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the common settings and the client authentication policy.
+func (c TLSServerSetting) Validate() error {
+	if err := c.TLSSetting.Validate(); err != nil {
+		return err
+	}
+	_, err := c.clientAuth()
+	return err
+}
+
+// clientAuth returns the client authentication policy, with the default applied.
+func (c TLSServerSetting) clientAuth() (tls.ClientAuthType, error) {
+	if c.ClientAuth == "" {
+		if c.ClientCAFile != "" {
+			return tls.RequireAndVerifyClientCert, nil
+		}
+		return tls.NoClientCert, nil
+	}
+	clientAuth, err := convertClientAuth(c.ClientAuth)
+	if err != nil {
+		return tls.NoClientCert, err
+	}
+	if clientAuth >= tls.VerifyClientCertIfGiven && c.ClientCAFile == "" {
+		return tls.NoClientCert, fmt.Errorf("client_auth %q requires a client_ca_file", c.ClientAuth)
+	}
+	return clientAuth, nil
 }
 
 // LoadTLSConfig loads TLS certificates and returns a tls.Config.
 // This will set the RootCAs and Certificates of a tls.Config.
 func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	minVersion, _ := convertVersion(c.MinVersion)
+	maxVersion, _ := convertVersion(c.MaxVersion)
+	cipherSuites, _ := convertCipherSuites(c.CipherSuites)
+	curvePreferences, _ := convertCurvePreferences(c.CurvePreferences)
+
 	// There is no need to load the System Certs for RootCAs because
 	// if the value is nil, it will default to checking against th System Certs.
 	var err error
 	var certPool *x509.CertPool
 	if len(c.CAFile) != 0 {
 		// Set up user specified truststore.
-		certPool, err = c.loadCert(c.CAFile)
+		certPool, err = loadCertPool(c.CAFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load CA CertPool: %w", err)
 		}
@@ -94,22 +174,35 @@ func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
 		return nil, fmt.Errorf("for auth via TLS, either both certificate and key must be supplied, or neither")
 	}
 
-	var certificates []tls.Certificate
+	tlsCfg := &tls.Config{
+		RootCAs:          certPool,
+		MinVersion:       minVersion,
+		MaxVersion:       maxVersion,
+		CipherSuites:     cipherSuites,
+		CurvePreferences: curvePreferences,
+	}
 	if c.CertFile != "" && c.KeyFile != "" {
-		tlsCert, err := tls.LoadX509KeyPair(filepath.Clean(c.CertFile), filepath.Clean(c.KeyFile))
-		if err != nil {
-			return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
+		if c.ReloadInterval > 0 {
+			kp, err := newKeyPairReloader(c.CertFile, c.KeyFile, c.ReloadInterval)
+			if err != nil {
+				return nil, err
+			}
+			tlsCfg.GetCertificate = kp.GetCertificate
+			tlsCfg.GetClientCertificate = kp.GetClientCertificate
+		} else {
+			tlsCert, err := tls.LoadX509KeyPair(filepath.Clean(c.CertFile), filepath.Clean(c.KeyFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
+			}
+			recordCertExpiry(c.CertFile, &tlsCert)
+			tlsCfg.Certificates = []tls.Certificate{tlsCert}
 		}
-		certificates = append(certificates, tlsCert)
 	}
 
-	return &tls.Config{
-		RootCAs:      certPool,
-		Certificates: certificates,
-	}, nil
+	return tlsCfg, nil
 }
 
-func (c TLSSetting) loadCert(caPath string) (*x509.CertPool, error) {
+func loadCertPool(caPath string) (*x509.CertPool, error) {
 	caPEM, err := ioutil.ReadFile(filepath.Clean(caPath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load CA %s: %w", caPath, err)
@@ -124,6 +217,13 @@ func (c TLSSetting) loadCert(caPath string) (*x509.CertPool, error) {
 
 // LoadTLSConfig loads the TLS configuration.
 func (c TLSClientSetting) LoadTLSConfig() (*tls.Config, error) {
+	return c.LoadTLSConfigForHost("")
+}
+
+// LoadTLSConfigForHost loads the TLS configuration of a client connecting to host. When the CA is reloaded, and
+// no ServerName is set, the server certificate is verified against host if the connection has no server name,
+// e.g. when host is an IP address.
+func (c TLSClientSetting) LoadTLSConfigForHost(host string) (*tls.Config, error) {
 	if c.Insecure && c.CAFile == "" {
 		return nil, nil
 	}
@@ -134,6 +234,20 @@ func (c TLSClientSetting) LoadTLSConfig() (*tls.Config, error) {
 	}
 	tlsCfg.ServerName = c.ServerName
 	tlsCfg.InsecureSkipVerify = c.InsecureSkipVerify
+	if c.ReloadInterval > 0 && c.CAFile != "" && !c.InsecureSkipVerify {
+		ca, err := newCAReloader(c.CAFile, c.ReloadInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: failed to load CA CertPool: %w", err)
+		}
+		// The standard verification is replaced by one using the reloaded CA pool.
+		tlsCfg.RootCAs = nil
+		tlsCfg.InsecureSkipVerify = true
+		serverName := c.ServerName
+		if serverName == "" {
+			serverName = host
+		}
+		tlsCfg.VerifyConnection = ca.verifyServerConnection(x509.ExtKeyUsageServerAuth, true, true, serverName)
+	}
 	return tlsCfg, nil
 }
 
@@ -143,13 +257,31 @@ func (c TLSServerSetting) LoadTLSConfig() (*tls.Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
-	if c.ClientCAFile != "" {
-		certPool, err := c.loadCert(c.ClientCAFile)
+	clientAuth, err := c.clientAuth()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS config: %w", err)
+	}
+	tlsCfg.ClientAuth = clientAuth
+	if c.ClientCAFile != "" && c.ReloadInterval > 0 {
+		ca, err := newCAReloader(c.ClientCAFile, c.ReloadInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: failed to load client CA CertPool: %w", err)
+		}
+		// The standard verification is replaced by one using the reloaded CA pool.
+		switch clientAuth {
+		case tls.VerifyClientCertIfGiven:
+			tlsCfg.ClientAuth = tls.RequestClientCert
+			tlsCfg.VerifyConnection = ca.verifyConnection(x509.ExtKeyUsageClientAuth, false, false)
+		case tls.RequireAndVerifyClientCert:
+			tlsCfg.ClientAuth = tls.RequireAnyClientCert
+			tlsCfg.VerifyConnection = ca.verifyConnection(x509.ExtKeyUsageClientAuth, false, true)
+		}
+	} else if c.ClientCAFile != "" {
+		certPool, err := loadCertPool(c.ClientCAFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load TLS config: failed to load client CA CertPool: %w", err)
 		}
 		tlsCfg.ClientCAs = certPool
-		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 	return tlsCfg, nil
 }
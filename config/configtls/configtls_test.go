@@ -15,7 +15,11 @@
 package configtls
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -107,6 +111,50 @@ func TestOptionsToConfig(t *testing.T) {
 				CAFile: "testdata/testCA.pem",
 			},
 		},
+		{
+			name: "should load valid TLS version, cipher suites and curves",
+			options: TLSSetting{
+				MinVersion:       "1.2",
+				MaxVersion:       "1.3",
+				CipherSuites:     []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+				CurvePreferences: []string{"X25519", "P256"},
+			},
+		},
+		{
+			name:        "should fail with invalid min version",
+			options:     TLSSetting{MinVersion: "1.4"},
+			expectError: "invalid min_version",
+		},
+		{
+			name:        "should fail with invalid max version",
+			options:     TLSSetting{MaxVersion: "TLS1.2"},
+			expectError: "invalid max_version",
+		},
+		{
+			name:        "should fail with min version greater than max version",
+			options:     TLSSetting{MinVersion: "1.3", MaxVersion: "1.2"},
+			expectError: "is greater than max_version",
+		},
+		{
+			name:        "should fail with unknown cipher suite",
+			options:     TLSSetting{CipherSuites: []string{"TLS_UNKNOWN"}},
+			expectError: "unsupported cipher suite",
+		},
+		{
+			name:        "should fail with insecure cipher suite",
+			options:     TLSSetting{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			expectError: "unsupported cipher suite",
+		},
+		{
+			name:        "should fail with TLS 1.3 cipher suite",
+			options:     TLSSetting{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}},
+			expectError: "TLS 1.3 cipher suites are always enabled",
+		},
+		{
+			name:        "should fail with unknown curve",
+			options:     TLSSetting{CurvePreferences: []string{"P224"}},
+			expectError: "unsupported curve",
+		},
 	}
 
 	for _, test := range tests {
@@ -179,3 +227,146 @@ func TestLoadTLSServerConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tlsCfg)
 }
+
+func TestLoadTLSConfigOptions(t *testing.T) {
+	tlsCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{
+			MinVersion:       "1.1",
+			MaxVersion:       "1.2",
+			CipherSuites:     []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+			CurvePreferences: []string{"P384", "X25519"},
+		},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.EqualValues(t, tls.VersionTLS11, tlsCfg.MinVersion)
+	assert.EqualValues(t, tls.VersionTLS12, tlsCfg.MaxVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, tlsCfg.CipherSuites)
+	assert.Equal(t, []tls.CurveID{tls.CurveP384, tls.X25519}, tlsCfg.CurvePreferences)
+}
+
+func TestTLSServerSettingClientAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		setting     TLSServerSetting
+		expected    tls.ClientAuthType
+		expectError string
+	}{
+		{
+			name:     "default without client CA",
+			setting:  TLSServerSetting{},
+			expected: tls.NoClientCert,
+		},
+		{
+			name:     "default with client CA",
+			setting:  TLSServerSetting{ClientCAFile: "testdata/testCA.pem"},
+			expected: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:     "none",
+			setting:  TLSServerSetting{ClientAuth: ClientAuthNone, ClientCAFile: "testdata/testCA.pem"},
+			expected: tls.NoClientCert,
+		},
+		{
+			name:     "request",
+			setting:  TLSServerSetting{ClientAuth: ClientAuthRequest},
+			expected: tls.RequestClientCert,
+		},
+		{
+			name:     "require",
+			setting:  TLSServerSetting{ClientAuth: ClientAuthRequire},
+			expected: tls.RequireAnyClientCert,
+		},
+		{
+			name:     "verify if given",
+			setting:  TLSServerSetting{ClientAuth: ClientAuthVerifyIfGiven, ClientCAFile: "testdata/testCA.pem"},
+			expected: tls.VerifyClientCertIfGiven,
+		},
+		{
+			name:        "verify if given without client CA",
+			setting:     TLSServerSetting{ClientAuth: ClientAuthVerifyIfGiven},
+			expectError: "requires a client_ca_file",
+		},
+		{
+			name:        "require and verify without client CA",
+			setting:     TLSServerSetting{ClientAuth: ClientAuthRequireAndVerify},
+			expectError: "requires a client_ca_file",
+		},
+		{
+			name:        "unknown",
+			setting:     TLSServerSetting{ClientAuth: "always"},
+			expectError: "unsupported client_auth",
+		},
+		{
+			name:        "invalid common settings",
+			setting:     TLSServerSetting{TLSSetting: TLSSetting{MinVersion: "2.0"}},
+			expectError: "invalid min_version",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.setting.Validate()
+			tlsCfg, loadErr := test.setting.LoadTLSConfig()
+			if test.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectError)
+				assert.Error(t, loadErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, loadErr)
+			assert.Equal(t, test.expected, tlsCfg.ClientAuth)
+		})
+	}
+}
+
+func TestHandshakeOptions(t *testing.T) {
+	dir := t.TempDir()
+	serverCA, clientCA := newTestCA(t, "server"), newTestCA(t, "client")
+	certFile, keyFile, clientCAFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "client-ca.pem")
+	certPEM, keyPEM := serverCA.issue(t, 1, time.Now().Add(time.Hour))
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	writeFile(t, clientCAFile, clientCA.pem)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCA.cert)
+	clientCertPEM, clientKeyPEM := clientCA.issue(t, 2, time.Now().Add(time.Hour))
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+	untrustedCertPEM, untrustedKeyPEM := newTestCA(t, "untrusted").issue(t, 3, time.Now().Add(time.Hour))
+	untrustedCert, err := tls.X509KeyPair(untrustedCertPEM, untrustedKeyPEM)
+	require.NoError(t, err)
+
+	for _, reloadInterval := range []time.Duration{0, testReloadInterval} {
+		serverCfg, err := TLSServerSetting{
+			TLSSetting: TLSSetting{
+				CertFile:       certFile,
+				KeyFile:        keyFile,
+				MinVersion:     "1.3",
+				ReloadInterval: reloadInterval,
+			},
+			ClientCAFile: clientCAFile,
+			ClientAuth:   ClientAuthVerifyIfGiven,
+		}.LoadTLSConfig()
+		require.NoError(t, err)
+
+		clientCfg := func(maxVersion uint16, cert *tls.Certificate) *tls.Config {
+			return &tls.Config{
+				RootCAs:    roots,
+				ServerName: "localhost",
+				MaxVersion: maxVersion,
+				// Always send the certificate, even if not issued by a CA accepted by the server.
+				GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					if cert == nil {
+						return &tls.Certificate{}, nil
+					}
+					return cert, nil
+				},
+			}
+		}
+		assert.NoError(t, handshake(t, serverCfg, clientCfg(0, nil)))
+		assert.NoError(t, handshake(t, serverCfg, clientCfg(0, &clientCert)))
+		assert.Error(t, handshake(t, serverCfg, clientCfg(0, &untrustedCert)))
+		assert.Error(t, handshake(t, serverCfg, clientCfg(tls.VersionTLS12, nil)))
+	}
+}
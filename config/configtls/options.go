@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+const (
+	// ClientAuthNone doesn't request a client certificate.
+	ClientAuthNone = "none"
+	// ClientAuthRequest requests a client certificate, without requiring nor verifying it.
+	ClientAuthRequest = "request"
+	// ClientAuthRequire requires a client certificate, without verifying it.
+	ClientAuthRequire = "require"
+	// ClientAuthVerifyIfGiven requests a client certificate, and verifies it if one is given.
+	ClientAuthVerifyIfGiven = "verify_if_given"
+	// ClientAuthRequireAndVerify requires a client certificate and verifies it.
+	ClientAuthRequireAndVerify = "require_and_verify"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var curves = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	ClientAuthNone:             tls.NoClientCert,
+	ClientAuthRequest:          tls.RequestClientCert,
+	ClientAuthRequire:          tls.RequireAnyClientCert,
+	ClientAuthVerifyIfGiven:    tls.VerifyClientCertIfGiven,
+	ClientAuthRequireAndVerify: tls.RequireAndVerifyClientCert,
+}
+
+// convertVersion returns the TLS version of the given name, or 0, the crypto/tls default, if empty.
+func convertVersion(v string) (uint16, error) {
+	if v == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[v]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version %q, must be one of \"1.0\", \"1.1\", \"1.2\" or \"1.3\"", v)
+	}
+	return version, nil
+}
+
+// convertCipherSuites returns the IDs of the given cipher suites. Only the cipher suites considered
+// secure by crypto/tls are supported. TLS 1.3 cipher suites are not configurable, and rejected.
+func convertCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	supported := map[string]*tls.CipherSuite{}
+	for _, cs := range tls.CipherSuites() {
+		supported[cs.Name] = cs
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		cs, ok := supported[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported cipher suite %q", name)
+		}
+		if len(cs.SupportedVersions) == 1 && cs.SupportedVersions[0] == tls.VersionTLS13 {
+			return nil, fmt.Errorf("cipher suite %q cannot be configured, TLS 1.3 cipher suites are always enabled", name)
+		}
+		ids = append(ids, cs.ID)
+	}
+	return ids, nil
+}
+
+// convertCurvePreferences returns the IDs of the given elliptic curves.
+func convertCurvePreferences(names []string) ([]tls.CurveID, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	ids := make([]tls.CurveID, 0, len(names))
+	for _, name := range names {
+		id, ok := curves[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q, must be one of \"X25519\", \"P256\", \"P384\" or \"P521\"", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// convertClientAuth returns the client authentication policy of the given name.
+func convertClientAuth(name string) (tls.ClientAuthType, error) {
+	clientAuth, ok := clientAuthTypes[name]
+	if !ok {
+		return tls.NoClientCert, fmt.Errorf("unsupported client_auth %q, must be one of %q, %q, %q, %q or %q", name,
+			ClientAuthNone, ClientAuthRequest, ClientAuthRequire, ClientAuthVerifyIfGiven, ClientAuthRequireAndVerify)
+	}
+	return clientAuth, nil
+}
@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.opencensus.io/metric"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricproducer"
+)
+
+var (
+	r = metric.NewRegistry()
+
+	certExpiryGauge, _ = r.AddInt64Gauge(
+		"tls/certificate_expiry_time",
+		metric.WithDescription("Expiry time of the loaded TLS certificate, in seconds since the Unix epoch"),
+		metric.WithLabelKeys("cert_file"),
+		metric.WithUnit(metricdata.Unit("s")))
+)
+
+func init() {
+	metricproducer.GlobalManager().AddProducer(r)
+}
+
+// recordCertExpiry records the expiry time of the leaf certificate of the given key pair.
+func recordCertExpiry(certFile string, cert *tls.Certificate) {
+	if len(cert.Certificate) == 0 {
+		return
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return
+	}
+	if entry, err := certExpiryGauge.GetEntry(metricdata.NewLabelValue(certFile)); err == nil {
+		entry.Set(leaf.NotAfter.Unix())
+	}
+}
+
+// fileReloader holds the content loaded from a set of files, and loads it again when any of the
+// files changed. The files are only checked on use, at most once per interval, so that no
+// background goroutine is needed.
+type fileReloader struct {
+	files    []string
+	interval time.Duration
+	load     func() error
+
+	mu        sync.Mutex
+	lastCheck time.Time
+	modTimes  []time.Time
+}
+
+func newFileReloader(interval time.Duration, load func() error, files ...string) (*fileReloader, error) {
+	fr := &fileReloader{
+		files:    files,
+		interval: interval,
+		load:     load,
+		modTimes: make([]time.Time, len(files)),
+	}
+	if err := fr.load(); err != nil {
+		return nil, err
+	}
+	fr.lastCheck = time.Now()
+	fr.modTimes, _ = fr.currentModTimes()
+	return fr, nil
+}
+
+// maybeReload loads the files again if the interval elapsed since the last check and any of them changed.
+// Failures to load the files keep the previous content, and are retried at the next check.
+func (fr *fileReloader) maybeReload() {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(fr.lastCheck) < fr.interval {
+		return
+	}
+	fr.lastCheck = now
+
+	modTimes, err := fr.currentModTimes()
+	if err != nil {
+		return
+	}
+	changed := false
+	for i := range modTimes {
+		if !modTimes[i].Equal(fr.modTimes[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	if err = fr.load(); err == nil {
+		fr.modTimes = modTimes
+	}
+}
+
+func (fr *fileReloader) currentModTimes() ([]time.Time, error) {
+	modTimes := make([]time.Time, len(fr.files))
+	for i, f := range fr.files {
+		fi, err := os.Stat(filepath.Clean(f))
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = fi.ModTime()
+	}
+	return modTimes, nil
+}
+
+// keyPairReloader provides the certificate of a key pair, reloading it when the files change.
+type keyPairReloader struct {
+	*fileReloader
+	cert *tls.Certificate
+}
+
+func newKeyPairReloader(certFile, keyFile string, interval time.Duration) (*keyPairReloader, error) {
+	kp := &keyPairReloader{}
+	fr, err := newFileReloader(interval, func() error {
+		cert, err := tls.LoadX509KeyPair(filepath.Clean(certFile), filepath.Clean(keyFile))
+		if err != nil {
+			return fmt.Errorf("failed to load TLS cert and key: %w", err)
+		}
+		recordCertExpiry(certFile, &cert)
+		kp.cert = &cert
+		return nil
+	}, certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	kp.fileReloader = fr
+	return kp, nil
+}
+
+func (kp *keyPairReloader) certificate() *tls.Certificate {
+	kp.maybeReload()
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	return kp.cert
+}
+
+// GetCertificate can be used as tls.Config.GetCertificate by servers.
+func (kp *keyPairReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return kp.certificate(), nil
+}
+
+// GetClientCertificate can be used as tls.Config.GetClientCertificate by clients.
+func (kp *keyPairReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return kp.certificate(), nil
+}
+
+// caReloader provides a CA cert pool, reloading it when the CA file changes.
+type caReloader struct {
+	*fileReloader
+	pool *x509.CertPool
+}
+
+func newCAReloader(caFile string, interval time.Duration) (*caReloader, error) {
+	ca := &caReloader{}
+	fr, err := newFileReloader(interval, func() error {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return err
+		}
+		ca.pool = pool
+		return nil
+	}, caFile)
+	if err != nil {
+		return nil, err
+	}
+	ca.fileReloader = fr
+	return ca, nil
+}
+
+func (ca *caReloader) certPool() *x509.CertPool {
+	ca.maybeReload()
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	return ca.pool
+}
+
+// verifyConnection returns a function, suitable for tls.Config.VerifyConnection, verifying the peer
+// certificate chain against the current pool. When verifying servers, the server name is also verified.
+// If the peer certificate isn't required, connections without one are accepted.
+// It must be used with the standard verification disabled, which would use a pool loaded only once.
+func (ca *caReloader) verifyConnection(keyUsage x509.ExtKeyUsage, verifyServerName, requireCert bool) func(tls.ConnectionState) error {
+	return ca.verifyServerConnection(keyUsage, verifyServerName, requireCert, "")
+}
+
+// verifyServerConnection is verifyConnection verifying the server certificate against serverName when the
+// client sent no server name, as for the IP addresses which are not sent with SNI. The connections are refused if
+// there is no server name to verify the certificate against.
+func (ca *caReloader) verifyServerConnection(keyUsage x509.ExtKeyUsage, verifyServerName, requireCert bool, serverName string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			if !requireCert {
+				return nil
+			}
+			return errors.New("tls: no peer certificate provided")
+		}
+		opts := x509.VerifyOptions{
+			Roots:         ca.certPool(),
+			Intermediates: x509.NewCertPool(),
+			KeyUsages:     []x509.ExtKeyUsage{keyUsage},
+		}
+		if verifyServerName {
+			opts.DNSName = cs.ServerName
+			if opts.DNSName == "" {
+				opts.DNSName = serverName
+			}
+			if opts.DNSName == "" {
+				return errors.New("tls: no server name to verify the server certificate against, server_name_override must be set")
+			}
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/metric/metricproducer"
+)
+
+const testReloadInterval = 10 * time.Millisecond
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM encoded certificate and key of a new leaf certificate for "localhost".
+func (ca *testCA) issue(t *testing.T, serial int64, notAfter time.Time) ([]byte, []byte) {
+	return ca.issueFor(t, serial, notAfter, "localhost", nil)
+}
+
+// issueFor returns the PEM encoded certificate and key of a new leaf certificate for the DNS name and IP addresses.
+func (ca *testCA) issueFor(t *testing.T, serial int64, notAfter time.Time, dnsName string, ips []net.IP) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		IPAddresses:  ips,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile writes the file, and moves its modification time forward, so that the change is
+// detected even on file systems with a coarse time resolution.
+func writeFile(t *testing.T, path string, content []byte) {
+	var modTime time.Time
+	if fi, err := os.Stat(path); err == nil {
+		modTime = fi.ModTime()
+	}
+	require.NoError(t, ioutil.WriteFile(path, content, 0600))
+	if !modTime.IsZero() {
+		require.NoError(t, os.Chtimes(path, modTime.Add(time.Second), modTime.Add(time.Second)))
+	}
+}
+
+// handshake runs a TLS handshake between the given configurations over a loopback connection.
+func handshake(t *testing.T, serverCfg, clientCfg *tls.Config) error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverConn := tls.Server(conn, serverCfg)
+		err = serverConn.Handshake()
+		if err == nil {
+			// With TLS 1.3, client certificates are verified after the client handshake
+			// completed, make sure the client observes the result.
+			_, err = serverConn.Write([]byte{0})
+		}
+		serverErr <- err
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	clientConn := tls.Client(conn, clientCfg)
+	clientErr := clientConn.Handshake()
+	if clientErr == nil {
+		_, clientErr = clientConn.Read(make([]byte, 1))
+	}
+	if err := <-serverErr; err != nil {
+		return err
+	}
+	return clientErr
+}
+
+func TestKeyPairReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "ca")
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certPEM, keyPEM := ca.issue(t, 1, time.Now().Add(time.Hour))
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	cfg, err := TLSServerSetting{
+		TLSSetting: TLSSetting{CertFile: certFile, KeyFile: keyFile, ReloadInterval: testReloadInterval},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	require.Empty(t, cfg.Certificates)
+
+	serial := func() int64 {
+		cert, err := cfg.GetCertificate(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.SerialNumber.Int64()
+	}
+	assert.EqualValues(t, 1, serial())
+
+	certPEM, keyPEM = ca.issue(t, 2, time.Now().Add(time.Hour))
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	assert.Eventually(t, func() bool { return serial() == 2 }, time.Second, testReloadInterval)
+
+	// Invalid content keeps the previous certificate.
+	writeFile(t, keyFile, []byte("invalid"))
+	time.Sleep(2 * testReloadInterval)
+	assert.EqualValues(t, 2, serial())
+
+	// The client hook uses the same certificate.
+	cert, err := cfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	assert.NotNil(t, cert)
+}
+
+func TestClientCAReload(t *testing.T) {
+	dir := t.TempDir()
+	ca1, ca2 := newTestCA(t, "ca1"), newTestCA(t, "ca2")
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, ca1.pem)
+
+	serverCertPEM, serverKeyPEM := ca2.issue(t, 1, time.Now().Add(time.Hour))
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	require.NoError(t, err)
+	serverCfg := &tls.Config{Certificates: []tls.Certificate{serverCert}}
+
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: caFile, ReloadInterval: testReloadInterval},
+		ServerName: "localhost",
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	// The server certificate isn't signed by the trusted CA.
+	assert.Error(t, handshake(t, serverCfg, clientCfg.Clone()))
+
+	writeFile(t, caFile, ca2.pem)
+	assert.Eventually(t, func() bool {
+		return handshake(t, serverCfg, clientCfg.Clone()) == nil
+	}, time.Second, testReloadInterval)
+
+	// The server name is still verified.
+	clientCfg.ServerName = "example.com"
+	assert.Error(t, handshake(t, serverCfg, clientCfg.Clone()))
+}
+
+func TestClientCAReloadIPEndpoint(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "ca")
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, ca.pem)
+
+	newServerCfg := func(ips []net.IP) *tls.Config {
+		certPEM, keyPEM := ca.issueFor(t, 1, time.Now().Add(time.Hour), "other.example", ips)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		require.NoError(t, err)
+		return &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	setting := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: caFile, ReloadInterval: testReloadInterval},
+	}
+
+	// No server name is sent for the IP addresses, the certificate is verified against the dialed host.
+	clientCfg, err := setting.LoadTLSConfigForHost("127.0.0.1")
+	require.NoError(t, err)
+	assert.Error(t, handshake(t, newServerCfg(nil), clientCfg.Clone()))
+	assert.NoError(t, handshake(t, newServerCfg([]net.IP{net.IPv4(127, 0, 0, 1)}), clientCfg.Clone()))
+
+	// Without dialed host, the connections without server name are refused.
+	clientCfg, err = setting.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.Error(t, handshake(t, newServerCfg([]net.IP{net.IPv4(127, 0, 0, 1)}), clientCfg.Clone()))
+
+	// The configured server name takes precedence over the dialed host.
+	setting.ServerName = "127.0.0.2"
+	clientCfg, err = setting.LoadTLSConfigForHost("127.0.0.1")
+	require.NoError(t, err)
+	assert.Error(t, handshake(t, newServerCfg([]net.IP{net.IPv4(127, 0, 0, 1)}), clientCfg.Clone()))
+	assert.NoError(t, handshake(t, newServerCfg([]net.IP{net.IPv4(127, 0, 0, 2)}), clientCfg.Clone()))
+}
+
+func TestServerClientCAReload(t *testing.T) {
+	dir := t.TempDir()
+	serverCA, ca1, ca2 := newTestCA(t, "server"), newTestCA(t, "ca1"), newTestCA(t, "ca2")
+	certFile, keyFile, clientCAFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "client-ca.pem")
+	certPEM, keyPEM := serverCA.issue(t, 1, time.Now().Add(time.Hour))
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	writeFile(t, clientCAFile, ca1.pem)
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting:   TLSSetting{CertFile: certFile, KeyFile: keyFile, ReloadInterval: testReloadInterval},
+		ClientCAFile: clientCAFile,
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	clientCertPEM, clientKeyPEM := ca2.issue(t, 2, time.Now().Add(time.Hour))
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCA.cert)
+	clientCfg := &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{clientCert}}
+
+	// The client certificate isn't signed by the trusted client CA.
+	assert.Error(t, handshake(t, serverCfg, clientCfg.Clone()))
+
+	writeFile(t, clientCAFile, ca2.pem)
+	assert.Eventually(t, func() bool {
+		return handshake(t, serverCfg, clientCfg.Clone()) == nil
+	}, time.Second, testReloadInterval)
+
+	// A client certificate is still required.
+	clientCfg.Certificates = nil
+	assert.Error(t, handshake(t, serverCfg, clientCfg.Clone()))
+}
+
+func TestReloadInvalidFiles(t *testing.T) {
+	_, err := TLSServerSetting{
+		TLSSetting: TLSSetting{
+			CertFile:       filepath.Join("testdata", "test-cert.pem"),
+			KeyFile:        filepath.Join("testdata", "missing.pem"),
+			ReloadInterval: testReloadInterval,
+		},
+	}.LoadTLSConfig()
+	assert.Error(t, err)
+
+	_, err = TLSServerSetting{
+		TLSSetting:   TLSSetting{ReloadInterval: testReloadInterval},
+		ClientCAFile: filepath.Join("testdata", "testCA-bad.txt"),
+	}.LoadTLSConfig()
+	assert.Error(t, err)
+}
+
+func TestCertExpiryMetric(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "ca")
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	certPEM, keyPEM := ca.issue(t, 1, notAfter)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	_, err := TLSServerSetting{TLSSetting: TLSSetting{CertFile: certFile, KeyFile: keyFile}}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	for _, producer := range metricproducer.GlobalManager().GetAll() {
+		for _, m := range producer.Read() {
+			if m.Descriptor.Name != "tls/certificate_expiry_time" {
+				continue
+			}
+			for _, ts := range m.TimeSeries {
+				if ts.LabelValues[0].Value == certFile {
+					assert.Equal(t, notAfter.Unix(), ts.Points[len(ts.Points)-1].Value.(int64))
+					return
+				}
+			}
+		}
+	}
+	assert.Fail(t, "certificate expiry metric not reported")
+}
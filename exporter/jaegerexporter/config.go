@@ -15,12 +15,27 @@
 package jaegerexporter
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
-// Config defines configuration for Jaeger gRPC exporter.
+const (
+	// transportGRPC sends the spans to the gRPC endpoint of the Jaeger collector.
+	transportGRPC = "grpc"
+	// transportThriftHTTP sends the spans as Thrift batches to the HTTP endpoint of the Jaeger collector.
+	transportThriftHTTP = "thrift_http"
+	// transportThriftCompact sends the spans as compact Thrift batches in UDP packets to a Jaeger agent.
+	transportThriftCompact = "thrift_compact"
+
+	// defaultMaxPacketSize is the default maximum size of the UDP packets, as accepted by the Jaeger agent.
+	defaultMaxPacketSize = 65_000
+)
+
+// Config defines configuration for Jaeger exporter.
 type Config struct {
 	config.ExporterSettings        `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
@@ -28,11 +43,52 @@ type Config struct {
 	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
 
 	configgrpc.GRPCClientSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// Transport is the protocol the spans are sent with, "grpc", "thrift_http" or "thrift_compact".
+	// Defaults to "grpc".
+	Transport string `mapstructure:"transport"`
+
+	// ThriftHTTP configures the client of the "thrift_http" transport. Its endpoint is the URL of the Thrift HTTP
+	// endpoint of the Jaeger collector, e.g. "http://jaeger-collector:14268/api/traces".
+	ThriftHTTP confighttp.HTTPClientSettings `mapstructure:"thrift_http"`
+
+	// ThriftCompact configures the "thrift_compact" transport.
+	ThriftCompact ThriftCompactSettings `mapstructure:"thrift_compact"`
+}
+
+// ThriftCompactSettings defines the settings of the transport sending the spans to the UDP compact Thrift
+// endpoint of a Jaeger agent.
+type ThriftCompactSettings struct {
+	// Endpoint is the host:port of the Jaeger agent, e.g. "jaeger-agent:6831".
+	Endpoint string `mapstructure:"endpoint"`
+
+	// MaxPacketSize is the maximum size of the UDP packets. The batches that don't fit are split into
+	// several packets.
+	MaxPacketSize int `mapstructure:"max_packet_size"`
 }
 
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
-	return nil
+	switch cfg.Transport {
+	case "", transportGRPC:
+		return cfg.GRPCClientSettings.Validate()
+	case transportThriftHTTP:
+		if cfg.ThriftHTTP.Endpoint == "" {
+			return fmt.Errorf("\"thrift_http\" transport requires a non-empty \"thrift_http\" \"endpoint\"")
+		}
+		return cfg.ThriftHTTP.Validate()
+	case transportThriftCompact:
+		if cfg.ThriftCompact.Endpoint == "" {
+			return fmt.Errorf("\"thrift_compact\" transport requires a non-empty \"thrift_compact\" \"endpoint\"")
+		}
+		if cfg.ThriftCompact.MaxPacketSize <= 0 {
+			return fmt.Errorf("\"thrift_compact\" \"max_packet_size\" must be positive")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported transport %q, must be %q, %q or %q",
+			cfg.Transport, transportGRPC, transportThriftHTTP, transportThriftCompact)
+	}
 }
@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtest"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
@@ -67,10 +68,116 @@ func TestLoadConfig(t *testing.T) {
 				WriteBufferSize: 512 * 1024,
 				BalancerName:    "round_robin",
 			},
+			Transport: transportGRPC,
+			ThriftCompact: ThriftCompactSettings{
+				MaxPacketSize: defaultMaxPacketSize,
+			},
 		})
 
 	set := componenttest.NewNopExporterCreateSettings()
 	te, err := factory.CreateTracesExporter(context.Background(), set, e1)
 	require.NoError(t, err)
 	require.NotNil(t, te)
+
+	e2 := cfg.Exporters[config.NewIDWithName(typeStr, "thrift_http")].(*Config)
+	assert.Equal(t, transportThriftHTTP, e2.Transport)
+	assert.Equal(t, confighttp.HTTPClientSettings{
+		Endpoint: "http://jaeger-collector:14268/api/traces",
+		Timeout:  5 * time.Second,
+		Headers:  map[string]string{"X-Tenant": "tenant-1"},
+	}, e2.ThriftHTTP)
+
+	te, err = factory.CreateTracesExporter(context.Background(), set, e2)
+	require.NoError(t, err)
+	require.NotNil(t, te)
+
+	e3 := cfg.Exporters[config.NewIDWithName(typeStr, "thrift_compact")].(*Config)
+	assert.Equal(t, transportThriftCompact, e3.Transport)
+	assert.Equal(t, ThriftCompactSettings{
+		Endpoint:      "jaeger-agent:6831",
+		MaxPacketSize: 1500,
+	}, e3.ThriftCompact)
+
+	te, err = factory.CreateTracesExporter(context.Background(), set, e3)
+	require.NoError(t, err)
+	require.NotNil(t, te)
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		errMsg string
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name: "thrift_http",
+			modify: func(cfg *Config) {
+				cfg.Transport = transportThriftHTTP
+				cfg.ThriftHTTP.Endpoint = "http://jaeger-collector:14268/api/traces"
+			},
+		},
+		{
+			name: "thrift_http without endpoint",
+			modify: func(cfg *Config) {
+				cfg.Transport = transportThriftHTTP
+			},
+			errMsg: `"thrift_http" transport requires a non-empty "thrift_http" "endpoint"`,
+		},
+		{
+			name: "thrift_http with invalid proxy",
+			modify: func(cfg *Config) {
+				cfg.Transport = transportThriftHTTP
+				cfg.ThriftHTTP.Endpoint = "http://jaeger-collector:14268/api/traces"
+				cfg.ThriftHTTP.ProxyURL = "ftp://proxy:21"
+			},
+			errMsg: "proxy_url",
+		},
+		{
+			name: "thrift_compact",
+			modify: func(cfg *Config) {
+				cfg.Transport = transportThriftCompact
+				cfg.ThriftCompact.Endpoint = "jaeger-agent:6831"
+			},
+		},
+		{
+			name: "thrift_compact without endpoint",
+			modify: func(cfg *Config) {
+				cfg.Transport = transportThriftCompact
+			},
+			errMsg: `"thrift_compact" transport requires a non-empty "thrift_compact" "endpoint"`,
+		},
+		{
+			name: "thrift_compact without max packet size",
+			modify: func(cfg *Config) {
+				cfg.Transport = transportThriftCompact
+				cfg.ThriftCompact.Endpoint = "jaeger-agent:6831"
+				cfg.ThriftCompact.MaxPacketSize = 0
+			},
+			errMsg: `"thrift_compact" "max_packet_size" must be positive`,
+		},
+		{
+			name: "unsupported transport",
+			modify: func(cfg *Config) {
+				cfg.Transport = "thrift_tcp"
+			},
+			errMsg: `unsupported transport "thrift_tcp", must be "grpc", "thrift_http" or "thrift_compact"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
 }
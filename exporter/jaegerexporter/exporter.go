@@ -37,11 +37,27 @@ import (
 	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
 )
 
-// newTracesExporter returns a new Jaeger gRPC exporter.
+// sender sends the spans to Jaeger with one of the transports.
+type sender interface {
+	pushTraces(ctx context.Context, td pdata.Traces) error
+	start(ctx context.Context, host component.Host) error
+	shutdown(ctx context.Context) error
+}
+
+// newTracesExporter returns a new Jaeger exporter, sending the spans with the gRPC transport,
+// or the Thrift HTTP or compact Thrift UDP one if configured.
 // The exporter name is the name to be used in the observability of the exporter.
 // The collectorEndpoint should be of the form "hostname:14250" (a gRPC target).
 func newTracesExporter(cfg *Config, logger *zap.Logger) (component.TracesExporter, error) {
-	s := newProtoGRPCSender(cfg, logger)
+	var s sender
+	switch cfg.Transport {
+	case transportThriftHTTP:
+		s = newThriftHTTPSender(cfg, logger)
+	case transportThriftCompact:
+		s = newThriftCompactSender(cfg, logger)
+	default:
+		s = newProtoGRPCSender(cfg, logger)
+	}
 	return exporterhelper.NewTracesExporter(
 		cfg, logger, s.pushTraces,
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
@@ -43,6 +43,10 @@ func createDefaultConfig() config.Exporter {
 		TimeoutSettings:  exporterhelper.DefaultTimeoutSettings(),
 		RetrySettings:    exporterhelper.DefaultRetrySettings(),
 		QueueSettings:    exporterhelper.DefaultQueueSettings(),
+		Transport:        transportGRPC,
+		ThriftCompact: ThriftCompactSettings{
+			MaxPacketSize: defaultMaxPacketSize,
+		},
 		GRPCClientSettings: configgrpc.GRPCClientSettings{
 			// We almost read 0 bytes, so no need to tune ReadBufferSize.
 			WriteBufferSize: 512 * 1024,
@@ -57,7 +61,7 @@ func createTracesExporter(
 ) (component.TracesExporter, error) {
 
 	expCfg := config.(*Config)
-	if expCfg.Endpoint == "" {
+	if (expCfg.Transport == "" || expCfg.Transport == transportGRPC) && expCfg.Endpoint == "" {
 		// TODO: Improve error message, see #215
 		return nil, fmt.Errorf(
 			"%q config requires a non-empty \"endpoint\"",
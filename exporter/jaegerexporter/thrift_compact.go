@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerexporter
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/agent"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/pdatautil"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// thriftCompactSender forwards spans encoded in the jaeger thrift
+// format, as emitBatch calls of the compact Thrift protocol in UDP
+// packets, to a Jaeger agent.
+type thriftCompactSender struct {
+	logger   *zap.Logger
+	settings *ThriftCompactSettings
+	conn     net.Conn
+}
+
+func newThriftCompactSender(cfg *Config, logger *zap.Logger) *thriftCompactSender {
+	return &thriftCompactSender{
+		logger:   logger,
+		settings: &cfg.ThriftCompact,
+	}
+}
+
+func (s *thriftCompactSender) start(context.Context, component.Host) error {
+	conn, err := net.Dial("udp", s.settings.Endpoint)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *thriftCompactSender) shutdown(context.Context) error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+func (s *thriftCompactSender) pushTraces(ctx context.Context, td pdata.Traces) error {
+	batches, err := resourceBatches(td)
+	if err != nil {
+		return consumererror.Permanent(fmt.Errorf("failed to push trace data via Jaeger exporter: %w", err))
+	}
+
+	dropped := 0
+	for i, rb := range batches {
+		n, err := s.emit(ctx, rb.batch)
+		if err != nil {
+			s.logger.Debug("failed to push trace data to Jaeger", zap.Error(err))
+			err = fmt.Errorf("failed to push trace data via Jaeger exporter: %w", err)
+			if i == 0 || consumererror.IsPermanent(err) {
+				return err
+			}
+			// The batches of the previous resources were sent, the following ones are retried, including the
+			// failed one, some packets of which may already be sent.
+			return consumererror.NewTraces(err, pdatautil.TracesFrom(td, rb.resource))
+		}
+		dropped += n
+	}
+	if dropped > 0 {
+		return consumererror.Permanent(fmt.Errorf(
+			"dropped %d spans larger than the max packet size of %d bytes", dropped, s.settings.MaxPacketSize))
+	}
+	return nil
+}
+
+// emit sends the batch in one packet, or splits its spans in halves sent separately, each with the process, if the
+// packet is larger than the max packet size. It returns the number of spans dropped because they don't fit in a
+// packet on their own.
+func (s *thriftCompactSender) emit(ctx context.Context, batch *jaeger.Batch) (int, error) {
+	packet, err := serializeEmitBatch(ctx, batch)
+	if err != nil {
+		return 0, consumererror.Permanent(err)
+	}
+	if len(packet) <= s.settings.MaxPacketSize {
+		_, err = s.conn.Write(packet)
+		return 0, err
+	}
+	if len(batch.Spans) <= 1 {
+		return len(batch.Spans), nil
+	}
+
+	half := len(batch.Spans) / 2
+	dropped, err := s.emit(ctx, &jaeger.Batch{Process: batch.Process, Spans: batch.Spans[:half]})
+	if err != nil {
+		return 0, err
+	}
+	n, err := s.emit(ctx, &jaeger.Batch{Process: batch.Process, Spans: batch.Spans[half:]})
+	return dropped + n, err
+}
+
+// serializeEmitBatch returns the emitBatch call of the Jaeger agent for the batch, in the compact Thrift protocol.
+func serializeEmitBatch(ctx context.Context, batch *jaeger.Batch) ([]byte, error) {
+	buf := thrift.NewTMemoryBuffer()
+	client := agent.NewAgentClientFactory(buf, thrift.NewTCompactProtocolFactoryConf(&thrift.TConfiguration{}))
+	if err := client.EmitBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to serialize Jaeger Thrift batch: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/agent"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/model/pdata"
+	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
+)
+
+func TestThriftCompactSender(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Transport = transportThriftCompact
+	cfg.ThriftCompact.Endpoint = conn.LocalAddr().String()
+	// Disable the queue to send the spans synchronously.
+	cfg.QueueSettings.Enabled = false
+	require.NoError(t, cfg.Validate())
+
+	set := componenttest.NewNopExporterCreateSettings()
+	exp, err := NewFactory().CreateTracesExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, exp.Shutdown(context.Background())) }()
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), generateTracesWithIDs()))
+
+	batches := readEmitBatches(t, conn, 1)
+	require.Len(t, batches[0].Spans, 2)
+	assert.Equal(t, "operationA", batches[0].Spans[0].OperationName)
+	assert.Equal(t, "operationB", batches[0].Spans[1].OperationName)
+}
+
+func TestThriftCompactSenderSplit(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	td := generateTracesWithIDs()
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 2; i < 10; i++ {
+		span := spans.AppendEmpty()
+		spans.At(0).CopyTo(span)
+		span.SetSpanID(pdata.NewSpanID([8]byte{0, 1, 2, 3, 4, 5, 6, byte(i + 1)}))
+	}
+	onePacket, err := serializeEmitBatch(context.Background(), mustThriftBatch(t, td))
+	require.NoError(t, err)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ThriftCompact.Endpoint = conn.LocalAddr().String()
+	cfg.ThriftCompact.MaxPacketSize = len(onePacket) / 3
+	s := newThriftCompactSender(cfg, componenttest.NewNopExporterCreateSettings().Logger)
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, s.shutdown(context.Background())) }()
+
+	require.NoError(t, s.pushTraces(context.Background(), td))
+
+	// The spans are split in halves until they fit in the packets.
+	var spanIDs []int64
+	packets := 0
+	for len(spanIDs) < 10 {
+		batch := readEmitBatches(t, conn, 1)[0]
+		assert.NotNil(t, batch.Process)
+		for _, span := range batch.Spans {
+			spanIDs = append(spanIDs, span.SpanId)
+		}
+		packets++
+	}
+	assert.Len(t, spanIDs, 10)
+	assert.GreaterOrEqual(t, packets, 3)
+}
+
+func TestThriftCompactSenderDropsLargeSpans(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	td := generateTracesWithIDs()
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.At(1).Attributes().InsertString("large", strings.Repeat("x", 2000))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ThriftCompact.Endpoint = conn.LocalAddr().String()
+	cfg.ThriftCompact.MaxPacketSize = 1000
+	s := newThriftCompactSender(cfg, componenttest.NewNopExporterCreateSettings().Logger)
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, s.shutdown(context.Background())) }()
+
+	err = s.pushTraces(context.Background(), td)
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Contains(t, err.Error(), "dropped 1 spans larger than the max packet size of 1000 bytes")
+
+	// The other span is still sent.
+	batches := readEmitBatches(t, conn, 1)
+	require.Len(t, batches[0].Spans, 1)
+	assert.Equal(t, "operationA", batches[0].Spans[0].OperationName)
+}
+
+func TestThriftCompactSenderPartialFailure(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ThriftCompact.Endpoint = conn.LocalAddr().String()
+	s := newThriftCompactSender(cfg, componenttest.NewNopExporterCreateSettings().Logger)
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, s.shutdown(context.Background())) }()
+	s.conn = &failingConn{Conn: s.conn, writes: 1}
+
+	// The batch of the first resource is sent, only the following resources are returned to be retried.
+	err = s.pushTraces(context.Background(), generateTracesWithResources())
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	var tracesErr consumererror.Traces
+	require.True(t, consumererror.AsTraces(err, &tracesErr))
+	assert.Equal(t, []string{"operationC", "operationD"}, resourceSpanNames(tracesErr.GetTraces()))
+
+	batches := readEmitBatches(t, conn, 1)
+	require.Len(t, batches[0].Spans, 2)
+	assert.Equal(t, "operationA", batches[0].Spans[0].OperationName)
+}
+
+// failingConn is a net.Conn failing the writes after the first ones.
+type failingConn struct {
+	net.Conn
+	writes int
+}
+
+func (c *failingConn) Write(b []byte) (int, error) {
+	if c.writes == 0 {
+		return 0, errors.New("write failed")
+	}
+	c.writes--
+	return c.Conn.Write(b)
+}
+
+func mustThriftBatch(t *testing.T, td pdata.Traces) *jaeger.Batch {
+	require.Equal(t, 1, td.ResourceSpans().Len())
+	batch, err := jaegertranslator.ResourceSpansToJaegerThrift(td.ResourceSpans().At(0))
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+	return batch
+}
+
+// readEmitBatches reads n packets of emitBatch calls from the connection, as the Jaeger agent does.
+func readEmitBatches(t *testing.T, conn net.PacketConn, n int) []*jaeger.Batch {
+	var batches []*jaeger.Batch
+	buf := make([]byte, defaultMaxPacketSize)
+	for i := 0; i < n; i++ {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		size, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+
+		trans := thrift.NewTMemoryBuffer()
+		_, err = trans.Write(buf[:size])
+		require.NoError(t, err)
+		protocol := thrift.NewTCompactProtocolConf(trans, &thrift.TConfiguration{})
+		name, _, _, err := protocol.ReadMessageBegin(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "emitBatch", name)
+		args := agent.NewAgentEmitBatchArgs()
+		require.NoError(t, args.Read(context.Background(), protocol))
+		batches = append(batches, args.Batch)
+	}
+	return batches
+}
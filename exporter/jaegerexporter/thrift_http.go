@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerexporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/pdatautil"
+	"go.opentelemetry.io/collector/model/pdata"
+	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
+)
+
+const (
+	// thriftContentType is the content type of the binary Thrift batches accepted by the Jaeger collector.
+	thriftContentType = "application/x-thrift"
+	// maxErrorBodyBytes is the maximum number of bytes of the error responses kept in the returned errors.
+	maxErrorBodyBytes = 1024
+)
+
+// thriftHTTPSender forwards spans encoded in the jaeger thrift
+// format, to the Thrift HTTP endpoint of a Jaeger collector.
+type thriftHTTPSender struct {
+	logger         *zap.Logger
+	clientSettings *confighttp.HTTPClientSettings
+	client         *http.Client
+}
+
+func newThriftHTTPSender(cfg *Config, logger *zap.Logger) *thriftHTTPSender {
+	return &thriftHTTPSender{
+		logger:         logger,
+		clientSettings: &cfg.ThriftHTTP,
+	}
+}
+
+func (s *thriftHTTPSender) start(_ context.Context, host component.Host) error {
+	client, err := s.clientSettings.ToClient(host.GetExtensions())
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+func (s *thriftHTTPSender) shutdown(context.Context) error {
+	if s.client != nil {
+		s.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+func (s *thriftHTTPSender) pushTraces(ctx context.Context, td pdata.Traces) error {
+	batches, err := resourceBatches(td)
+	if err != nil {
+		return consumererror.Permanent(fmt.Errorf("failed to push trace data via Jaeger exporter: %w", err))
+	}
+
+	serializer := thrift.NewTSerializer()
+	for i, rb := range batches {
+		body, err := serializer.Write(ctx, rb.batch)
+		if err != nil {
+			return consumererror.Permanent(fmt.Errorf("failed to serialize Jaeger Thrift batch: %w", err))
+		}
+		if err = s.post(ctx, body); err != nil {
+			s.logger.Debug("failed to push trace data to Jaeger", zap.Error(err))
+			err = fmt.Errorf("failed to push trace data via Jaeger exporter: %w", err)
+			if i == 0 || consumererror.IsPermanent(err) {
+				return err
+			}
+			// The batches of the previous resources were sent, only the following ones are retried.
+			return consumererror.NewTraces(err, pdatautil.TracesFrom(td, rb.resource))
+		}
+	}
+	return nil
+}
+
+// resourceBatch is the Jaeger Thrift batch of the resource at the index resource of the traces.
+type resourceBatch struct {
+	resource int
+	batch    *jaeger.Batch
+}
+
+// resourceBatches translates the resources of td into Jaeger Thrift batches, skipping the empty resources.
+func resourceBatches(td pdata.Traces) ([]resourceBatch, error) {
+	rss := td.ResourceSpans()
+	batches := make([]resourceBatch, 0, rss.Len())
+	for i := 0; i < rss.Len(); i++ {
+		batch, err := jaegertranslator.ResourceSpansToJaegerThrift(rss.At(i))
+		if err != nil {
+			return nil, err
+		}
+		if batch != nil {
+			batches = append(batches, resourceBatch{resource: i, batch: batch})
+		}
+	}
+	return batches, nil
+}
+
+// post sends the serialized batch to the collector. The 4xx responses are permanent errors, apart from
+// 408 Request Timeout and 429 Too Many Requests which are retried like the other failures.
+func (s *thriftHTTPSender) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.clientSettings.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+	req.Header.Set("Content-Type", thriftContentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Discard the remaining response body so that the connection can be reused.
+		io.Copy(ioutil.Discard, resp.Body) // nolint:errcheck
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	err = fmt.Errorf("request to %s responded with HTTP status code %d: %s",
+		s.clientSettings.Endpoint, resp.StatusCode, strings.TrimSpace(string(msg)))
+	if resp.StatusCode >= 400 && resp.StatusCode <= 499 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return consumererror.Permanent(err)
+	}
+	return err
+}
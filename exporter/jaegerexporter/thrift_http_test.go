@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerexporter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestThriftHTTPSender(t *testing.T) {
+	var mu sync.Mutex
+	var batches []*jaeger.Batch
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/traces", r.URL.Path)
+		assert.Equal(t, thriftContentType, r.Header.Get("Content-Type"))
+		assert.Equal(t, "value", r.Header.Get("X-Custom"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		batch := &jaeger.Batch{}
+		require.NoError(t, thrift.NewTDeserializer().Read(context.Background(), batch, body))
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Transport = transportThriftHTTP
+	cfg.ThriftHTTP.Endpoint = srv.URL + "/api/traces"
+	cfg.ThriftHTTP.Headers = map[string]string{"X-Custom": "value"}
+	// Disable the queue to send the spans synchronously.
+	cfg.QueueSettings.Enabled = false
+	require.NoError(t, cfg.Validate())
+
+	set := componenttest.NewNopExporterCreateSettings()
+	exp, err := NewFactory().CreateTracesExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, exp.Shutdown(context.Background())) }()
+
+	td := generateTracesWithIDs()
+	require.NoError(t, exp.ConsumeTraces(context.Background(), td))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Spans, 2)
+	assert.Equal(t, "operationA", batches[0].Spans[0].OperationName)
+	assert.Equal(t, "operationB", batches[0].Spans[1].OperationName)
+}
+
+func TestThriftHTTPSenderErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		permanent bool
+	}{
+		{
+			name:      "bad request",
+			status:    http.StatusBadRequest,
+			permanent: true,
+		},
+		{
+			name:   "too many requests",
+			status: http.StatusTooManyRequests,
+		},
+		{
+			name:   "unavailable",
+			status: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "cannot process", tt.status)
+			}))
+			defer srv.Close()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.Transport = transportThriftHTTP
+			cfg.ThriftHTTP.Endpoint = srv.URL
+			s := newThriftHTTPSender(cfg, componenttest.NewNopExporterCreateSettings().Logger)
+			require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+			err := s.pushTraces(context.Background(), generateTracesWithIDs())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "cannot process")
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(err))
+			assert.NoError(t, s.shutdown(context.Background()))
+		})
+	}
+}
+
+func TestThriftHTTPSenderPartialFailure(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > 1 {
+			http.Error(w, "cannot process", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Transport = transportThriftHTTP
+	cfg.ThriftHTTP.Endpoint = srv.URL
+	s := newThriftHTTPSender(cfg, componenttest.NewNopExporterCreateSettings().Logger)
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, s.shutdown(context.Background())) }()
+
+	// The batch of the first resource is sent, only the following resources are returned to be retried.
+	err := s.pushTraces(context.Background(), generateTracesWithResources())
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	var tracesErr consumererror.Traces
+	require.True(t, consumererror.AsTraces(err, &tracesErr))
+	assert.Equal(t, []string{"operationC", "operationD"}, resourceSpanNames(tracesErr.GetTraces()))
+}
+
+// generateTracesWithResources returns traces with an empty resource, skipped by the translation, followed by three
+// resources with the spans operationA and operationB, operationC and operationD.
+func generateTracesWithResources() pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty()
+	generateTracesWithIDs().ResourceSpans().At(0).CopyTo(td.ResourceSpans().AppendEmpty())
+	for _, name := range []string{"operationC", "operationD"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		generateTracesWithIDs().ResourceSpans().At(0).CopyTo(rs)
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(1)
+		spans.At(0).SetName(name)
+	}
+	return td
+}
+
+// resourceSpanNames returns the names of the spans of td, with one entry per resource joined by ",".
+func resourceSpanNames(td pdata.Traces) []string {
+	var names []string
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		var resourceNames []string
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				resourceNames = append(resourceNames, spans.At(k).Name())
+			}
+		}
+		names = append(names, strings.Join(resourceNames, ","))
+	}
+	return names
+}
+
+// generateTracesWithIDs returns two spans of a resource, with the trace and span IDs required by Jaeger.
+func generateTracesWithIDs() pdata.Traces {
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetTraceID(pdata.NewTraceID([16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}))
+		spans.At(i).SetSpanID(pdata.NewSpanID([8]byte{0, 1, 2, 3, 4, 5, 6, byte(i + 1)}))
+	}
+	return td
+}
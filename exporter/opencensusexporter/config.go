@@ -15,6 +15,10 @@
 package opencensusexporter
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -29,11 +33,43 @@ type Config struct {
 
 	// The number of workers that send the gRPC requests.
 	NumWorkers int `mapstructure:"num_workers"`
+
+	// The maximum number of export streams, one per distinct node and resource, 100 if 0.
+	MaxStreams int `mapstructure:"max_streams"`
+
+	// The time after which the unused export streams are closed, 5m if 0.
+	StreamIdleTimeout time.Duration `mapstructure:"stream_idle_timeout"`
 }
 
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
+	if err := cfg.GRPCClientSettings.Validate(); err != nil {
+		return err
+	}
+	if cfg.MaxStreams < 0 {
+		return errors.New("max_streams can't be negative")
+	}
+	if cfg.StreamIdleTimeout < 0 {
+		return errors.New("stream_idle_timeout can't be negative")
+	}
+	if cfg.StreamIdleTimeout > 0 && cfg.StreamIdleTimeout < minStreamIdleTimeout {
+		return fmt.Errorf("stream_idle_timeout must be at least %v", minStreamIdleTimeout)
+	}
 	return nil
 }
+
+func (cfg *Config) maxStreams() int {
+	if cfg.MaxStreams <= 0 {
+		return defaultMaxStreams
+	}
+	return cfg.MaxStreams
+}
+
+func (cfg *Config) streamIdleTimeout() time.Duration {
+	if cfg.StreamIdleTimeout <= 0 {
+		return defaultStreamIdleTimeout
+	}
+	return cfg.StreamIdleTimeout
+}
@@ -81,6 +81,23 @@ func TestLoadConfig(t *testing.T) {
 				WriteBufferSize: 512 * 1024,
 				BalancerName:    "round_robin",
 			},
-			NumWorkers: 123,
+			NumWorkers:        123,
+			MaxStreams:        10,
+			StreamIdleTimeout: time.Minute,
 		})
 }
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MaxStreams = -1
+	assert.EqualError(t, cfg.Validate(), "max_streams can't be negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.StreamIdleTimeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "stream_idle_timeout can't be negative")
+
+	cfg.StreamIdleTimeout = time.Nanosecond
+	assert.EqualError(t, cfg.Validate(), "stream_idle_timeout must be at least 1s")
+}
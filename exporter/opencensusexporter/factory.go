@@ -16,6 +16,7 @@ package opencensusexporter
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -27,6 +28,10 @@ import (
 const (
 	// The value of "type" key in configuration.
 	typeStr = "opencensus"
+
+	defaultMaxStreams        = 100
+	defaultStreamIdleTimeout = 5 * time.Minute
+	minStreamIdleTimeout     = time.Second
 )
 
 // NewFactory creates a factory for OTLP exporter.
@@ -46,7 +51,9 @@ func createDefaultConfig() config.Exporter {
 			// We almost read 0 bytes, so no need to tune ReadBufferSize.
 			WriteBufferSize: 512 * 1024,
 		},
-		NumWorkers: 2,
+		NumWorkers:        2,
+		MaxStreams:        defaultMaxStreams,
+		StreamIdleTimeout: defaultStreamIdleTimeout,
 	}
 }
 
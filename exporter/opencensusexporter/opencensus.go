@@ -27,33 +27,23 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/pdatautil"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/translator/internaldata"
 )
 
-// See https://godoc.org/google.golang.org/grpc#ClientConn.NewStream
-// why we need to keep the cancel func to cancel the stream
-type tracesClientWithCancel struct {
-	cancel context.CancelFunc
-	tsec   agenttracepb.TraceService_ExportClient
-}
-
-// See https://godoc.org/google.golang.org/grpc#ClientConn.NewStream
-// why we need to keep the cancel func to cancel the stream
-type metricsClientWithCancel struct {
-	cancel context.CancelFunc
-	msec   agentmetricspb.MetricsService_ExportClient
-}
-
 type ocExporter struct {
 	cfg *Config
 	// gRPC clients and connection.
 	traceSvcClient   agenttracepb.TraceServiceClient
 	metricsSvcClient agentmetricspb.MetricsServiceClient
-	// In any of the channels we keep always NumWorkers object (sometimes nil),
-	// to make sure we don't open more than NumWorkers RPCs at any moment.
-	tracesClients  chan *tracesClientWithCancel
-	metricsClients chan *metricsClientWithCancel
+	// The export streams, one per node and resource.
+	tracesStreams  *streamPool
+	metricsStreams *streamPool
+	// In the workers channel we keep always NumWorkers tokens, to make sure we don't send
+	// more than NumWorkers requests at any moment.
+	workers        chan struct{}
 	grpcClientConn *grpc.ClientConn
 	metadata       metadata.MD
 }
@@ -70,6 +60,7 @@ func newOcExporter(_ context.Context, cfg *Config) (*ocExporter, error) {
 	oce := &ocExporter{
 		cfg:      cfg,
 		metadata: metadata.New(cfg.GRPCClientSettings.Headers),
+		workers:  make(chan struct{}, cfg.NumWorkers),
 	}
 	return oce, nil
 }
@@ -87,44 +78,37 @@ func (oce *ocExporter) start(ctx context.Context, host component.Host) error {
 
 	oce.grpcClientConn = clientConn
 
-	if oce.tracesClients != nil {
+	if oce.tracesStreams != nil {
 		oce.traceSvcClient = agenttracepb.NewTraceServiceClient(oce.grpcClientConn)
-		// Try to create rpc clients now.
-		for i := 0; i < oce.cfg.NumWorkers; i++ {
-			// Populate the channel with NumWorkers nil RPCs to keep the number of workers
-			// constant in the channel.
-			oce.tracesClients <- nil
-		}
+		oce.tracesStreams.start()
 	}
 
-	if oce.metricsClients != nil {
+	if oce.metricsStreams != nil {
 		oce.metricsSvcClient = agentmetricspb.NewMetricsServiceClient(oce.grpcClientConn)
-		// Try to create rpc clients now.
-		for i := 0; i < oce.cfg.NumWorkers; i++ {
-			// Populate the channel with NumWorkers nil RPCs to keep the number of workers
-			// constant in the channel.
-			oce.metricsClients <- nil
-		}
+		oce.metricsStreams.start()
+	}
+
+	for i := 0; i < oce.cfg.NumWorkers; i++ {
+		oce.workers <- struct{}{}
 	}
 	return nil
 }
 
 func (oce *ocExporter) shutdown(context.Context) error {
-	if oce.tracesClients != nil {
-		// First remove all the clients from the channel.
-		for i := 0; i < oce.cfg.NumWorkers; i++ {
-			<-oce.tracesClients
-		}
-		// Now close the channel
-		close(oce.tracesClients)
+	if oce.grpcClientConn == nil {
+		return nil
 	}
-	if oce.metricsClients != nil {
-		// First remove all the clients from the channel.
-		for i := 0; i < oce.cfg.NumWorkers; i++ {
-			<-oce.metricsClients
-		}
-		// Now close the channel
-		close(oce.metricsClients)
+	// First wait for the requests being sent, by removing all the tokens from the channel.
+	for i := 0; i < oce.cfg.NumWorkers; i++ {
+		<-oce.workers
+	}
+	// Now close the channel
+	close(oce.workers)
+	if oce.tracesStreams != nil {
+		oce.tracesStreams.shutdown()
+	}
+	if oce.metricsStreams != nil {
+		oce.metricsStreams.shutdown()
 	}
 	return oce.grpcClientConn.Close()
 }
@@ -134,7 +118,7 @@ func newTracesExporter(ctx context.Context, cfg *Config) (*ocExporter, error) {
 	if err != nil {
 		return nil, err
 	}
-	oce.tracesClients = make(chan *tracesClientWithCancel, oce.cfg.NumWorkers)
+	oce.tracesStreams = newStreamPool(cfg.maxStreams(), cfg.streamIdleTimeout(), oce.createTraceServiceRPC)
 	return oce, nil
 }
 
@@ -143,31 +127,17 @@ func newMetricsExporter(ctx context.Context, cfg *Config) (*ocExporter, error) {
 	if err != nil {
 		return nil, err
 	}
-	oce.metricsClients = make(chan *metricsClientWithCancel, oce.cfg.NumWorkers)
+	oce.metricsStreams = newStreamPool(cfg.maxStreams(), cfg.streamIdleTimeout(), oce.createMetricsServiceRPC)
 	return oce, nil
 }
 
 func (oce *ocExporter) pushTraces(_ context.Context, td pdata.Traces) error {
-	// Get first available trace Client.
-	tClient, ok := <-oce.tracesClients
-	if !ok {
+	// Get an available worker.
+	if _, ok := <-oce.workers; !ok {
 		err := errors.New("failed to push traces, OpenCensus exporter was already stopped")
 		return err
 	}
-
-	// In any of the metricsClients channel we keep always NumWorkers object (sometimes nil),
-	// to make sure we don't open more than NumWorkers RPCs at any moment.
-	// Here check if the client is nil and create a new one if that is the case. A nil
-	// object means that an error happened: could not connect, service went down, etc.
-	if tClient == nil {
-		var err error
-		tClient, err = oce.createTraceServiceRPC()
-		if err != nil {
-			// Cannot create an RPC, put back nil to keep the number of workers constant.
-			oce.tracesClients <- nil
-			return err
-		}
-	}
+	defer func() { oce.workers <- struct{}{} }()
 
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
@@ -179,95 +149,84 @@ func (oce *ocExporter) pushTraces(_ context.Context, td pdata.Traces) error {
 		if resource == nil {
 			resource = &resourcepb.Resource{}
 		}
-		req := &agenttracepb.ExportTraceServiceRequest{
-			Spans:    spans,
-			Resource: resource,
-			Node:     node,
-		}
-		if err := tClient.tsec.Send(req); err != nil {
-			// Error received, cancel the context used to create the RPC to free all resources,
-			// put back nil to keep the number of workers constant.
-			tClient.cancel()
-			oce.tracesClients <- nil
-			return err
+		err := oce.tracesStreams.send(node, resource, func(withNode bool) interface{} {
+			req := &agenttracepb.ExportTraceServiceRequest{
+				Spans:    spans,
+				Resource: resource,
+			}
+			if withNode {
+				req.Node = node
+			}
+			return req
+		})
+		if err != nil {
+			// The previous resources were sent, only the following ones are retried.
+			return consumererror.NewTraces(err, pdatautil.TracesFrom(td, i))
 		}
 	}
-	oce.tracesClients <- tClient
 	return nil
 }
 
 func (oce *ocExporter) pushMetrics(_ context.Context, md pdata.Metrics) error {
-	// Get first available mClient.
-	mClient, ok := <-oce.metricsClients
-	if !ok {
+	// Get an available worker.
+	if _, ok := <-oce.workers; !ok {
 		err := errors.New("failed to push metrics, OpenCensus exporter was already stopped")
 		return err
 	}
-
-	// In any of the metricsClients channel we keep always NumWorkers object (sometimes nil),
-	// to make sure we don't open more than NumWorkers RPCs at any moment.
-	// Here check if the client is nil and create a new one if that is the case. A nil
-	// object means that an error happened: could not connect, service went down, etc.
-	if mClient == nil {
-		var err error
-		mClient, err = oce.createMetricsServiceRPC()
-		if err != nil {
-			// Cannot create an RPC, put back nil to keep the number of workers constant.
-			oce.metricsClients <- nil
-			return err
-		}
-	}
+	defer func() { oce.workers <- struct{}{} }()
 
 	rms := md.ResourceMetrics()
 	for i := 0; i < rms.Len(); i++ {
-		ocReq := agentmetricspb.ExportMetricsServiceRequest{}
-		ocReq.Node, ocReq.Resource, ocReq.Metrics = internaldata.ResourceMetricsToOC(rms.At(i))
-
+		node, resource, metrics := internaldata.ResourceMetricsToOC(rms.At(i))
 		// This is a hack because OC protocol expects a Node for the initial message.
-		if ocReq.Node == nil {
-			ocReq.Node = &commonpb.Node{}
+		if node == nil {
+			node = &commonpb.Node{}
 		}
-		if ocReq.Resource == nil {
-			ocReq.Resource = &resourcepb.Resource{}
+		if resource == nil {
+			resource = &resourcepb.Resource{}
 		}
-		if err := mClient.msec.Send(&ocReq); err != nil {
-			// Error received, cancel the context used to create the RPC to free all resources,
-			// put back nil to keep the number of workers constant.
-			mClient.cancel()
-			oce.metricsClients <- nil
-			return err
+		err := oce.metricsStreams.send(node, resource, func(withNode bool) interface{} {
+			req := &agentmetricspb.ExportMetricsServiceRequest{
+				Metrics:  metrics,
+				Resource: resource,
+			}
+			if withNode {
+				req.Node = node
+			}
+			return req
+		})
+		if err != nil {
+			// The previous resources were sent, only the following ones are retried.
+			return consumererror.NewMetrics(err, pdatautil.MetricsFrom(md, i))
 		}
 	}
-	oce.metricsClients <- mClient
 	return nil
 }
 
-func (oce *ocExporter) createTraceServiceRPC() (*tracesClientWithCancel, error) {
-	// Initiate the trace service by sending over node identifier info.
-	ctx, cancel := context.WithCancel(context.Background())
+func (oce *ocExporter) createTraceServiceRPC(ctx context.Context) (func(msg interface{}) error, error) {
 	if len(oce.cfg.Headers) > 0 {
 		ctx = metadata.NewOutgoingContext(ctx, metadata.New(oce.cfg.Headers))
 	}
 	// Cannot use grpc.WaitForReady(cfg.WaitForReady) because will block forever.
 	traceClient, err := oce.traceSvcClient.Export(ctx)
 	if err != nil {
-		cancel()
 		return nil, fmt.Errorf("TraceServiceClient: %w", err)
 	}
-	return &tracesClientWithCancel{cancel: cancel, tsec: traceClient}, nil
+	return func(msg interface{}) error {
+		return traceClient.Send(msg.(*agenttracepb.ExportTraceServiceRequest))
+	}, nil
 }
 
-func (oce *ocExporter) createMetricsServiceRPC() (*metricsClientWithCancel, error) {
-	// Initiate the trace service by sending over node identifier info.
-	ctx, cancel := context.WithCancel(context.Background())
+func (oce *ocExporter) createMetricsServiceRPC(ctx context.Context) (func(msg interface{}) error, error) {
 	if len(oce.cfg.Headers) > 0 {
 		ctx = metadata.NewOutgoingContext(ctx, metadata.New(oce.cfg.Headers))
 	}
 	// Cannot use grpc.WaitForReady(cfg.WaitForReady) because will block forever.
 	metricsClient, err := oce.metricsSvcClient.Export(ctx)
 	if err != nil {
-		cancel()
 		return nil, fmt.Errorf("MetricsServiceClient: %w", err)
 	}
-	return &metricsClientWithCancel{cancel: cancel, msec: metricsClient}, nil
+	return func(msg interface{}) error {
+		return metricsClient.Send(msg.(*agentmetricspb.ExportMetricsServiceRequest))
+	}, nil
 }
@@ -27,8 +27,10 @@ import (
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver"
 	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/translator/conventions"
 )
 
 func TestSendTraces(t *testing.T) {
@@ -85,6 +87,61 @@ func TestSendTraces(t *testing.T) {
 	assert.EqualValues(t, td, traces[0])
 }
 
+func TestSendTracesStreamPerNode(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	rFactory := opencensusreceiver.NewFactory()
+	rCfg := rFactory.CreateDefaultConfig().(*opencensusreceiver.Config)
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	rCfg.GRPCServerSettings.NetAddr.Endpoint = endpoint
+	set := componenttest.NewNopReceiverCreateSettings()
+	recv, err := rFactory.CreateTracesReceiver(context.Background(), set, rCfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, recv.Shutdown(context.Background()))
+	})
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: endpoint,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	td := pdata.NewTraces()
+	for _, service := range []string{"service-a", "service-b"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, service)
+		span := rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetName(service + "-span")
+		span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{1}))
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, exp.pushTraces(context.Background(), td))
+	}
+	assert.Equal(t, 2, exp.tracesStreams.len())
+
+	assert.Eventually(t, func() bool {
+		return sink.SpansCount() == 6
+	}, 10*time.Second, 5*time.Millisecond)
+	for _, received := range sink.AllTraces() {
+		rs := received.ResourceSpans().At(0)
+		service, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName)
+		require.True(t, ok)
+		assert.Equal(t, service.StringVal()+"-span", rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+	}
+}
+
 func TestSendTraces_NoBackend(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig().(*Config)
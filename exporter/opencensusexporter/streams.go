@@ -0,0 +1,244 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// errStreamPoolShutdown is returned for the messages sent after the shutdown of the pool.
+var errStreamPoolShutdown = errors.New("the export streams are shut down")
+
+// exportStream is an export RPC of a node and resource. The node is only sent with the first message of the stream,
+// as the OpenCensus receivers apply the last node of the stream to the following messages.
+type exportStream struct {
+	// lastUsed is the time the stream was last used at, in Unix nanoseconds, accessed atomically.
+	lastUsed int64
+	cancel   context.CancelFunc
+	sendMsg  func(msg interface{}) error
+
+	mu       sync.Mutex
+	nodeSent bool
+	closed   bool
+}
+
+// close cancels the RPC, the stream must be locked.
+func (s *exportStream) close() {
+	if !s.closed {
+		s.closed = true
+		s.cancel()
+	}
+}
+
+// streamPool keeps an export stream per distinct node and resource, so that each stream carries the identity of a
+// single node. At most maxStreams streams are open, the least recently used one being closed to open a new one, and
+// the streams unused for idleTimeout are closed.
+type streamPool struct {
+	maxStreams  int
+	idleTimeout time.Duration
+	// open opens the RPC of a stream, whose messages are sent by the returned func.
+	open func(ctx context.Context) (func(msg interface{}) error, error)
+
+	mu      sync.Mutex
+	streams map[string]*exportStream
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newStreamPool(maxStreams int, idleTimeout time.Duration, open func(ctx context.Context) (func(msg interface{}) error, error)) *streamPool {
+	return &streamPool{
+		maxStreams:  maxStreams,
+		idleTimeout: idleTimeout,
+		open:        open,
+		streams:     map[string]*exportStream{},
+		done:        make(chan struct{}),
+	}
+}
+
+// start starts closing the idle streams.
+func (p *streamPool) start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		// The interval is kept positive for the ticker, whatever the idle timeout.
+		ticker := time.NewTicker(p.idleTimeout/2 + time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.closeIdle(time.Now())
+			case <-p.done:
+				return
+			}
+		}
+	}()
+}
+
+// shutdown closes all the streams.
+func (p *streamPool) shutdown() {
+	close(p.done)
+	p.wg.Wait()
+	p.mu.Lock()
+	streams := p.streams
+	p.streams = map[string]*exportStream{}
+	p.mu.Unlock()
+	closeStreams(streams)
+}
+
+// send sends the message built by newMsg on the stream of the node and resource. newMsg gets whether the node must
+// be set in the message. The stream is closed if the message can't be sent, to be reopened by the next message.
+func (p *streamPool) send(node *commonpb.Node, resource *resourcepb.Resource, newMsg func(withNode bool) interface{}) error {
+	key := streamKey(node, resource)
+	for {
+		s, err := p.get(key)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		if s.closed {
+			// Closed by the eviction since it was got, get a new one.
+			s.mu.Unlock()
+			continue
+		}
+		err = s.sendMsg(newMsg(!s.nodeSent))
+		if err != nil {
+			s.close()
+			s.mu.Unlock()
+			p.remove(key, s)
+			return err
+		}
+		s.nodeSent = true
+		atomic.StoreInt64(&s.lastUsed, time.Now().UnixNano())
+		s.mu.Unlock()
+		return nil
+	}
+}
+
+// get returns the stream of the key, opening it if needed. The stream is opened without locking the pool, so that
+// the other streams are used meanwhile.
+func (p *streamPool) get(key string) (*exportStream, error) {
+	p.mu.Lock()
+	s, ok := p.streams[key]
+	p.mu.Unlock()
+	if ok {
+		return s, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sendMsg, err := p.open(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s = &exportStream{
+		lastUsed: time.Now().UnixNano(),
+		cancel:   cancel,
+		sendMsg:  sendMsg,
+	}
+
+	p.mu.Lock()
+	select {
+	case <-p.done:
+		p.mu.Unlock()
+		cancel()
+		return nil, errStreamPoolShutdown
+	default:
+	}
+	if opened, ok := p.streams[key]; ok {
+		// Opened by another message meanwhile.
+		p.mu.Unlock()
+		cancel()
+		return opened, nil
+	}
+	evicted := map[string]*exportStream{}
+	for len(p.streams) >= p.maxStreams {
+		lruKey, lru := p.leastRecentlyUsed()
+		delete(p.streams, lruKey)
+		evicted[lruKey] = lru
+	}
+	p.streams[key] = s
+	p.mu.Unlock()
+	closeStreams(evicted)
+	return s, nil
+}
+
+// leastRecentlyUsed returns the least recently used stream, the pool must be locked.
+func (p *streamPool) leastRecentlyUsed() (string, *exportStream) {
+	var lruKey string
+	var lru *exportStream
+	var lruTime int64
+	for key, s := range p.streams {
+		lastUsed := atomic.LoadInt64(&s.lastUsed)
+		if lru == nil || lastUsed < lruTime {
+			lruKey, lru, lruTime = key, s, lastUsed
+		}
+	}
+	return lruKey, lru
+}
+
+// remove removes the stream of the key, if it is still s.
+func (p *streamPool) remove(key string, s *exportStream) {
+	p.mu.Lock()
+	if p.streams[key] == s {
+		delete(p.streams, key)
+	}
+	p.mu.Unlock()
+}
+
+// closeIdle closes the streams unused for idleTimeout.
+func (p *streamPool) closeIdle(now time.Time) {
+	idle := map[string]*exportStream{}
+	p.mu.Lock()
+	for key, s := range p.streams {
+		if now.Sub(time.Unix(0, atomic.LoadInt64(&s.lastUsed))) >= p.idleTimeout {
+			idle[key] = s
+			delete(p.streams, key)
+		}
+	}
+	p.mu.Unlock()
+	closeStreams(idle)
+}
+
+// len returns the number of open streams.
+func (p *streamPool) len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.streams)
+}
+
+// closeStreams closes the streams, waiting for the messages being sent.
+func closeStreams(streams map[string]*exportStream) {
+	for _, s := range streams {
+		s.mu.Lock()
+		s.close()
+		s.mu.Unlock()
+	}
+}
+
+// streamKey returns the key of the stream of the node and resource.
+func streamKey(node *commonpb.Node, resource *resourcepb.Resource) string {
+	opts := proto.MarshalOptions{Deterministic: true}
+	nodeBytes, _ := opts.Marshal(node)
+	resourceBytes, _ := opts.Marshal(resource)
+	return string(nodeBytes) + "\xff" + string(resourceBytes)
+}
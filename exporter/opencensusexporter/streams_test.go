@@ -0,0 +1,213 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStreams records the messages sent on the streams opened by open.
+type fakeStreams struct {
+	mu      sync.Mutex
+	opened  int
+	ctxs    []context.Context
+	msgs    [][]interface{}
+	sendErr error
+}
+
+func (f *fakeStreams) open(ctx context.Context) (func(msg interface{}) error, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.opened
+	f.opened++
+	f.ctxs = append(f.ctxs, ctx)
+	f.msgs = append(f.msgs, nil)
+	return func(msg interface{}) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.sendErr != nil {
+			return f.sendErr
+		}
+		f.msgs[i] = append(f.msgs[i], msg)
+		return nil
+	}, nil
+}
+
+func node(name string) *commonpb.Node {
+	return &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: name}}
+}
+
+func sendNode(t *testing.T, p *streamPool, name string) {
+	require.NoError(t, p.send(node(name), &resourcepb.Resource{}, func(withNode bool) interface{} {
+		if withNode {
+			return name + " with node"
+		}
+		return name
+	}))
+}
+
+func TestStreamPoolStreamPerNode(t *testing.T) {
+	f := &fakeStreams{}
+	p := newStreamPool(10, time.Minute, f.open)
+
+	sendNode(t, p, "a")
+	sendNode(t, p, "b")
+	sendNode(t, p, "a")
+	// the resources also have their own streams
+	require.NoError(t, p.send(node("a"), &resourcepb.Resource{Type: "host"}, func(withNode bool) interface{} {
+		return withNode
+	}))
+
+	assert.Equal(t, 3, p.len())
+	assert.Equal(t, [][]interface{}{
+		{"a with node", "a"},
+		{"b with node"},
+		{true},
+	}, f.msgs)
+
+	p.shutdown()
+	assert.Equal(t, 0, p.len())
+	for _, ctx := range f.ctxs {
+		assert.Error(t, ctx.Err(), "the streams must be closed")
+	}
+}
+
+func TestStreamPoolMaxStreams(t *testing.T) {
+	f := &fakeStreams{}
+	p := newStreamPool(2, time.Minute, f.open)
+	defer p.shutdown()
+
+	sendNode(t, p, "a")
+	time.Sleep(time.Millisecond)
+	sendNode(t, p, "b")
+	time.Sleep(time.Millisecond)
+	sendNode(t, p, "a")
+	time.Sleep(time.Millisecond)
+	// b is the least recently used stream
+	sendNode(t, p, "c")
+
+	assert.Equal(t, 2, p.len())
+	assert.NoError(t, f.ctxs[0].Err())
+	assert.Error(t, f.ctxs[1].Err())
+	assert.NoError(t, f.ctxs[2].Err())
+
+	// b gets a new stream, with the node
+	sendNode(t, p, "b")
+	assert.Equal(t, []interface{}{"b with node"}, f.msgs[3])
+	assert.Error(t, f.ctxs[0].Err())
+}
+
+func TestStreamPoolIdleStreams(t *testing.T) {
+	f := &fakeStreams{}
+	p := newStreamPool(10, time.Minute, f.open)
+	defer p.shutdown()
+
+	sendNode(t, p, "a")
+	sendNode(t, p, "b")
+	p.closeIdle(time.Now().Add(30 * time.Second))
+	assert.Equal(t, 2, p.len())
+
+	p.closeIdle(time.Now().Add(time.Minute))
+	assert.Equal(t, 0, p.len())
+	assert.Error(t, f.ctxs[0].Err())
+	assert.Error(t, f.ctxs[1].Err())
+}
+
+func TestStreamPoolIdleStreamsClosedInBackground(t *testing.T) {
+	f := &fakeStreams{}
+	for _, idleTimeout := range []time.Duration{10 * time.Millisecond, time.Nanosecond} {
+		p := newStreamPool(10, idleTimeout, f.open)
+		p.start()
+
+		sendNode(t, p, "a")
+		assert.Eventually(t, func() bool { return p.len() == 0 }, 5*time.Second, 5*time.Millisecond)
+		p.shutdown()
+	}
+}
+
+func TestStreamPoolSendError(t *testing.T) {
+	f := &fakeStreams{}
+	p := newStreamPool(10, time.Minute, f.open)
+	defer p.shutdown()
+
+	sendNode(t, p, "a")
+	f.sendErr = errors.New("send error")
+	assert.Error(t, p.send(node("a"), &resourcepb.Resource{}, func(bool) interface{} { return nil }))
+	assert.Equal(t, 0, p.len())
+	assert.Error(t, f.ctxs[0].Err())
+
+	// the stream is reopened, with the node
+	f.sendErr = nil
+	sendNode(t, p, "a")
+	assert.Equal(t, []interface{}{"a with node"}, f.msgs[1])
+}
+
+func TestStreamPoolOpenWithoutLock(t *testing.T) {
+	f := &fakeStreams{}
+	var opens int32
+	opening, release := make(chan struct{}), make(chan struct{})
+	p := newStreamPool(10, time.Minute, func(ctx context.Context) (func(msg interface{}) error, error) {
+		if atomic.AddInt32(&opens, 1) == 2 {
+			close(opening)
+			<-release
+		}
+		return f.open(ctx)
+	})
+	defer p.shutdown()
+
+	sendNode(t, p, "a")
+	done := make(chan error)
+	go func() {
+		done <- p.send(node("b"), &resourcepb.Resource{}, func(bool) interface{} { return "b" })
+	}()
+	<-opening
+	// The open streams are used while another one is being opened.
+	sendNode(t, p, "a")
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, 2, p.len())
+	assert.Equal(t, []interface{}{"a with node", "a"}, f.msgs[0])
+}
+
+func TestStreamPoolSendAfterShutdown(t *testing.T) {
+	f := &fakeStreams{}
+	p := newStreamPool(10, time.Minute, f.open)
+	p.shutdown()
+
+	assert.Equal(t, errStreamPoolShutdown, p.send(node("a"), &resourcepb.Resource{}, func(bool) interface{} { return nil }))
+	assert.Equal(t, 0, p.len())
+	require.Len(t, f.ctxs, 1)
+	assert.Error(t, f.ctxs[0].Err())
+}
+
+func TestStreamPoolOpenError(t *testing.T) {
+	p := newStreamPool(10, time.Minute, func(context.Context) (func(msg interface{}) error, error) {
+		return nil, errors.New("open error")
+	})
+	defer p.shutdown()
+
+	assert.EqualError(t, p.send(node("a"), &resourcepb.Resource{}, func(bool) interface{} { return nil }), "open error")
+	assert.Equal(t, 0, p.len())
+}
@@ -34,5 +34,5 @@ var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
-	return nil
+	return cfg.GRPCClientSettings.Validate()
 }
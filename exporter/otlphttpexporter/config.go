@@ -37,7 +37,7 @@ type Config struct {
 	LogsEndpoint string `mapstructure:"logs_endpoint"`
 
 	// The compression key for supported compression types within
-	// collector. Currently the only supported mode is `gzip`.
+	// collector, one of `gzip`, `zstd` and `snappy`.
 	Compression string `mapstructure:"compression"`
 }
 
@@ -45,5 +45,5 @@ var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
-	return nil
+	return cfg.HTTPClientSettings.Validate()
 }
@@ -44,6 +44,7 @@ func TestLoadConfig(t *testing.T) {
 	e0 := cfg.Exporters[config.NewID(typeStr)]
 	assert.Equal(t, e0, factory.CreateDefaultConfig())
 
+	maxIdleConns, maxConnsPerHost, idleConnTimeout := 50, 10, 30*time.Second
 	e1 := cfg.Exporters[config.NewIDWithName(typeStr, "2")]
 	assert.Equal(t, e1,
 		&Config{
@@ -77,6 +78,10 @@ func TestLoadConfig(t *testing.T) {
 				ReadBufferSize:  123,
 				WriteBufferSize: 345,
 				Timeout:         time.Second * 10,
+				ProxyURL:        "http://proxy.example.com:3128",
+				MaxIdleConns:    &maxIdleConns,
+				MaxConnsPerHost: &maxConnsPerHost,
+				IdleConnTimeout: &idleConnTimeout,
 			},
 			Compression: "gzip",
 		})
@@ -148,6 +148,16 @@ func TestCompressionOptions(t *testing.T) {
 			baseURL:     fmt.Sprintf("http://%s", addr),
 			compression: "gzip",
 		},
+		{
+			name:        "zstd",
+			baseURL:     fmt.Sprintf("http://%s", addr),
+			compression: "zstd",
+		},
+		{
+			name:        "snappy",
+			baseURL:     fmt.Sprintf("http://%s", addr),
+			compression: "snappy",
+		},
 		{
 			name:        "incorrect compression",
 			baseURL:     fmt.Sprintf("http://%s", addr),
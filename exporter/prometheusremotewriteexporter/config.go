@@ -16,6 +16,8 @@ package prometheusremotewriteexporter
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -41,6 +43,20 @@ type Config struct {
 
 	HTTPClientSettings confighttp.HTTPClientSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 
+	// DeltaToCumulative configures the conversion of the delta sums and histograms to cumulative ones, they are
+	// dropped if disabled.
+	DeltaToCumulative DeltaToCumulativeSettings `mapstructure:"delta_to_cumulative"`
+
+	// SendMetadata enables sending the metadata of the metrics, their type, description and unit.
+	SendMetadata bool `mapstructure:"send_metadata"`
+
+	// Tenant configures the split of the metrics by tenant, each tenant being sent with its own header or to its own
+	// endpoint.
+	Tenant TenantSettings `mapstructure:"tenant"`
+
+	// WAL configures the write-ahead log the requests are written to before being sent, disabled if nil.
+	WAL *WALConfig `mapstructure:"wal"`
+
 	// ResourceToTelemetrySettings is the option for converting resource attributes to telemetry attributes.
 	// "Enabled" - A boolean field to enable/disable this option. Default is `false`.
 	// If enabled, all the resource attributes will be converted to metric labels by default.
@@ -60,6 +76,42 @@ type RemoteWriteQueue struct {
 
 // TODO(jbd): Add capacity, max_samples_per_send to QueueConfig.
 
+// DeltaToCumulativeSettings configures the conversion of the delta sums and histograms to cumulative ones.
+type DeltaToCumulativeSettings struct {
+	// Enabled enables the conversion.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxStale is the duration after which the series that don't receive points are forgotten, their next point
+	// restarts them.
+	MaxStale time.Duration `mapstructure:"max_stale"`
+}
+
+// TenantSettings configures the split of the metrics by tenant.
+type TenantSettings struct {
+	// ResourceAttribute is the resource attribute whose value is the tenant of the metrics, they are not split by
+	// tenant if empty.
+	ResourceAttribute string `mapstructure:"resource_attribute"`
+
+	// Default is the tenant of the resources without the attribute.
+	Default string `mapstructure:"default"`
+
+	// Header is the HTTP header the tenant is sent in, X-Scope-OrgID by default. The tenant is not sent in a header
+	// if empty.
+	Header string `mapstructure:"header"`
+
+	// Endpoints are the remote write URLs of the tenants, the metrics of the other tenants are sent to the endpoint.
+	Endpoints map[string]string `mapstructure:"endpoints"`
+}
+
+// WALConfig configures the write-ahead log of the exporter.
+type WALConfig struct {
+	// Directory is the directory of the WAL files.
+	Directory string `mapstructure:"directory"`
+
+	// SegmentSize is the maximum size in bytes of the WAL segment files, 16MiB if 0.
+	SegmentSize int `mapstructure:"segment_size"`
+}
+
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
@@ -70,5 +122,29 @@ func (cfg *Config) Validate() error {
 	if cfg.RemoteWriteQueue.NumConsumers < 0 {
 		return fmt.Errorf("remote write consumer number can't be negative")
 	}
-	return nil
+	if cfg.DeltaToCumulative.Enabled && cfg.DeltaToCumulative.MaxStale <= 0 {
+		return fmt.Errorf("delta_to_cumulative max_stale must be positive")
+	}
+	if cfg.Tenant.ResourceAttribute == "" && len(cfg.Tenant.Endpoints) != 0 {
+		return fmt.Errorf("tenant endpoints require the tenant resource_attribute")
+	}
+	if cfg.Tenant.ResourceAttribute != "" {
+		if cfg.WAL != nil {
+			return fmt.Errorf("wal can't be enabled with the tenant settings")
+		}
+		for key := range cfg.HTTPClientSettings.Headers {
+			if cfg.Tenant.Header != "" && http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(cfg.Tenant.Header) {
+				return fmt.Errorf("the %s header is set by the tenant settings", cfg.Tenant.Header)
+			}
+		}
+	}
+	if cfg.WAL != nil {
+		if cfg.WAL.Directory == "" {
+			return fmt.Errorf("wal directory can't be empty")
+		}
+		if cfg.WAL.SegmentSize < 0 {
+			return fmt.Errorf("wal segment size can't be negative")
+		}
+	}
+	return cfg.HTTPClientSettings.Validate()
 }
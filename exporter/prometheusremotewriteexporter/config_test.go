@@ -62,6 +62,16 @@ func Test_loadConfig(t *testing.T) {
 				QueueSize:    2000,
 				NumConsumers: 10,
 			},
+			WAL: &WALConfig{
+				Directory:   "/var/lib/otelcol/prometheusremotewrite",
+				SegmentSize: 1048576,
+			},
+			DeltaToCumulative: DeltaToCumulativeSettings{
+				Enabled:  true,
+				MaxStale: 10 * time.Minute,
+			},
+			SendMetadata:   true,
+			Tenant:         TenantSettings{Header: "X-Scope-OrgID"},
 			Namespace:      "test-space",
 			ExternalLabels: map[string]string{"key1": "value1", "key2": "value2"},
 			HTTPClientSettings: confighttp.HTTPClientSettings{
@@ -81,6 +91,14 @@ func Test_loadConfig(t *testing.T) {
 			},
 			ResourceToTelemetrySettings: exporterhelper.ResourceToTelemetrySettings{Enabled: true},
 		})
+
+	e2 := cfg.Exporters[config.NewIDWithName(typeStr, "tenant")].(*Config)
+	assert.Equal(t, TenantSettings{
+		ResourceAttribute: "tenant.id",
+		Default:           "anonymous",
+		Header:            "X-Scope-OrgID",
+		Endpoints:         map[string]string{"team-a": "http://cortex-a:9009/api/v1/push"},
+	}, e2.Tenant)
 }
 
 func TestNegativeQueueSize(t *testing.T) {
@@ -102,3 +120,74 @@ func TestNegativeNumConsumers(t *testing.T) {
 	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "negative_num_consumers.yaml"), factories)
 	assert.Error(t, err)
 }
+
+func TestInvalidDeltaToCumulative(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DeltaToCumulative = DeltaToCumulativeSettings{Enabled: true}
+	assert.EqualError(t, cfg.Validate(), "delta_to_cumulative max_stale must be positive")
+}
+
+func TestInvalidTenant(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name: "endpoints without resource attribute",
+			modify: func(cfg *Config) {
+				cfg.Tenant.Endpoints = map[string]string{"tenant": "http://localhost:9009"}
+			},
+			err: "tenant endpoints require the tenant resource_attribute",
+		},
+		{
+			name: "wal",
+			modify: func(cfg *Config) {
+				cfg.Tenant.ResourceAttribute = "tenant.id"
+				cfg.WAL = &WALConfig{Directory: "wal"}
+			},
+			err: "wal can't be enabled with the tenant settings",
+		},
+		{
+			name: "static tenant header",
+			modify: func(cfg *Config) {
+				cfg.Tenant.ResourceAttribute = "tenant.id"
+				cfg.HTTPClientSettings.Headers = map[string]string{"x-scope-orgid": "tenant"}
+			},
+			err: "the X-Scope-OrgID header is set by the tenant settings",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func TestInvalidWAL(t *testing.T) {
+	tests := []struct {
+		name string
+		wal  *WALConfig
+		err  string
+	}{
+		{
+			name: "empty directory",
+			wal:  &WALConfig{},
+			err:  "wal directory can't be empty",
+		},
+		{
+			name: "negative segment size",
+			wal:  &WALConfig{Directory: "wal", SegmentSize: -1},
+			err:  "wal segment size can't be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.WAL = tt.wal
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
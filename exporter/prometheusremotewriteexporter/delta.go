@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// deltaAccumulator converts the delta sums and histograms to cumulative ones, by adding the points of each series
+// to the previous ones. The series that don't receive points for maxStale are forgotten, and restart from their next
+// point.
+type deltaAccumulator struct {
+	maxStale time.Duration
+
+	mu     sync.Mutex
+	series map[string]*accumulatedPoint
+	// lastExpiry is the last time the stale series were removed.
+	lastExpiry time.Time
+}
+
+// accumulatedPoint is the cumulative point of a series.
+type accumulatedPoint struct {
+	startTimestamp pdata.Timestamp
+	timestamp      pdata.Timestamp
+	lastSeen       time.Time
+
+	intValue    int64
+	doubleValue float64
+	count       uint64
+	bounds      []float64
+	buckets     []uint64
+}
+
+func newDeltaAccumulator(maxStale time.Duration) *deltaAccumulator {
+	return &deltaAccumulator{
+		maxStale: maxStale,
+		series:   map[string]*accumulatedPoint{},
+	}
+}
+
+// isDelta returns whether the metric is a delta sum or histogram.
+func isDelta(metric pdata.Metric) bool {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeDoubleSum:
+		return metric.DoubleSum().AggregationTemporality() == pdata.AggregationTemporalityDelta
+	case pdata.MetricDataTypeIntSum:
+		return metric.IntSum().AggregationTemporality() == pdata.AggregationTemporalityDelta
+	case pdata.MetricDataTypeHistogram:
+		return metric.Histogram().AggregationTemporality() == pdata.AggregationTemporalityDelta
+	case pdata.MetricDataTypeIntHistogram:
+		return metric.IntHistogram().AggregationTemporality() == pdata.AggregationTemporalityDelta
+	}
+	return false
+}
+
+// isEmpty returns whether the sum or histogram has no data points.
+func isEmpty(metric pdata.Metric) bool {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeDoubleSum:
+		return metric.DoubleSum().DataPoints().Len() == 0
+	case pdata.MetricDataTypeIntSum:
+		return metric.IntSum().DataPoints().Len() == 0
+	case pdata.MetricDataTypeHistogram:
+		return metric.Histogram().DataPoints().Len() == 0
+	case pdata.MetricDataTypeIntHistogram:
+		return metric.IntHistogram().DataPoints().Len() == 0
+	}
+	return false
+}
+
+// toCumulative returns the cumulative metric of the delta metric of the resource. The points older than the last
+// accumulated point of their series are dropped, Prometheus would refuse them as out of order.
+func (a *deltaAccumulator) toCumulative(resource pdata.Resource, delta pdata.Metric) pdata.Metric {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(a.lastExpiry) >= a.maxStale/2 {
+		for key, p := range a.series {
+			if now.Sub(p.lastSeen) > a.maxStale {
+				delete(a.series, key)
+			}
+		}
+		a.lastExpiry = now
+	}
+
+	metric := pdata.NewMetric()
+	delta.CopyTo(metric)
+	resourceSignature := attributesSignature(resource.Attributes())
+	key := func(labels pdata.StringMap) string {
+		return metric.DataType().String() + "\xff" + metric.Name() + "\xff" + resourceSignature + "\xff" + labelsSignature(labels)
+	}
+
+	switch metric.DataType() {
+	case pdata.MetricDataTypeDoubleSum:
+		metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		metric.DoubleSum().DataPoints().RemoveIf(func(dp pdata.DoubleDataPoint) bool {
+			p, ok := a.accumulate(key(dp.LabelsMap()), dp.StartTimestamp(), dp.Timestamp(), now)
+			if !ok {
+				return true
+			}
+			p.doubleValue += dp.Value()
+			dp.SetStartTimestamp(p.startTimestamp)
+			dp.SetValue(p.doubleValue)
+			return false
+		})
+	case pdata.MetricDataTypeIntSum:
+		metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		metric.IntSum().DataPoints().RemoveIf(func(dp pdata.IntDataPoint) bool {
+			p, ok := a.accumulate(key(dp.LabelsMap()), dp.StartTimestamp(), dp.Timestamp(), now)
+			if !ok {
+				return true
+			}
+			p.intValue += dp.Value()
+			dp.SetStartTimestamp(p.startTimestamp)
+			dp.SetValue(p.intValue)
+			return false
+		})
+	case pdata.MetricDataTypeHistogram:
+		metric.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		metric.Histogram().DataPoints().RemoveIf(func(dp pdata.HistogramDataPoint) bool {
+			p, ok := a.accumulateHistogram(key(dp.LabelsMap()), dp.StartTimestamp(), dp.Timestamp(), now, dp.ExplicitBounds(), dp.BucketCounts())
+			if !ok {
+				return true
+			}
+			p.doubleValue += dp.Sum()
+			p.count += dp.Count()
+			dp.SetStartTimestamp(p.startTimestamp)
+			dp.SetSum(p.doubleValue)
+			dp.SetCount(p.count)
+			dp.SetBucketCounts(append([]uint64(nil), p.buckets...))
+			return false
+		})
+	case pdata.MetricDataTypeIntHistogram:
+		metric.IntHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		metric.IntHistogram().DataPoints().RemoveIf(func(dp pdata.IntHistogramDataPoint) bool {
+			p, ok := a.accumulateHistogram(key(dp.LabelsMap()), dp.StartTimestamp(), dp.Timestamp(), now, dp.ExplicitBounds(), dp.BucketCounts())
+			if !ok {
+				return true
+			}
+			p.intValue += dp.Sum()
+			p.count += dp.Count()
+			dp.SetStartTimestamp(p.startTimestamp)
+			dp.SetSum(p.intValue)
+			dp.SetCount(p.count)
+			dp.SetBucketCounts(append([]uint64(nil), p.buckets...))
+			return false
+		})
+	}
+	return metric
+}
+
+// accumulate returns the accumulated point of the series with the key, to add the delta point from start to ts to.
+// It returns false if the point is older than the accumulated one.
+func (a *deltaAccumulator) accumulate(key string, start, ts pdata.Timestamp, now time.Time) (*accumulatedPoint, bool) {
+	p, ok := a.series[key]
+	if !ok {
+		p = &accumulatedPoint{startTimestamp: start}
+		a.series[key] = p
+	} else if ts <= p.timestamp {
+		return nil, false
+	}
+	p.timestamp = ts
+	p.lastSeen = now
+	return p, true
+}
+
+// accumulateHistogram is accumulate for histograms, whose buckets are added to the accumulated ones. The series is
+// restarted if the bounds change.
+func (a *deltaAccumulator) accumulateHistogram(key string, start, ts pdata.Timestamp, now time.Time, bounds []float64, buckets []uint64) (*accumulatedPoint, bool) {
+	if p, ok := a.series[key]; ok && (!equalBounds(p.bounds, bounds) || len(p.buckets) != len(buckets)) {
+		delete(a.series, key)
+	}
+	p, ok := a.accumulate(key, start, ts, now)
+	if !ok {
+		return nil, false
+	}
+	if p.buckets == nil {
+		p.bounds = append([]float64(nil), bounds...)
+		p.buckets = make([]uint64, len(buckets))
+	}
+	for i, count := range buckets {
+		p.buckets[i] += count
+	}
+	return p, true
+}
+
+func equalBounds(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// attributesSignature returns a signature of the attributes, sorted by key.
+func attributesSignature(attrs pdata.AttributeMap) string {
+	kvs := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		kvs = append(kvs, k+"\xff"+tracetranslator.AttributeValueToString(v))
+		return true
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\xff")
+}
+
+// labelsSignature returns a signature of the labels, sorted by key.
+func labelsSignature(labels pdata.StringMap) string {
+	kvs := make([]string, 0, labels.Len())
+	labels.Range(func(k string, v string) bool {
+		kvs = append(kvs, k+"\xff"+v)
+		return true
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\xff")
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func getDeltaDoubleSumMetric(name string, value float64, start, ts uint64) pdata.Metric {
+	metric := getDoubleSumMetric(name, getLabels("label", "value"), value, ts)
+	metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	metric.DoubleSum().DataPoints().At(0).SetStartTimestamp(pdata.Timestamp(start))
+	return metric
+}
+
+func getDeltaHistogramMetric(ts uint64, bounds []float64, buckets []uint64) pdata.Metric {
+	count := uint64(0)
+	for _, b := range buckets {
+		count += b
+	}
+	metric := getHistogramMetric("histogram", getLabels("label", "value"), ts, float64(count), count, bounds, buckets)
+	metric.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	return metric
+}
+
+func Test_isDelta(t *testing.T) {
+	assert.True(t, isDelta(getDeltaDoubleSumMetric("sum", 1, 0, 1)))
+	assert.True(t, isDelta(getDeltaHistogramMetric(1, []float64{1}, []uint64{1, 1})))
+	assert.False(t, isDelta(getDoubleSumMetric("sum", getLabels(), 1, 1)))
+	assert.False(t, isDelta(getDoubleGaugeMetric("gauge", getLabels(), 1, 1)))
+}
+
+func Test_toCumulativeSum(t *testing.T) {
+	a := newDeltaAccumulator(time.Minute)
+	resource := getResource("job", "test")
+
+	metric := a.toCumulative(resource, getDeltaDoubleSumMetric("sum", 1, 10, 20))
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, metric.DoubleSum().AggregationTemporality())
+	require.Equal(t, 1, metric.DoubleSum().DataPoints().Len())
+	dp := metric.DoubleSum().DataPoints().At(0)
+	assert.Equal(t, 1.0, dp.Value())
+	assert.Equal(t, pdata.Timestamp(10), dp.StartTimestamp())
+
+	metric = a.toCumulative(resource, getDeltaDoubleSumMetric("sum", 2, 20, 30))
+	require.Equal(t, 1, metric.DoubleSum().DataPoints().Len())
+	dp = metric.DoubleSum().DataPoints().At(0)
+	assert.Equal(t, 3.0, dp.Value())
+	assert.Equal(t, pdata.Timestamp(10), dp.StartTimestamp())
+	assert.Equal(t, pdata.Timestamp(30), dp.Timestamp())
+
+	// Out of order points are dropped.
+	metric = a.toCumulative(resource, getDeltaDoubleSumMetric("sum", 5, 10, 20))
+	assert.True(t, isEmpty(metric))
+
+	// The series of other resources are accumulated separately.
+	metric = a.toCumulative(getResource("job", "other"), getDeltaDoubleSumMetric("sum", 5, 10, 20))
+	require.Equal(t, 1, metric.DoubleSum().DataPoints().Len())
+	assert.Equal(t, 5.0, metric.DoubleSum().DataPoints().At(0).Value())
+}
+
+func Test_toCumulativeHistogram(t *testing.T) {
+	a := newDeltaAccumulator(time.Minute)
+	resource := getResource("job", "test")
+
+	a.toCumulative(resource, getDeltaHistogramMetric(10, []float64{1, 2}, []uint64{1, 2, 3}))
+	metric := a.toCumulative(resource, getDeltaHistogramMetric(20, []float64{1, 2}, []uint64{1, 1, 1}))
+	require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+	dp := metric.Histogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{2, 3, 4}, dp.BucketCounts())
+	assert.Equal(t, uint64(9), dp.Count())
+	assert.Equal(t, 9.0, dp.Sum())
+
+	// The series restarts when the bounds change.
+	metric = a.toCumulative(resource, getDeltaHistogramMetric(30, []float64{1}, []uint64{1, 1}))
+	require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+	dp = metric.Histogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{1, 1}, dp.BucketCounts())
+	assert.Equal(t, uint64(2), dp.Count())
+}
+
+func Test_toCumulativeExpiry(t *testing.T) {
+	a := newDeltaAccumulator(time.Minute)
+	resource := getResource("job", "test")
+
+	a.toCumulative(resource, getDeltaDoubleSumMetric("sum", 1, 10, 20))
+	for _, p := range a.series {
+		p.lastSeen = p.lastSeen.Add(-2 * time.Minute)
+	}
+	a.lastExpiry = time.Time{}
+
+	metric := a.toCumulative(resource, getDeltaDoubleSumMetric("sum", 2, 20, 30))
+	require.Equal(t, 1, metric.DoubleSum().DataPoints().Len())
+	dp := metric.DoubleSum().DataPoints().At(0)
+	assert.Equal(t, 2.0, dp.Value())
+	assert.Equal(t, pdata.Timestamp(20), dp.StartTimestamp())
+}
@@ -25,16 +25,24 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/prompb"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
@@ -42,6 +50,7 @@ const maxBatchByteSize = 3000000
 
 // PRWExporter converts OTLP metrics to Prometheus remote write TimeSeries and sends them to a remote endpoint.
 type PRWExporter struct {
+	id              config.ComponentID
 	namespace       string
 	externalLabels  map[string]string
 	endpointURL     *url.URL
@@ -51,10 +60,27 @@ type PRWExporter struct {
 	concurrency     int
 	userAgentHeader string
 	clientSettings  *confighttp.HTTPClientSettings
+	logger          *zap.Logger
+	sendMetadata    bool
+	// accumulator converts the delta metrics to cumulative ones, if enabled.
+	accumulator *deltaAccumulator
+
+	// tenant configures the split of the metrics by tenant, nil if disabled. tenantEndpointURLs are the endpoints of
+	// the tenants that are not sent to endpointURL.
+	tenant             *TenantSettings
+	tenantEndpointURLs map[string]*url.URL
+
+	walConfig     *WALConfig
+	retrySettings exporterhelper.RetrySettings
+	// wal is the write-ahead log of the requests if enabled, walCancel stops sendWAL and walDone is closed once it
+	// returned.
+	wal       *wal
+	walCancel context.CancelFunc
+	walDone   chan struct{}
 }
 
 // NewPRWExporter initializes a new PRWExporter instance and sets fields accordingly.
-func NewPRWExporter(cfg *Config, buildInfo component.BuildInfo) (*PRWExporter, error) {
+func NewPRWExporter(cfg *Config, set component.ExporterCreateSettings) (*PRWExporter, error) {
 	sanitizedLabels, err := validateAndSanitizeExternalLabels(cfg.ExternalLabels)
 	if err != nil {
 		return nil, err
@@ -65,37 +91,83 @@ func NewPRWExporter(cfg *Config, buildInfo component.BuildInfo) (*PRWExporter, e
 		return nil, errors.New("invalid endpoint")
 	}
 
-	userAgentHeader := fmt.Sprintf("%s/%s", strings.ReplaceAll(strings.ToLower(buildInfo.Description), " ", "-"), buildInfo.Version)
+	var tenant *TenantSettings
+	tenantEndpointURLs := map[string]*url.URL{}
+	if cfg.Tenant.ResourceAttribute != "" {
+		tenant = &cfg.Tenant
+		for name, endpoint := range cfg.Tenant.Endpoints {
+			if tenantEndpointURLs[name], err = url.ParseRequestURI(endpoint); err != nil {
+				return nil, fmt.Errorf("invalid endpoint of tenant %q", name)
+			}
+		}
+	}
+
+	var accumulator *deltaAccumulator
+	if cfg.DeltaToCumulative.Enabled {
+		accumulator = newDeltaAccumulator(cfg.DeltaToCumulative.MaxStale)
+	}
+
+	userAgentHeader := fmt.Sprintf("%s/%s", strings.ReplaceAll(strings.ToLower(set.BuildInfo.Description), " ", "-"), set.BuildInfo.Version)
 
 	return &PRWExporter{
-		namespace:       cfg.Namespace,
-		externalLabels:  sanitizedLabels,
-		endpointURL:     endpointURL,
-		wg:              new(sync.WaitGroup),
-		closeChan:       make(chan struct{}),
-		userAgentHeader: userAgentHeader,
-		concurrency:     cfg.RemoteWriteQueue.NumConsumers,
-		clientSettings:  &cfg.HTTPClientSettings,
+		id:                 cfg.ID(),
+		namespace:          cfg.Namespace,
+		externalLabels:     sanitizedLabels,
+		endpointURL:        endpointURL,
+		wg:                 new(sync.WaitGroup),
+		closeChan:          make(chan struct{}),
+		userAgentHeader:    userAgentHeader,
+		concurrency:        cfg.RemoteWriteQueue.NumConsumers,
+		clientSettings:     &cfg.HTTPClientSettings,
+		logger:             set.Logger,
+		sendMetadata:       cfg.SendMetadata,
+		accumulator:        accumulator,
+		tenant:             tenant,
+		tenantEndpointURLs: tenantEndpointURLs,
+		walConfig:          cfg.WAL,
+		retrySettings:      cfg.RetrySettings,
 	}, nil
 }
 
-// Start creates the prometheus client
+// Start creates the prometheus client, and opens the WAL if enabled.
 func (prwe *PRWExporter) Start(_ context.Context, host component.Host) (err error) {
 	prwe.client, err = prwe.clientSettings.ToClient(host.GetExtensions())
-	return err
+	if err != nil || prwe.walConfig == nil {
+		return err
+	}
+
+	prwe.wal, err = openWAL(prwe.walConfig.Directory, prwe.walConfig.SegmentSize)
+	if err != nil {
+		return fmt.Errorf("failed to open the WAL: %w", err)
+	}
+	reader, err := prwe.wal.newReader()
+	if err != nil {
+		_ = prwe.wal.close()
+		return fmt.Errorf("failed to read the WAL: %w", err)
+	}
+	var ctx context.Context
+	ctx, prwe.walCancel = context.WithCancel(context.Background())
+	prwe.walDone = make(chan struct{})
+	go prwe.sendWAL(ctx, reader)
+	return nil
 }
 
 // Shutdown stops the exporter from accepting incoming calls(and return error), and wait for current export operations
-// to finish before returning
+// to finish before returning. The requests of the WAL that are not sent yet are sent after the next start.
 func (prwe *PRWExporter) Shutdown(context.Context) error {
 	close(prwe.closeChan)
 	prwe.wg.Wait()
-	return nil
+	if prwe.wal == nil {
+		return nil
+	}
+	prwe.walCancel()
+	<-prwe.walDone
+	return prwe.wal.close()
 }
 
-// PushMetrics converts metrics to Prometheus remote write TimeSeries and send to remote endpoint. It maintain a map of
-// TimeSeries, validates and handles each individual metric, adding the converted TimeSeries to the map, and finally
-// exports the map.
+// PushMetrics converts metrics to Prometheus remote write TimeSeries and send to remote endpoint. If the tenant
+// settings are enabled, the metrics of each tenant are sent concurrently, and only the metrics of the tenants that
+// failed with a retryable error are retried.
 func (prwe *PRWExporter) PushMetrics(ctx context.Context, md pdata.Metrics) error {
 	prwe.wg.Add(1)
 	defer prwe.wg.Done()
@@ -104,102 +176,187 @@ func (prwe *PRWExporter) PushMetrics(ctx context.Context, md pdata.Metrics) erro
 	case <-prwe.closeChan:
 		return errors.New("shutdown has been called")
 	default:
-		tsMap := map[string]*prompb.TimeSeries{}
-		dropped := 0
-		var errs []error
-		resourceMetricsSlice := md.ResourceMetrics()
-		for i := 0; i < resourceMetricsSlice.Len(); i++ {
-			resourceMetrics := resourceMetricsSlice.At(i)
-			resource := resourceMetrics.Resource()
-			instrumentationLibraryMetricsSlice := resourceMetrics.InstrumentationLibraryMetrics()
-			// TODO: add resource attributes as labels, probably in next PR
-			for j := 0; j < instrumentationLibraryMetricsSlice.Len(); j++ {
-				instrumentationLibraryMetrics := instrumentationLibraryMetricsSlice.At(j)
-				metricSlice := instrumentationLibraryMetrics.Metrics()
-
-				// TODO: decide if instrumentation library information should be exported as labels
-				for k := 0; k < metricSlice.Len(); k++ {
-					metric := metricSlice.At(k)
-
-					// check for valid type and temporality combination and for matching data field and type
-					if ok := validateMetrics(metric); !ok {
-						dropped++
-						errs = append(errs, consumererror.Permanent(errors.New("invalid temporality and type combination")))
+	}
+
+	if prwe.tenant == nil {
+		return prwe.pushTenantMetrics(ctx, "", md)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var errs []error
+	retryable := false
+	failed := pdata.NewMetrics()
+	for tenant, tenantMD := range splitByTenant(md, prwe.tenant.ResourceAttribute, prwe.tenant.Default) {
+		wg.Add(1)
+		go func(tenant string, tenantMD pdata.Metrics) {
+			defer wg.Done()
+			err := prwe.pushTenantMetrics(ctx, tenant, tenantMD)
+			if err == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, fmt.Errorf("tenant %q: %w", tenant, err))
+			if !consumererror.IsPermanent(err) {
+				retryable = true
+				tenantMD.ResourceMetrics().MoveAndAppendTo(failed.ResourceMetrics())
+			}
+		}(tenant, tenantMD)
+	}
+	wg.Wait()
+
+	if !retryable {
+		return consumererror.Combine(errs)
+	}
+	// The metrics of the tenants refused with a permanent error are not retried, they are only reported.
+	for _, err := range errs {
+		if consumererror.IsPermanent(err) {
+			prwe.logger.Error("Dropping the tenant metrics refused by the remote write endpoint", zap.Error(err))
+		}
+	}
+	return consumererror.NewMetrics(consumererror.Combine(retryableErrors(errs)), failed)
+}
+
+// retryableErrors returns the errors of errs that are not permanent.
+func retryableErrors(errs []error) []error {
+	var retryable []error
+	for _, err := range errs {
+		if !consumererror.IsPermanent(err) {
+			retryable = append(retryable, err)
+		}
+	}
+	return retryable
+}
+
+// pushTenantMetrics converts the metrics of the tenant to Prometheus remote write TimeSeries and sends them. It
+// maintains a map of TimeSeries, validates and handles each individual metric, adding the converted TimeSeries to the
+// map, and finally exports the map.
+func (prwe *PRWExporter) pushTenantMetrics(ctx context.Context, tenant string, md pdata.Metrics) error {
+	tsMap := map[string]*prompb.TimeSeries{}
+	metadata := map[string]prompb.MetricMetadata{}
+	dropped := 0
+	// accumulated is the number of delta metrics whose points were all already accumulated.
+	accumulated := 0
+	var errs []error
+	resourceMetricsSlice := md.ResourceMetrics()
+	for i := 0; i < resourceMetricsSlice.Len(); i++ {
+		resourceMetrics := resourceMetricsSlice.At(i)
+		resource := resourceMetrics.Resource()
+		instrumentationLibraryMetricsSlice := resourceMetrics.InstrumentationLibraryMetrics()
+		// TODO: add resource attributes as labels, probably in next PR
+		for j := 0; j < instrumentationLibraryMetricsSlice.Len(); j++ {
+			instrumentationLibraryMetrics := instrumentationLibraryMetricsSlice.At(j)
+			metricSlice := instrumentationLibraryMetrics.Metrics()
+
+			// TODO: decide if instrumentation library information should be exported as labels
+			for k := 0; k < metricSlice.Len(); k++ {
+				metric := metricSlice.At(k)
+
+				if prwe.accumulator != nil && isDelta(metric) {
+					metric = prwe.accumulator.toCumulative(resource, metric)
+					if isEmpty(metric) {
+						// All the points are older than the accumulated ones, e.g. when the metrics are retried.
+						accumulated++
 						continue
 					}
+				}
 
-					// handle individual metric based on type
-					switch metric.DataType() {
-					case pdata.MetricDataTypeDoubleGauge:
-						dataPoints := metric.DoubleGauge().DataPoints()
-						if err := prwe.addDoubleDataPointSlice(dataPoints, tsMap, resource, metric); err != nil {
-							dropped++
-							errs = append(errs, err)
-						}
-					case pdata.MetricDataTypeIntGauge:
-						dataPoints := metric.IntGauge().DataPoints()
-						if err := prwe.addIntDataPointSlice(dataPoints, tsMap, resource, metric); err != nil {
-							dropped++
-							errs = append(errs, err)
-						}
-					case pdata.MetricDataTypeDoubleSum:
-						dataPoints := metric.DoubleSum().DataPoints()
-						if err := prwe.addDoubleDataPointSlice(dataPoints, tsMap, resource, metric); err != nil {
-							dropped++
-							errs = append(errs, err)
-						}
-					case pdata.MetricDataTypeIntSum:
-						dataPoints := metric.IntSum().DataPoints()
-						if err := prwe.addIntDataPointSlice(dataPoints, tsMap, resource, metric); err != nil {
-							dropped++
-							errs = append(errs, err)
-						}
-					case pdata.MetricDataTypeIntHistogram:
-						dataPoints := metric.IntHistogram().DataPoints()
-						if dataPoints.Len() == 0 {
-							dropped++
-							errs = append(errs, consumererror.Permanent(fmt.Errorf("empty data points. %s is dropped", metric.Name())))
-						}
-						for x := 0; x < dataPoints.Len(); x++ {
-							addSingleIntHistogramDataPoint(dataPoints.At(x), resource, metric, prwe.namespace, tsMap, prwe.externalLabels)
-						}
-					case pdata.MetricDataTypeHistogram:
-						dataPoints := metric.Histogram().DataPoints()
-						if dataPoints.Len() == 0 {
-							dropped++
-							errs = append(errs, consumererror.Permanent(fmt.Errorf("empty data points. %s is dropped", metric.Name())))
-						}
-						for x := 0; x < dataPoints.Len(); x++ {
-							addSingleHistogramDataPoint(dataPoints.At(x), resource, metric, prwe.namespace, tsMap, prwe.externalLabels)
-						}
-					case pdata.MetricDataTypeSummary:
-						dataPoints := metric.Summary().DataPoints()
-						if dataPoints.Len() == 0 {
-							dropped++
-							errs = append(errs, consumererror.Permanent(fmt.Errorf("empty data points. %s is dropped", metric.Name())))
-						}
-						for x := 0; x < dataPoints.Len(); x++ {
-							addSingleSummaryDataPoint(dataPoints.At(x), resource, metric, prwe.namespace, tsMap, prwe.externalLabels)
-						}
-					default:
+				// check for valid type and temporality combination and for matching data field and type
+				if ok := validateMetrics(metric); !ok {
+					dropped++
+					errs = append(errs, consumererror.Permanent(errors.New("invalid temporality and type combination")))
+					continue
+				}
+
+				if prwe.sendMetadata {
+					name := getPromMetricName(metric, prwe.namespace)
+					metadata[name] = metricMetadata(metric, name)
+				}
+
+				// handle individual metric based on type
+				switch metric.DataType() {
+				case pdata.MetricDataTypeDoubleGauge:
+					dataPoints := metric.DoubleGauge().DataPoints()
+					if err := prwe.addDoubleDataPointSlice(dataPoints, tsMap, resource, metric); err != nil {
+						dropped++
+						errs = append(errs, err)
+					}
+				case pdata.MetricDataTypeIntGauge:
+					dataPoints := metric.IntGauge().DataPoints()
+					if err := prwe.addIntDataPointSlice(dataPoints, tsMap, resource, metric); err != nil {
+						dropped++
+						errs = append(errs, err)
+					}
+				case pdata.MetricDataTypeDoubleSum:
+					dataPoints := metric.DoubleSum().DataPoints()
+					if err := prwe.addDoubleDataPointSlice(dataPoints, tsMap, resource, metric); err != nil {
 						dropped++
-						errs = append(errs, consumererror.Permanent(errors.New("unsupported metric type")))
+						errs = append(errs, err)
 					}
+				case pdata.MetricDataTypeIntSum:
+					dataPoints := metric.IntSum().DataPoints()
+					if err := prwe.addIntDataPointSlice(dataPoints, tsMap, resource, metric); err != nil {
+						dropped++
+						errs = append(errs, err)
+					}
+				case pdata.MetricDataTypeIntHistogram:
+					dataPoints := metric.IntHistogram().DataPoints()
+					if dataPoints.Len() == 0 {
+						dropped++
+						errs = append(errs, consumererror.Permanent(fmt.Errorf("empty data points. %s is dropped", metric.Name())))
+					}
+					for x := 0; x < dataPoints.Len(); x++ {
+						addSingleIntHistogramDataPoint(dataPoints.At(x), resource, metric, prwe.namespace, tsMap, prwe.externalLabels)
+					}
+				case pdata.MetricDataTypeHistogram:
+					dataPoints := metric.Histogram().DataPoints()
+					if dataPoints.Len() == 0 {
+						dropped++
+						errs = append(errs, consumererror.Permanent(fmt.Errorf("empty data points. %s is dropped", metric.Name())))
+					}
+					for x := 0; x < dataPoints.Len(); x++ {
+						addSingleHistogramDataPoint(dataPoints.At(x), resource, metric, prwe.namespace, tsMap, prwe.externalLabels)
+					}
+				case pdata.MetricDataTypeSummary:
+					dataPoints := metric.Summary().DataPoints()
+					if dataPoints.Len() == 0 {
+						dropped++
+						errs = append(errs, consumererror.Permanent(fmt.Errorf("empty data points. %s is dropped", metric.Name())))
+					}
+					for x := 0; x < dataPoints.Len(); x++ {
+						addSingleSummaryDataPoint(dataPoints.At(x), resource, metric, prwe.namespace, tsMap, prwe.externalLabels)
+					}
+				default:
+					dropped++
+					errs = append(errs, consumererror.Permanent(errors.New("unsupported metric type")))
 				}
 			}
 		}
+	}
 
-		if exportErrors := prwe.export(ctx, tsMap); len(exportErrors) != 0 {
-			dropped = md.MetricCount()
-			errs = append(errs, exportErrors...)
-		}
+	if len(tsMap) == 0 && accumulated != 0 && dropped == 0 {
+		return nil
+	}
 
-		if dropped != 0 {
-			return consumererror.Combine(errs)
-		}
+	export := prwe.export
+	if prwe.wal != nil {
+		export = prwe.appendToWAL
+	}
+	exportErrors := export(ctx, tenant, tsMap, sortedMetadata(metadata))
+	if len(exportErrors) != 0 {
+		dropped = md.MetricCount()
+		errs = append(errs, exportErrors...)
+	}
+	if prwe.tenant != nil {
+		prwe.recordTenantSamples(ctx, tenant, tsMap, len(exportErrors) == 0)
+	}
 
-		return nil
+	if dropped != 0 {
+		return consumererror.Combine(errs)
 	}
+
+	return nil
 }
 
 func validateAndSanitizeExternalLabels(externalLabels map[string]string) (map[string]string, error) {
@@ -241,11 +398,34 @@ func (prwe *PRWExporter) addDoubleDataPointSlice(dataPoints pdata.DoubleDataPoin
 	return nil
 }
 
+// sortedMetadata returns the metadata sorted by metric family name.
+func sortedMetadata(metadata map[string]prompb.MetricMetadata) []prompb.MetricMetadata {
+	if len(metadata) == 0 {
+		return nil
+	}
+	sorted := make([]prompb.MetricMetadata, 0, len(metadata))
+	for _, m := range metadata {
+		sorted = append(sorted, m)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MetricFamilyName < sorted[j].MetricFamilyName })
+	return sorted
+}
+
+// batchRequests converts and batches the TsMap to WriteRequests, the metadata are sent with the first one.
+func batchRequests(tsMap map[string]*prompb.TimeSeries, metadata []prompb.MetricMetadata) ([]*prompb.WriteRequest, error) {
+	requests, err := batchTimeSeries(tsMap, maxBatchByteSize)
+	if err != nil {
+		return nil, err
+	}
+	requests[0].Metadata = metadata
+	return requests, nil
+}
+
 // export sends a Snappy-compressed WriteRequest containing TimeSeries to a remote write endpoint in order
-func (prwe *PRWExporter) export(ctx context.Context, tsMap map[string]*prompb.TimeSeries) []error {
+func (prwe *PRWExporter) export(ctx context.Context, tenant string, tsMap map[string]*prompb.TimeSeries, metadata []prompb.MetricMetadata) []error {
 	var errs []error
 	// Calls the helper function to convert and batch the TsMap to the desired format
-	requests, err := batchTimeSeries(tsMap, maxBatchByteSize)
+	requests, err := batchRequests(tsMap, metadata)
 	if err != nil {
 		errs = append(errs, consumererror.Permanent(err))
 		return errs
@@ -270,7 +450,7 @@ func (prwe *PRWExporter) export(ctx context.Context, tsMap map[string]*prompb.Ti
 			defer wg.Done()
 
 			for request := range input {
-				err := prwe.execute(ctx, request)
+				err := prwe.execute(ctx, tenant, request)
 				if err != nil {
 					mu.Lock()
 					errs = append(errs, err)
@@ -284,17 +464,135 @@ func (prwe *PRWExporter) export(ctx context.Context, tsMap map[string]*prompb.Ti
 	return errs
 }
 
-func (prwe *PRWExporter) execute(ctx context.Context, writeReq *prompb.WriteRequest) error {
+// appendToWAL appends the WriteRequests containing TimeSeries to the WAL, they are sent by sendWAL.
+func (prwe *PRWExporter) appendToWAL(_ context.Context, _ string, tsMap map[string]*prompb.TimeSeries, metadata []prompb.MetricMetadata) []error {
+	requests, err := batchRequests(tsMap, metadata)
+	if err != nil {
+		return []error{consumererror.Permanent(err)}
+	}
+	encoded := make([][]byte, 0, len(requests))
+	for _, request := range requests {
+		data, err := encodeWriteRequest(request)
+		if err != nil {
+			return []error{consumererror.Permanent(err)}
+		}
+		encoded = append(encoded, data)
+	}
+	if err := prwe.wal.append(encoded); err != nil {
+		return []error{fmt.Errorf("failed to write to the WAL: %w", err)}
+	}
+	return nil
+}
+
+// sendWAL sends the requests of the WAL in order until ctx is canceled. The requests refused with a permanent error
+// are dropped, the others are retried until they are delivered, whatever the max_elapsed_time retry setting.
+func (prwe *PRWExporter) sendWAL(ctx context.Context, reader *walReader) {
+	defer close(prwe.walDone)
+	defer reader.close()
+
+	expBackoff := backoff.NewExponentialBackOff()
+	if prwe.retrySettings.InitialInterval > 0 {
+		expBackoff.InitialInterval = prwe.retrySettings.InitialInterval
+	}
+	if prwe.retrySettings.MaxInterval > 0 {
+		expBackoff.MaxInterval = prwe.retrySettings.MaxInterval
+	}
+	expBackoff.MaxElapsedTime = 0
+	wait := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(expBackoff.NextBackOff()):
+			return true
+		}
+	}
+
+	for {
+		data, err := reader.read(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, errWALClosed) {
+				return
+			}
+			prwe.logger.Error("Failed to read the WAL", zap.Error(err))
+			if !wait() {
+				return
+			}
+			continue
+		}
+
+		expBackoff.Reset()
+		for {
+			err = prwe.send(ctx, "", data)
+			if err == nil || consumererror.IsPermanent(err) || ctx.Err() != nil {
+				break
+			}
+			prwe.logger.Warn("Failed to send the WAL request, will retry", zap.Error(err))
+			if !wait() {
+				return
+			}
+		}
+		if ctx.Err() != nil {
+			// The request is sent again after the next start.
+			return
+		}
+		if err != nil {
+			prwe.logger.Error("Dropping the WAL request refused by the remote write endpoint", zap.Error(err))
+		}
+		if err = reader.commit(); err != nil {
+			prwe.logger.Error("Failed to write the WAL checkpoint", zap.Error(err))
+		}
+	}
+}
+
+// recordTenantSamples records the number of samples of tsMap sent, or failed to be sent, for the tenant.
+func (prwe *PRWExporter) recordTenantSamples(ctx context.Context, tenant string, tsMap map[string]*prompb.TimeSeries, sent bool) {
+	samples := 0
+	for _, ts := range tsMap {
+		samples += len(ts.Samples)
+	}
+	measure := statTenantSentSamples
+	if !sent {
+		measure = statTenantFailedSamples
+	}
+	statsTags := []tag.Mutator{tag.Insert(tagExporterName, prwe.id.String()), tag.Insert(tagTenant, tenant)}
+	_ = stats.RecordWithTags(ctx, statsTags, measure.M(int64(samples)))
+}
+
+func (prwe *PRWExporter) execute(ctx context.Context, tenant string, writeReq *prompb.WriteRequest) error {
+	data, err := encodeWriteRequest(writeReq)
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+	err = prwe.send(ctx, tenant, data)
+	// The HTTP client errors are not retried by the exporter helper, unlike the 5xx responses.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return consumererror.Permanent(err)
+	}
+	return err
+}
+
+// encodeWriteRequest returns the Snappy-compressed protobuf encoding of writeReq.
+func encodeWriteRequest(writeReq *prompb.WriteRequest) ([]byte, error) {
 	// Uses proto.Marshal to convert the WriteRequest into bytes array
 	data, err := proto.Marshal(writeReq)
 	if err != nil {
-		return consumererror.Permanent(err)
+		return nil, err
 	}
 	buf := make([]byte, len(data), cap(data))
-	compressedData := snappy.Encode(buf, data)
+	return snappy.Encode(buf, data), nil
+}
+
+// send sends the encoded WriteRequest of the tenant to the remote write endpoint, the tenant is empty if the metrics
+// are not split by tenant. The errors of the HTTP client, e.g. when the endpoint is unreachable, are returned as is.
+func (prwe *PRWExporter) send(ctx context.Context, tenant string, compressedData []byte) error {
+	endpointURL := prwe.endpointURL
+	if tenantURL, ok := prwe.tenantEndpointURLs[tenant]; ok {
+		endpointURL = tenantURL
+	}
 
 	// Create the HTTP POST request to send to the endpoint
-	req, err := http.NewRequestWithContext(ctx, "POST", prwe.endpointURL.String(), bytes.NewReader(compressedData))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpointURL.String(), bytes.NewReader(compressedData))
 	if err != nil {
 		return consumererror.Permanent(err)
 	}
@@ -305,15 +603,18 @@ func (prwe *PRWExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 	req.Header.Set("Content-Type", "application/x-protobuf")
 	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
 	req.Header.Set("User-Agent", prwe.userAgentHeader)
+	if prwe.tenant != nil && prwe.tenant.Header != "" && tenant != "" {
+		req.Header.Set(prwe.tenant.Header, tenant)
+	}
 
 	resp, err := prwe.client.Do(req)
 	if err != nil {
-		return consumererror.Permanent(err)
+		return err
 	}
 	defer resp.Body.Close()
 
 	// 2xx status code is considered a success
-	// 5xx errors are recoverable and the exporter should retry
+	// 5xx and 429 errors are recoverable and the exporter should retry
 	// Reference for different behavior according to status code:
 	// https://github.com/prometheus/prometheus/pull/2552/files#diff-ae8db9d16d8057358e49d694522e7186
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
@@ -321,7 +622,7 @@ func (prwe *PRWExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 	}
 	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 256))
 	rerr := fmt.Errorf("remote write returned HTTP status %v; err = %v: %s", resp.Status, err, body)
-	if resp.StatusCode >= 500 && resp.StatusCode < 600 {
+	if resp.StatusCode >= 500 && resp.StatusCode < 600 || resp.StatusCode == http.StatusTooManyRequests {
 		return rerr
 	}
 	return consumererror.Permanent(rerr)
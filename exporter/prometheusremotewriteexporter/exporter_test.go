@@ -22,12 +22,14 @@ import (
 	"net/url"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -110,7 +112,7 @@ func Test_NewPRWExporter(t *testing.T) {
 			cfg.ExternalLabels = tt.externalLabels
 			cfg.Namespace = tt.namespace
 			cfg.RemoteWriteQueue.NumConsumers = 1
-			prwe, err := NewPRWExporter(cfg, tt.buildInfo)
+			prwe, err := NewPRWExporter(cfg, component.ExporterCreateSettings{Logger: zap.NewNop(), BuildInfo: tt.buildInfo})
 
 			if tt.returnErrorOnCreate {
 				assert.Error(t, err)
@@ -191,7 +193,7 @@ func Test_Start(t *testing.T) {
 			cfg.RemoteWriteQueue.NumConsumers = 1
 			cfg.HTTPClientSettings = tt.clientSettings
 
-			prwe, err := NewPRWExporter(cfg, tt.buildInfo)
+			prwe, err := NewPRWExporter(cfg, component.ExporterCreateSettings{Logger: zap.NewNop(), BuildInfo: tt.buildInfo})
 			assert.NoError(t, err)
 			assert.NotNil(t, prwe)
 
@@ -333,7 +335,7 @@ func runExportPipeline(ts *prompb.TimeSeries, endpoint *url.URL) []error {
 		Version:     "1.0",
 	}
 	// after this, instantiate a CortexExporter with the current HTTP client and endpoint set to passed in endpoint
-	prwe, err := NewPRWExporter(cfg, buildInfo)
+	prwe, err := NewPRWExporter(cfg, component.ExporterCreateSettings{Logger: zap.NewNop(), BuildInfo: buildInfo})
 	if err != nil {
 		errs = append(errs, err)
 		return errs
@@ -344,7 +346,7 @@ func runExportPipeline(ts *prompb.TimeSeries, endpoint *url.URL) []error {
 		return errs
 	}
 
-	errs = append(errs, prwe.export(context.Background(), testmap)...)
+	errs = append(errs, prwe.export(context.Background(), "", testmap, nil)...)
 	return errs
 }
 
@@ -591,7 +593,7 @@ func Test_PushMetrics(t *testing.T) {
 				Description: "OpenTelemetry Collector",
 				Version:     "1.0",
 			}
-			prwe, nErr := NewPRWExporter(cfg, buildInfo)
+			prwe, nErr := NewPRWExporter(cfg, component.ExporterCreateSettings{Logger: zap.NewNop(), BuildInfo: buildInfo})
 			require.NoError(t, nErr)
 			require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
 			err := prwe.PushMetrics(context.Background(), *tt.md)
@@ -650,3 +652,107 @@ func Test_validateAndSanitizeExternalLabels(t *testing.T) {
 		})
 	}
 }
+
+// Test_PushMetricsWAL checks that the metrics written to the WAL are delivered after an outage of the endpoint,
+// and after a restart.
+func Test_PushMetricsWAL(t *testing.T) {
+	var mu sync.Mutex
+	statusCode := http.StatusServiceUnavailable
+	var received []prompb.WriteRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+		var wr prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(data, &wr))
+
+		mu.Lock()
+		defer mu.Unlock()
+		if statusCode == http.StatusNoContent {
+			received = append(received, wr)
+		}
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+	setStatusCode := func(code int) {
+		mu.Lock()
+		defer mu.Unlock()
+		statusCode = code
+	}
+	receivedCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received)
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = server.URL
+	cfg.WAL = &WALConfig{Directory: t.TempDir()}
+	cfg.RetrySettings.InitialInterval = time.Millisecond
+	cfg.RetrySettings.MaxInterval = 10 * time.Millisecond
+	set := component.ExporterCreateSettings{Logger: zap.NewNop()}
+
+	prwe, err := NewPRWExporter(cfg, set)
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The metrics are accepted, and sent once the endpoint is available.
+	require.NoError(t, prwe.PushMetrics(context.Background(), getMetricsFromMetricList(validMetrics1[validDoubleGauge])))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 0, receivedCount())
+	setStatusCode(http.StatusNoContent)
+	assert.Eventually(t, func() bool { return receivedCount() == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	// The metrics that are not sent at shutdown are sent after the next start.
+	setStatusCode(http.StatusServiceUnavailable)
+	require.NoError(t, prwe.PushMetrics(context.Background(), getMetricsFromMetricList(validMetrics2[validDoubleGauge])))
+	require.NoError(t, prwe.Shutdown(context.Background()))
+
+	setStatusCode(http.StatusNoContent)
+	prwe, err = NewPRWExporter(cfg, set)
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, prwe.Shutdown(context.Background())) }()
+	assert.Eventually(t, func() bool { return receivedCount() == 2 }, 5*time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received[1].Timeseries, 1)
+	assert.Equal(t, floatVal2, received[1].Timeseries[0].Samples[0].Value)
+}
+
+func Test_PushMetricsDeltaToCumulativeAndMetadata(t *testing.T) {
+	var received []prompb.WriteRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+		var wr prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(data, &wr))
+		received = append(received, wr)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = server.URL
+	cfg.RemoteWriteQueue.NumConsumers = 1
+	cfg.DeltaToCumulative.Enabled = true
+	cfg.SendMetadata = true
+	prwe, err := NewPRWExporter(cfg, component.ExporterCreateSettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, prwe.Shutdown(context.Background())) }()
+
+	require.NoError(t, prwe.PushMetrics(context.Background(), getMetricsFromMetricList(getDeltaDoubleSumMetric("sum", 1, 10, 1000000))))
+	require.NoError(t, prwe.PushMetrics(context.Background(), getMetricsFromMetricList(getDeltaDoubleSumMetric("sum", 2, 1000000, 2000000))))
+	// The retried points are dropped.
+	require.NoError(t, prwe.PushMetrics(context.Background(), getMetricsFromMetricList(getDeltaDoubleSumMetric("sum", 2, 1000000, 2000000))))
+
+	require.Len(t, received, 2)
+	assert.Equal(t, []prompb.MetricMetadata{{Type: prompb.MetricMetadata_GAUGE, MetricFamilyName: "sum"}}, received[0].Metadata)
+	require.Len(t, received[1].Timeseries, 1)
+	assert.Equal(t, []prompb.Sample{{Value: 3, Timestamp: 2}}, received[1].Timeseries[0].Samples)
+}
@@ -46,7 +46,7 @@ func createMetricsExporter(_ context.Context, set component.ExporterCreateSettin
 		return nil, errors.New("invalid configuration")
 	}
 
-	prwe, err := NewPRWExporter(prwCfg, set.BuildInfo)
+	prwe, err := NewPRWExporter(prwCfg, set)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +94,12 @@ func createDefaultConfig() config.Exporter {
 			Timeout:         exporterhelper.DefaultTimeoutSettings().Timeout,
 			Headers:         map[string]string{},
 		},
+		DeltaToCumulative: DeltaToCumulativeSettings{
+			MaxStale: 5 * time.Minute,
+		},
+		Tenant: TenantSettings{
+			Header: "X-Scope-OrgID",
+		},
 		// TODO(jbd): Adjust the default queue size.
 		RemoteWriteQueue: RemoteWriteQueue{
 			QueueSize:    10000,
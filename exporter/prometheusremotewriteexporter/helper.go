@@ -92,6 +92,91 @@ func addSample(tsMap map[string]*prompb.TimeSeries, sample *prompb.Sample, label
 	}
 }
 
+// addExemplars adds the exemplars to the bucket TimeSeries of a histogram whose bound is the first one greater than
+// or equal to their value. bucketLabels are the labels of the bucket TimeSeries in the order of the bounds, there are
+// fewer of them than bounds when the histogram has fewer bucket counts. The exemplars of the bounds without bucket
+// TimeSeries, and the ones greater than all the bounds, are added to the +Inf bucket TimeSeries of infLabels.
+func addExemplars(tsMap map[string]*prompb.TimeSeries, exemplars []prompb.Exemplar, bounds []float64,
+	bucketLabels [][]prompb.Label, infLabels []prompb.Label, metric pdata.Metric) {
+
+	for _, exemplar := range exemplars {
+		labels := infLabels
+		if index := sort.SearchFloat64s(bounds, exemplar.Value); index < len(bucketLabels) {
+			labels = bucketLabels[index]
+		}
+		if ts, ok := tsMap[timeSeriesSignature(metric, &labels)]; ok {
+			ts.Exemplars = append(ts.Exemplars, exemplar)
+		}
+	}
+}
+
+// exemplarsToPrompb converts the exemplars to Prometheus exemplars, whose labels are their filtered labels, e.g. the
+// trace_id and span_id of the traces they link to.
+func exemplarsToPrompb(exemplars pdata.ExemplarSlice) []prompb.Exemplar {
+	pExemplars := make([]prompb.Exemplar, 0, exemplars.Len())
+	for i := 0; i < exemplars.Len(); i++ {
+		exemplar := exemplars.At(i)
+		pExemplars = append(pExemplars, prompb.Exemplar{
+			Labels:    exemplarLabels(exemplar.FilteredLabels()),
+			Value:     exemplar.Value(),
+			Timestamp: convertTimeStamp(exemplar.Timestamp()),
+		})
+	}
+	return pExemplars
+}
+
+// intExemplarsToPrompb is exemplarsToPrompb for the exemplars of the int histograms.
+func intExemplarsToPrompb(exemplars pdata.IntExemplarSlice) []prompb.Exemplar {
+	pExemplars := make([]prompb.Exemplar, 0, exemplars.Len())
+	for i := 0; i < exemplars.Len(); i++ {
+		exemplar := exemplars.At(i)
+		pExemplars = append(pExemplars, prompb.Exemplar{
+			Labels:    exemplarLabels(exemplar.FilteredLabels()),
+			Value:     float64(exemplar.Value()),
+			Timestamp: convertTimeStamp(exemplar.Timestamp()),
+		})
+	}
+	return pExemplars
+}
+
+func exemplarLabels(labels pdata.StringMap) []prompb.Label {
+	pLabels := make([]prompb.Label, 0, labels.Len())
+	labels.Range(func(key string, value string) bool {
+		pLabels = append(pLabels, prompb.Label{Name: sanitize(key), Value: value})
+		return true
+	})
+	sort.Sort(ByLabelName(pLabels))
+	return pLabels
+}
+
+// metricMetadata returns the Prometheus metadata of the metric, whose family name is name.
+func metricMetadata(metric pdata.Metric, name string) prompb.MetricMetadata {
+	metadata := prompb.MetricMetadata{
+		MetricFamilyName: name,
+		Help:             metric.Description(),
+		Unit:             metric.Unit(),
+	}
+	switch metric.DataType() {
+	case pdata.MetricDataTypeDoubleGauge, pdata.MetricDataTypeIntGauge:
+		metadata.Type = prompb.MetricMetadata_GAUGE
+	case pdata.MetricDataTypeDoubleSum:
+		metadata.Type = prompb.MetricMetadata_GAUGE
+		if metric.DoubleSum().IsMonotonic() {
+			metadata.Type = prompb.MetricMetadata_COUNTER
+		}
+	case pdata.MetricDataTypeIntSum:
+		metadata.Type = prompb.MetricMetadata_GAUGE
+		if metric.IntSum().IsMonotonic() {
+			metadata.Type = prompb.MetricMetadata_COUNTER
+		}
+	case pdata.MetricDataTypeHistogram, pdata.MetricDataTypeIntHistogram:
+		metadata.Type = prompb.MetricMetadata_HISTOGRAM
+	case pdata.MetricDataTypeSummary:
+		metadata.Type = prompb.MetricMetadata_SUMMARY
+	}
+	return metadata
+}
+
 // timeSeries return a string signature in the form of:
 // 		TYPE-label1-value1- ...  -labelN-valueN
 // the label slice should not contain duplicate label names; this method sorts the slice by label name before creating
@@ -322,6 +407,8 @@ func addSingleIntHistogramDataPoint(pt pdata.IntHistogramDataPoint, resource pda
 
 	// cumulative count for conversion to cumulative histogram
 	var cumulativeCount uint64
+	// labels of the bucket TimeSeries, to add the exemplars to
+	bucketLabels := make([][]prompb.Label, 0, len(pt.ExplicitBounds()))
 
 	// process each bound, ignore extra bucket values
 	for index, bound := range pt.ExplicitBounds() {
@@ -336,6 +423,7 @@ func addSingleIntHistogramDataPoint(pt pdata.IntHistogramDataPoint, resource pda
 		boundStr := strconv.FormatFloat(bound, 'f', -1, 64)
 		labels := createLabelSet(resource, pt.LabelsMap(), externalLabels, nameStr, baseName+bucketStr, leStr, boundStr)
 		addSample(tsMap, bucket, labels, metric)
+		bucketLabels = append(bucketLabels, labels)
 	}
 	// add le=+Inf bucket
 	cumulativeCount += pt.BucketCounts()[len(pt.BucketCounts())-1]
@@ -345,6 +433,8 @@ func addSingleIntHistogramDataPoint(pt pdata.IntHistogramDataPoint, resource pda
 	}
 	infLabels := createLabelSet(resource, pt.LabelsMap(), externalLabels, nameStr, baseName+bucketStr, leStr, pInfStr)
 	addSample(tsMap, infBucket, infLabels, metric)
+
+	addExemplars(tsMap, intExemplarsToPrompb(pt.Exemplars()), pt.ExplicitBounds(), bucketLabels, infLabels, metric)
 }
 
 // addSingleHistogramDataPoint converts pt to 2 + min(len(ExplicitBounds), len(BucketCount)) + 1 samples. It
@@ -373,6 +463,8 @@ func addSingleHistogramDataPoint(pt pdata.HistogramDataPoint, resource pdata.Res
 
 	// cumulative count for conversion to cumulative histogram
 	var cumulativeCount uint64
+	// labels of the bucket TimeSeries, to add the exemplars to
+	bucketLabels := make([][]prompb.Label, 0, len(pt.ExplicitBounds()))
 
 	// process each bound, based on histograms proto definition, # of buckets = # of explicit bounds + 1
 	for index, bound := range pt.ExplicitBounds() {
@@ -387,6 +479,7 @@ func addSingleHistogramDataPoint(pt pdata.HistogramDataPoint, resource pdata.Res
 		boundStr := strconv.FormatFloat(bound, 'f', -1, 64)
 		labels := createLabelSet(resource, pt.LabelsMap(), externalLabels, nameStr, baseName+bucketStr, leStr, boundStr)
 		addSample(tsMap, bucket, labels, metric)
+		bucketLabels = append(bucketLabels, labels)
 	}
 	// add le=+Inf bucket
 	cumulativeCount += pt.BucketCounts()[len(pt.BucketCounts())-1]
@@ -396,6 +489,8 @@ func addSingleHistogramDataPoint(pt pdata.HistogramDataPoint, resource pdata.Res
 	}
 	infLabels := createLabelSet(resource, pt.LabelsMap(), externalLabels, nameStr, baseName+bucketStr, leStr, pInfStr)
 	addSample(tsMap, infBucket, infLabels, metric)
+
+	addExemplars(tsMap, exemplarsToPrompb(pt.Exemplars()), pt.ExplicitBounds(), bucketLabels, infLabels, metric)
 }
 
 // addSingleSummaryDataPoint converts pt to len(QuantileValues) + 2 samples.
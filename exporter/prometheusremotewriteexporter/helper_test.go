@@ -487,3 +487,103 @@ func TestEnsureTimeseriesPointsAreSortedByTimestamp(t *testing.T) {
 		}
 	}
 }
+
+func Test_addSingleHistogramDataPointExemplars(t *testing.T) {
+	metric := getHistogramMetric("histogram", getLabels(), 1000000, 10, 3, []float64{1, 5}, []uint64{1, 1, 1})
+	exemplars := metric.Histogram().DataPoints().At(0).Exemplars()
+	for _, v := range []float64{3, 10} {
+		exemplar := exemplars.AppendEmpty()
+		exemplar.SetValue(v)
+		exemplar.SetTimestamp(pdata.Timestamp(1000000))
+		exemplar.FilteredLabels().Insert("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736")
+	}
+
+	tsMap := map[string]*prompb.TimeSeries{}
+	addSingleHistogramDataPoint(metric.Histogram().DataPoints().At(0), pdata.NewResource(), metric, "", tsMap, nil)
+
+	exemplarsByBucket := map[string][]prompb.Exemplar{}
+	for _, ts := range tsMap {
+		for _, l := range ts.Labels {
+			if l.Name == "le" {
+				exemplarsByBucket[l.Value] = ts.Exemplars
+			}
+		}
+	}
+	assert.Empty(t, exemplarsByBucket["1"])
+	assert.Equal(t, []prompb.Exemplar{{Labels: getPromLabels("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"), Value: 3, Timestamp: 1}}, exemplarsByBucket["5"])
+	assert.Equal(t, []prompb.Exemplar{{Labels: getPromLabels("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"), Value: 10, Timestamp: 1}}, exemplarsByBucket["+Inf"])
+}
+
+func Test_addSingleHistogramDataPointExemplarsBucketCountMismatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		bounds       []float64
+		bucketCounts []uint64
+		want         map[string][]float64
+	}{
+		{
+			name:         "fewer bucket counts than bounds",
+			bounds:       []float64{1, 5, 10},
+			bucketCounts: []uint64{1, 1},
+			want:         map[string][]float64{"1": {0.5}, "5": {3}, "+Inf": {7, 20}},
+		},
+		{
+			name:         "more bucket counts than bounds",
+			bounds:       []float64{1},
+			bucketCounts: []uint64{1, 1, 1},
+			want:         map[string][]float64{"1": {0.5}, "+Inf": {3, 7, 20}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := getHistogramMetric("histogram", getLabels(), 1000000, 10, 3, tt.bounds, tt.bucketCounts)
+			exemplars := metric.Histogram().DataPoints().At(0).Exemplars()
+			for _, v := range []float64{0.5, 3, 7, 20} {
+				exemplars.AppendEmpty().SetValue(v)
+			}
+
+			tsMap := map[string]*prompb.TimeSeries{}
+			addSingleHistogramDataPoint(metric.Histogram().DataPoints().At(0), pdata.NewResource(), metric, "", tsMap, nil)
+
+			got := map[string][]float64{}
+			for _, ts := range tsMap {
+				for _, l := range ts.Labels {
+					if l.Name == "le" {
+						for _, e := range ts.Exemplars {
+							got[l.Value] = append(got[l.Value], e.Value)
+						}
+					}
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_metricMetadata(t *testing.T) {
+	monotonicSum := getDoubleSumMetric("sum", getLabels(), 1, 1)
+	monotonicSum.DoubleSum().SetIsMonotonic(true)
+	monotonicSum.SetDescription("Monotonic sum")
+	monotonicSum.SetUnit("By")
+
+	tests := []struct {
+		name   string
+		metric pdata.Metric
+		want   prompb.MetricMetadata_MetricType
+	}{
+		{"gauge", getDoubleGaugeMetric("gauge", getLabels(), 1, 1), prompb.MetricMetadata_GAUGE},
+		{"monotonic sum", monotonicSum, prompb.MetricMetadata_COUNTER},
+		{"non monotonic sum", getIntSumMetric("sum", getLabels(), 1, 1), prompb.MetricMetadata_GAUGE},
+		{"histogram", getHistogramMetric("histogram", getLabels(), 1, 1, 1, nil, []uint64{1}), prompb.MetricMetadata_HISTOGRAM},
+		{"summary", getSummaryMetric("summary", getLabels(), 1, 1, 1, getQuantiles([]float64{0.5}, []float64{1})), prompb.MetricMetadata_SUMMARY},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := metricMetadata(tt.metric, "name")
+			assert.Equal(t, tt.want, metadata.Type)
+			assert.Equal(t, "name", metadata.MetricFamilyName)
+			assert.Equal(t, tt.metric.Description(), metadata.Help)
+			assert.Equal(t, tt.metric.Unit(), metadata.Unit)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	tagExporterName, _ = tag.NewKey("exporter")
+	tagTenant, _       = tag.NewKey("tenant")
+
+	statTenantSentSamples   = stats.Int64("prometheusremotewrite_exporter_tenant_sent_samples", "Number of samples sent per tenant", stats.UnitDimensionless)
+	statTenantFailedSamples = stats.Int64("prometheusremotewrite_exporter_tenant_failed_samples", "Number of samples that failed to be sent per tenant", stats.UnitDimensionless)
+)
+
+// MetricViews return metric views for the Prometheus remote write exporter.
+func MetricViews() []*view.View {
+	tagKeys := []tag.Key{tagExporterName, tagTenant}
+
+	countSentSamples := &view.View{
+		Name:        statTenantSentSamples.Name(),
+		Measure:     statTenantSentSamples,
+		Description: statTenantSentSamples.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countFailedSamples := &view.View{
+		Name:        statTenantFailedSamples.Name(),
+		Measure:     statTenantFailedSamples,
+		Description: statTenantFailedSamples.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	return []*view.View{
+		countSentSamples,
+		countFailedSamples,
+	}
+}
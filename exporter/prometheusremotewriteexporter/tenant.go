@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"go.opentelemetry.io/collector/model/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// splitByTenant splits the metrics by tenant, the value of the attribute of their resource. The tenant of the
+// resources without the attribute is defaultTenant.
+func splitByTenant(md pdata.Metrics, attribute string, defaultTenant string) map[string]pdata.Metrics {
+	tenants := map[string]pdata.Metrics{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		tenant := defaultTenant
+		if v, ok := rm.Resource().Attributes().Get(attribute); ok {
+			tenant = tracetranslator.AttributeValueToString(v)
+		}
+		tenantMD, ok := tenants[tenant]
+		if !ok {
+			tenantMD = pdata.NewMetrics()
+			tenants[tenant] = tenantMD
+		}
+		rm.CopyTo(tenantMD.ResourceMetrics().AppendEmpty())
+	}
+	return tenants
+}
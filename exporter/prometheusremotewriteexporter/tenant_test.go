@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func getTenantMetrics(tenants ...string) pdata.Metrics {
+	md := pdata.NewMetrics()
+	for _, tenant := range tenants {
+		rm := md.ResourceMetrics().AppendEmpty()
+		if tenant != "" {
+			getResource("tenant.id", tenant).CopyTo(rm.Resource())
+		}
+		metric := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty()
+		validMetrics1[validDoubleGauge].CopyTo(metric)
+	}
+	return md
+}
+
+func Test_splitByTenant(t *testing.T) {
+	tenants := splitByTenant(getTenantMetrics("a", "b", "", "a"), "tenant.id", "default")
+	require.Len(t, tenants, 3)
+	assert.Equal(t, 2, tenants["a"].ResourceMetrics().Len())
+	assert.Equal(t, 1, tenants["b"].ResourceMetrics().Len())
+	assert.Equal(t, 1, tenants["default"].ResourceMetrics().Len())
+}
+
+func Test_PushMetricsTenants(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]int{}
+	handler := func(path string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			tenant := r.Header.Get("X-Scope-OrgID")
+			if tenant == "limited" {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			if tenant == "refused" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			received[path+"/"+tenant]++
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	server := httptest.NewServer(handler("shared"))
+	defer server.Close()
+	dedicatedServer := httptest.NewServer(handler("dedicated"))
+	defer dedicatedServer.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = server.URL
+	cfg.Tenant.ResourceAttribute = "tenant.id"
+	cfg.Tenant.Default = "anonymous"
+	cfg.Tenant.Endpoints = map[string]string{"dedicated": dedicatedServer.URL}
+	prwe, err := NewPRWExporter(cfg, component.ExporterCreateSettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, prwe.Shutdown(context.Background())) }()
+
+	err = prwe.PushMetrics(context.Background(), getTenantMetrics("a", "", "dedicated", "limited", "refused"))
+	require.Error(t, err)
+	assert.Equal(t, map[string]int{"shared/a": 1, "shared/anonymous": 1, "dedicated/dedicated": 1}, received)
+
+	// Only the metrics of the tenant refused with a retryable error are retried.
+	assert.False(t, consumererror.IsPermanent(err))
+	var metricsErr consumererror.Metrics
+	require.True(t, consumererror.AsMetrics(err, &metricsErr))
+	failed := metricsErr.GetMetrics()
+	require.Equal(t, 1, failed.ResourceMetrics().Len())
+	tenant, ok := failed.ResourceMetrics().At(0).Resource().Attributes().Get("tenant.id")
+	require.True(t, ok)
+	assert.Equal(t, "limited", tenant.StringVal())
+
+	// The permanent errors are returned as is if no tenant can be retried.
+	err = prwe.PushMetrics(context.Background(), getTenantMetrics("refused"))
+	assert.True(t, consumererror.IsPermanent(err))
+}
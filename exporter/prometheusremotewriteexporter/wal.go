@@ -0,0 +1,455 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const (
+	defaultWALSegmentSize = 16 * 1024 * 1024
+
+	walCheckpointFile = "checkpoint"
+	// walRecordHeaderSize is the size of the header of the records, their length and the CRC32 of their data.
+	walRecordHeaderSize = 8
+)
+
+var (
+	walCastagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+	errWALClosed        = errors.New("the WAL is closed")
+	errWALCorruptRecord = errors.New("corrupt WAL record")
+)
+
+// wal is the write-ahead log of the exporter: the encoded write requests are appended to the segment files of its
+// directory, and read back in order by the sender. The position of the last delivered request is saved in the
+// checkpoint file, the requests after it are replayed after a restart, and the segments are removed once all their
+// requests are delivered.
+//
+// The records of the segments are the length and CRC32 of the request, followed by the request.
+type wal struct {
+	dir         string
+	segmentSize int64
+
+	// mu protects the fields below.
+	mu     sync.Mutex
+	closed bool
+	// segment is the segment the requests are appended to, segmentLen the size of its complete records.
+	segment      *os.File
+	segmentIndex int
+	segmentLen   int64
+	// appended is notified when requests are appended or the WAL is closed.
+	appended chan struct{}
+}
+
+// walPosition is the position of a record in the WAL.
+type walPosition struct {
+	segment int
+	offset  int64
+}
+
+// openWAL opens the WAL in dir, creating dir if needed. The torn records written by a crash at the end of the
+// last segment are truncated.
+func openWAL(dir string, segmentSize int) (*wal, error) {
+	if segmentSize <= 0 {
+		segmentSize = defaultWALSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	segments, err := listWALSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	index := 0
+	if len(segments) != 0 {
+		index = segments[len(segments)-1]
+	}
+	f, err := os.OpenFile(walSegmentPath(dir, index), os.O_CREATE|os.O_RDWR, 0640)
+	if err != nil {
+		return nil, err
+	}
+	size, err := validWALSegmentSize(f)
+	if err == nil {
+		err = f.Truncate(size)
+	}
+	if err == nil {
+		_, err = f.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &wal{
+		dir:          dir,
+		segmentSize:  int64(segmentSize),
+		segment:      f,
+		segmentIndex: index,
+		segmentLen:   size,
+		appended:     make(chan struct{}, 1),
+	}, nil
+}
+
+// append appends the requests to the WAL, and syncs it.
+func (w *wal) append(requests [][]byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return errWALClosed
+	}
+	for _, req := range requests {
+		recordLen := int64(walRecordHeaderSize + len(req))
+		if w.segmentLen != 0 && w.segmentLen+recordLen > w.segmentSize {
+			if err := w.nextSegment(); err != nil {
+				return err
+			}
+		}
+		record := make([]byte, recordLen)
+		binary.BigEndian.PutUint32(record, uint32(len(req)))
+		binary.BigEndian.PutUint32(record[4:], crc32.Checksum(req, walCastagnoliTable))
+		copy(record[walRecordHeaderSize:], req)
+		if _, err := w.segment.Write(record); err != nil {
+			// Remove the partially written record, the next ones would be unreadable after it.
+			if terr := w.segment.Truncate(w.segmentLen); terr == nil {
+				_, _ = w.segment.Seek(w.segmentLen, io.SeekStart)
+			}
+			return err
+		}
+		w.segmentLen += recordLen
+	}
+	if err := w.segment.Sync(); err != nil {
+		return err
+	}
+	select {
+	case w.appended <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// nextSegment closes the current segment, and creates the next one. It must be called with mu held.
+func (w *wal) nextSegment() error {
+	if err := w.segment.Sync(); err != nil {
+		return err
+	}
+	if err := w.segment.Close(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(walSegmentPath(w.dir, w.segmentIndex+1), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	w.segment = f
+	w.segmentIndex++
+	w.segmentLen = 0
+	return nil
+}
+
+// end returns the position of the end of the WAL, and whether the WAL is closed.
+func (w *wal) end() (walPosition, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return walPosition{segment: w.segmentIndex, offset: w.segmentLen}, w.closed
+}
+
+// close closes the WAL, the readers waiting for requests return errWALClosed.
+func (w *wal) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	close(w.appended)
+	return w.segment.Close()
+}
+
+// newReader returns a reader of the requests after the checkpoint.
+func (w *wal) newReader() (*walReader, error) {
+	pos, err := readWALCheckpoint(w.dir)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := listWALSegments(w.dir)
+	if err != nil {
+		return nil, err
+	}
+	end, _ := w.end()
+	if pos.segment < segments[0] || pos.segment > end.segment || (pos.segment == end.segment && pos.offset > end.offset) {
+		// The checkpoint doesn't match the segments, e.g. they were removed: the remaining requests are all read.
+		pos = walPosition{segment: segments[0]}
+	}
+	if err := removeWALSegmentsBefore(w.dir, pos.segment); err != nil {
+		return nil, err
+	}
+	return &walReader{wal: w, pos: pos, next: pos, firstSegment: pos.segment}, nil
+}
+
+// walReader reads the requests of the WAL in order.
+type walReader struct {
+	wal *wal
+	// pos is the position of the next request, i.e. of the request after the last delivered one.
+	pos walPosition
+	// next is the position after the request returned by read.
+	next walPosition
+	// firstSegment is the first segment that is not removed.
+	firstSegment int
+
+	segment *os.File
+	reader  *bufio.Reader
+}
+
+// read returns the next request, waiting for it to be appended if needed. The request must be committed, or read
+// again after a failed delivery.
+func (r *walReader) read(ctx context.Context) ([]byte, error) {
+	for {
+		end, closed := r.wal.end()
+		if r.pos.segment == end.segment && r.pos.offset >= end.offset {
+			if closed {
+				return nil, errWALClosed
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-r.wal.appended:
+			}
+			continue
+		}
+
+		if err := r.openSegment(); err != nil {
+			return nil, err
+		}
+		// The records of the last segment are limited to the complete ones, the previous segments are complete.
+		remaining := end.offset - r.pos.offset
+		if r.pos.segment < end.segment {
+			fi, err := r.segment.Stat()
+			if err != nil {
+				return nil, err
+			}
+			remaining = fi.Size() - r.pos.offset
+		}
+		req, n, err := readWALRecord(r.reader, remaining)
+		if err != nil {
+			// The position of the segment reader is unknown after an error, the segment is reopened at the
+			// next read.
+			_ = r.close()
+		}
+		switch {
+		case err == nil:
+			r.next = walPosition{segment: r.pos.segment, offset: r.pos.offset + n}
+			return req, nil
+		case errors.Is(err, io.EOF) && r.pos.segment < end.segment:
+			// The segment is complete, continue with the next one.
+			r.skipSegment()
+		case errors.Is(err, errWALCorruptRecord) && r.pos.segment < end.segment:
+			r.skipSegment()
+			return nil, fmt.Errorf("%w, the rest of the segment is skipped", err)
+		default:
+			return nil, err
+		}
+	}
+}
+
+// commit saves the position after the request returned by read as delivered, and removes the segments whose
+// requests are all delivered.
+func (r *walReader) commit() error {
+	r.pos = r.next
+	if err := writeWALCheckpoint(r.wal.dir, r.pos); err != nil {
+		return err
+	}
+	if r.pos.segment == r.firstSegment {
+		return nil
+	}
+	if err := removeWALSegmentsBefore(r.wal.dir, r.pos.segment); err != nil {
+		return err
+	}
+	r.firstSegment = r.pos.segment
+	return nil
+}
+
+// close closes the segment file being read.
+func (r *walReader) close() error {
+	if r.segment == nil {
+		return nil
+	}
+	err := r.segment.Close()
+	r.segment = nil
+	r.reader = nil
+	return err
+}
+
+// openSegment opens the segment of the reader position, and seeks to the position. The opened segment is only
+// reused when reading the record right after the last one read.
+func (r *walReader) openSegment() error {
+	if r.segment != nil && r.next == r.pos {
+		return nil
+	}
+	if err := r.close(); err != nil {
+		return err
+	}
+	f, err := os.Open(walSegmentPath(r.wal.dir, r.pos.segment))
+	if err != nil {
+		return err
+	}
+	if _, err = f.Seek(r.pos.offset, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+	r.segment = f
+	r.reader = bufio.NewReader(f)
+	r.next = r.pos
+	return nil
+}
+
+func (r *walReader) skipSegment() {
+	_ = r.close()
+	r.pos = walPosition{segment: r.pos.segment + 1}
+	r.next = r.pos
+}
+
+// readWALRecord reads a record from the remaining bytes of the segment, and returns its request and size. It
+// returns io.EOF at the end of the segment, and errWALCorruptRecord if the record is incomplete, is larger than the
+// remaining bytes, or doesn't match its CRC32.
+func readWALRecord(r io.Reader, remaining int64) ([]byte, int64, error) {
+	var header [walRecordHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, 0, errWALCorruptRecord
+		}
+		return nil, 0, err
+	}
+	// The length is checked before allocating the request, as a corrupt header can claim up to 4 GiB.
+	reqLen := int64(binary.BigEndian.Uint32(header[:]))
+	if reqLen > remaining-walRecordHeaderSize {
+		return nil, 0, errWALCorruptRecord
+	}
+	req := make([]byte, reqLen)
+	if _, err := io.ReadFull(r, req); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, 0, errWALCorruptRecord
+		}
+		return nil, 0, err
+	}
+	if crc32.Checksum(req, walCastagnoliTable) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, 0, errWALCorruptRecord
+	}
+	return req, int64(walRecordHeaderSize + len(req)), nil
+}
+
+// validWALSegmentSize returns the size of the complete and valid records at the start of the segment.
+func validWALSegmentSize(f *os.File) (int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	r := bufio.NewReader(f)
+	var size int64
+	for {
+		_, n, err := readWALRecord(r, fi.Size()-size)
+		switch {
+		case err == nil:
+			size += n
+		case errors.Is(err, io.EOF), errors.Is(err, errWALCorruptRecord):
+			return size, nil
+		default:
+			return 0, err
+		}
+	}
+}
+
+// listWALSegments returns the indexes of the segments in dir, sorted.
+func listWALSegments(dir string) ([]int, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []int
+	for _, f := range files {
+		index, err := strconv.Atoi(f.Name())
+		if err != nil || f.IsDir() {
+			continue
+		}
+		segments = append(segments, index)
+	}
+	sort.Ints(segments)
+	return segments, nil
+}
+
+// removeWALSegmentsBefore removes the segments of dir before the segment index.
+func removeWALSegmentsBefore(dir string, index int) error {
+	segments, err := listWALSegments(dir)
+	if err != nil {
+		return err
+	}
+	for _, s := range segments {
+		if s >= index {
+			break
+		}
+		if err := os.Remove(walSegmentPath(dir, s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walSegmentPath(dir string, index int) string {
+	return filepath.Join(dir, fmt.Sprintf("%08d", index))
+}
+
+// readWALCheckpoint returns the position saved in the checkpoint file of dir, or the start of the WAL if there is
+// none.
+func readWALCheckpoint(dir string) (walPosition, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, walCheckpointFile))
+	if os.IsNotExist(err) {
+		return walPosition{}, nil
+	}
+	if err != nil {
+		return walPosition{}, err
+	}
+	if len(data) != 16 {
+		return walPosition{}, fmt.Errorf("invalid WAL checkpoint file of %d bytes", len(data))
+	}
+	return walPosition{
+		segment: int(binary.BigEndian.Uint64(data)),
+		offset:  int64(binary.BigEndian.Uint64(data[8:])),
+	}, nil
+}
+
+// writeWALCheckpoint replaces the checkpoint file of dir with pos.
+func writeWALCheckpoint(dir string, pos walPosition) error {
+	var data [16]byte
+	binary.BigEndian.PutUint64(data[:], uint64(pos.segment))
+	binary.BigEndian.PutUint64(data[8:], uint64(pos.offset))
+	tmp := filepath.Join(dir, walCheckpointFile+".tmp")
+	if err := ioutil.WriteFile(tmp, data[:], 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, walCheckpointFile))
+}
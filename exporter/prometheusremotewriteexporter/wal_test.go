@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAndCommit(t *testing.T, r *walReader) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	data, err := r.read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, r.commit())
+	return string(data)
+}
+
+func TestWALSegments(t *testing.T) {
+	dir := t.TempDir()
+	// Each segment holds a single 10 bytes request.
+	w, err := openWAL(dir, walRecordHeaderSize+10)
+	require.NoError(t, err)
+	defer w.close()
+
+	require.NoError(t, w.append([][]byte{[]byte("request-01"), []byte("request-02")}))
+	require.NoError(t, w.append([][]byte{[]byte("request-03")}))
+	segments, err := listWALSegments(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, segments)
+
+	r, err := w.newReader()
+	require.NoError(t, err)
+	defer r.close()
+
+	// A request read again before being committed is the same request.
+	data, err := r.read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "request-01", string(data))
+	assert.Equal(t, "request-01", readAndCommit(t, r))
+
+	assert.Equal(t, "request-02", readAndCommit(t, r))
+	segments, err = listWALSegments(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, segments)
+
+	assert.Equal(t, "request-03", readAndCommit(t, r))
+	segments, err = listWALSegments(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, segments)
+}
+
+func TestWALReplay(t *testing.T) {
+	dir := t.TempDir()
+	w, err := openWAL(dir, 0)
+	require.NoError(t, err)
+	require.NoError(t, w.append([][]byte{[]byte("request-01"), []byte("request-02"), []byte("request-03")}))
+	r, err := w.newReader()
+	require.NoError(t, err)
+	assert.Equal(t, "request-01", readAndCommit(t, r))
+	// The second request is read but not delivered.
+	_, err = r.read(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, r.close())
+	require.NoError(t, w.close())
+
+	// A torn record written by a crash is truncated.
+	f, err := os.OpenFile(walSegmentPath(dir, 0), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 0, 42, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	w, err = openWAL(dir, 0)
+	require.NoError(t, err)
+	defer w.close()
+	require.NoError(t, w.append([][]byte{[]byte("request-04")}))
+	r, err = w.newReader()
+	require.NoError(t, err)
+	defer r.close()
+	assert.Equal(t, "request-02", readAndCommit(t, r))
+	assert.Equal(t, "request-03", readAndCommit(t, r))
+	assert.Equal(t, "request-04", readAndCommit(t, r))
+}
+
+func TestWALReadWaits(t *testing.T) {
+	w, err := openWAL(t.TempDir(), 0)
+	require.NoError(t, err)
+	r, err := w.newReader()
+	require.NoError(t, err)
+	defer r.close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = r.read(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, w.append([][]byte{[]byte("request-01")}))
+	}()
+	assert.Equal(t, "request-01", readAndCommit(t, r))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, w.close())
+	}()
+	_, err = r.read(context.Background())
+	assert.Equal(t, errWALClosed, err)
+	assert.Equal(t, errWALClosed, w.append([][]byte{[]byte("request-02")}))
+}
+
+func TestWALCorruptSegment(t *testing.T) {
+	dir := t.TempDir()
+	w, err := openWAL(dir, walRecordHeaderSize+10)
+	require.NoError(t, err)
+	defer w.close()
+	require.NoError(t, w.append([][]byte{[]byte("request-01"), []byte("request-02")}))
+
+	// Corrupt the data of the first request.
+	f, err := os.OpenFile(filepath.Join(dir, "00000000"), os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte("x"), walRecordHeaderSize)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	r, err := w.newReader()
+	require.NoError(t, err)
+	defer r.close()
+	_, err = r.read(context.Background())
+	assert.ErrorIs(t, err, errWALCorruptRecord)
+	assert.Equal(t, "request-02", readAndCommit(t, r))
+}
+
+func TestWALCorruptRecordLength(t *testing.T) {
+	dir := t.TempDir()
+	w, err := openWAL(dir, 0)
+	require.NoError(t, err)
+	require.NoError(t, w.append([][]byte{[]byte("request-01")}))
+	require.NoError(t, w.close())
+
+	// A torn record, whose header claims 4 GiB of data, is truncated without being allocated.
+	f, err := os.OpenFile(filepath.Join(dir, "00000000"), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 'x'})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	w, err = openWAL(dir, 0)
+	require.NoError(t, err)
+	defer w.close()
+	end, _ := w.end()
+	assert.Equal(t, int64(walRecordHeaderSize+10), end.offset)
+
+	_, _, err = readWALRecord(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}), 1024)
+	assert.ErrorIs(t, err, errWALCorruptRecord)
+}
+
+func TestWALReadAfterError(t *testing.T) {
+	dir := t.TempDir()
+	w, err := openWAL(dir, 0)
+	require.NoError(t, err)
+	defer w.close()
+	require.NoError(t, w.append([][]byte{[]byte("request-01"), []byte("request-02")}))
+
+	path := filepath.Join(dir, "00000000")
+	writeAt := func(b byte) {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		require.NoError(t, err)
+		_, err = f.WriteAt([]byte{b}, walRecordHeaderSize)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	r, err := w.newReader()
+	require.NoError(t, err)
+	defer r.close()
+
+	// The failed read leaves the position of the segment reader after the record, the segment is reopened at the
+	// position of the record for the next read.
+	writeAt('x')
+	_, err = r.read(context.Background())
+	assert.ErrorIs(t, err, errWALCorruptRecord)
+	writeAt('r')
+	assert.Equal(t, "request-01", readAndCommit(t, r))
+	assert.Equal(t, "request-02", readAndCommit(t, r))
+}
@@ -15,6 +15,8 @@
 package zipkinexporter
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -33,11 +35,41 @@ type Config struct {
 	Format string `mapstructure:"format"`
 
 	DefaultServiceName string `mapstructure:"default_service_name"`
+
+	// LocalEndpoint configures the localEndpoint of the spans whose resource has no service name.
+	LocalEndpoint LocalEndpointSettings `mapstructure:"local_endpoint"`
+
+	// MaxRequestSpans is the maximum number of spans sent per request, the spans are not split by count if 0.
+	MaxRequestSpans int `mapstructure:"max_request_spans"`
+
+	// MaxRequestSize is the maximum size in bytes of the request bodies before compression, the spans are not split
+	// by size if 0. A single span larger than the maximum size is sent in its own request.
+	MaxRequestSize int `mapstructure:"max_request_size"`
+
+	// Compression is the compression of the request bodies, only `gzip` is supported. The bodies are not compressed
+	// if empty.
+	Compression string `mapstructure:"compression"`
+}
+
+// LocalEndpointSettings configures the localEndpoint of the spans whose resource has no service name.
+type LocalEndpointSettings struct {
+	// ServiceNameAttributes are the resource attributes, in order of precedence, whose value is the service name of
+	// the localEndpoint. The DefaultServiceName is used if the resource has none of them.
+	ServiceNameAttributes []string `mapstructure:"service_name_attributes"`
 }
 
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
-	return nil
+	if cfg.MaxRequestSpans < 0 {
+		return fmt.Errorf("max_request_spans can't be negative")
+	}
+	if cfg.MaxRequestSize < 0 {
+		return fmt.Errorf("max_request_size can't be negative")
+	}
+	if cfg.Compression != "" && cfg.Compression != "gzip" {
+		return fmt.Errorf("unsupported compression %q, only gzip is supported", cfg.Compression)
+	}
+	return cfg.HTTPClientSettings.Validate()
 }
@@ -70,8 +70,46 @@ func TestLoadConfig(t *testing.T) {
 		},
 		Format:             "proto",
 		DefaultServiceName: "test_name",
+		LocalEndpoint: LocalEndpointSettings{
+			ServiceNameAttributes: []string{"k8s.pod.name", "host.name"},
+		},
+		MaxRequestSpans: 100,
+		MaxRequestSize:  1048576,
+		Compression:     "gzip",
 	}, e1)
 	set := componenttest.NewNopExporterCreateSettings()
 	_, err = factory.CreateTracesExporter(context.Background(), set, e1)
 	require.NoError(t, err)
 }
+
+func TestInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "negative max request spans",
+			modify: func(cfg *Config) { cfg.MaxRequestSpans = -1 },
+			err:    "max_request_spans can't be negative",
+		},
+		{
+			name:   "negative max request size",
+			modify: func(cfg *Config) { cfg.MaxRequestSize = -1 },
+			err:    "max_request_size can't be negative",
+		},
+		{
+			name:   "unsupported compression",
+			modify: func(cfg *Config) { cfg.Compression = "zstd" },
+			err:    `unsupported compression "zstd", only gzip is supported`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "http://localhost:9411/api/v2/spans"
+			tt.modify(cfg)
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
@@ -20,13 +20,16 @@ import (
 	"fmt"
 	"net/http"
 
+	zipkinmodel "github.com/openzipkin/zipkin-go/model"
 	"github.com/openzipkin/zipkin-go/proto/zipkin_proto3"
 	zipkinreporter "github.com/openzipkin/zipkin-go/reporter"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/middleware"
 	"go.opentelemetry.io/collector/model/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"go.opentelemetry.io/collector/translator/trace/zipkinv2"
 )
 
@@ -38,20 +41,34 @@ var translator zipkinv2.FromTranslator
 // Zipkin servers and then transform them back to the final form when creating an
 // OpenCensus spandata.
 type zipkinExporter struct {
-	defaultServiceName string
+	defaultServiceName    string
+	serviceNameAttributes []string
 
-	url            string
-	client         *http.Client
-	serializer     zipkinreporter.SpanSerializer
-	clientSettings *confighttp.HTTPClientSettings
+	url             string
+	client          *http.Client
+	serializer      zipkinreporter.SpanSerializer
+	clientSettings  *confighttp.HTTPClientSettings
+	compression     string
+	maxRequestSpans int
+	maxRequestSize  int
+}
+
+// zipkinRequest is the serialized body of a request, of the spans starting at the index start.
+type zipkinRequest struct {
+	start int
+	body  []byte
 }
 
 func createZipkinExporter(cfg *Config) (*zipkinExporter, error) {
 	ze := &zipkinExporter{
-		defaultServiceName: cfg.DefaultServiceName,
-		url:                cfg.Endpoint,
-		clientSettings:     &cfg.HTTPClientSettings,
-		client:             nil,
+		defaultServiceName:    cfg.DefaultServiceName,
+		serviceNameAttributes: cfg.LocalEndpoint.ServiceNameAttributes,
+		url:                   cfg.Endpoint,
+		clientSettings:        &cfg.HTTPClientSettings,
+		client:                nil,
+		compression:           cfg.Compression,
+		maxRequestSpans:       cfg.MaxRequestSpans,
+		maxRequestSize:        cfg.MaxRequestSize,
 	}
 
 	switch cfg.Format {
@@ -68,21 +85,143 @@ func createZipkinExporter(cfg *Config) (*zipkinExporter, error) {
 
 // start creates the http client
 func (ze *zipkinExporter) start(_ context.Context, host component.Host) (err error) {
-	ze.client, err = ze.clientSettings.ToClient(host.GetExtensions())
-	return
+	client, err := ze.clientSettings.ToClient(host.GetExtensions())
+	if err != nil {
+		return err
+	}
+	if ze.compression != "" {
+		if client.Transport, err = middleware.NewCompressRoundTripper(client.Transport, ze.compression); err != nil {
+			return err
+		}
+	}
+	ze.client = client
+	return nil
 }
 
+// pushTraces sends the spans in requests of at most maxRequestSpans spans and maxRequestSize bytes. If a request
+// fails with a retryable error, the spans of this request and of the following ones are retried.
 func (ze *zipkinExporter) pushTraces(ctx context.Context, td pdata.Traces) error {
 	spans, err := translator.FromTraces(td)
 	if err != nil {
 		return consumererror.Permanent(fmt.Errorf("failed to push trace data via Zipkin exporter: %w", err))
 	}
+	ze.setLocalEndpoints(spans)
 
-	body, err := ze.serializer.Serialize(spans)
+	requests, err := ze.splitSpans(spans, 0)
 	if err != nil {
 		return consumererror.Permanent(fmt.Errorf("failed to push trace data via Zipkin exporter: %w", err))
 	}
+	for i, request := range requests {
+		if err = ze.send(ctx, request.body); err != nil {
+			if i == 0 || consumererror.IsPermanent(err) {
+				return err
+			}
+			return consumererror.NewTraces(err, tracesFromSpan(td, request.start))
+		}
+	}
+	return nil
+}
+
+// setLocalEndpoints sets the service name of the localEndpoint of the spans whose resource has no service name, to
+// the value of the first serviceNameAttributes of the resource, or to the defaultServiceName.
+func (ze *zipkinExporter) setLocalEndpoints(spans []*zipkinmodel.SpanModel) {
+	for _, span := range spans {
+		if span.LocalEndpoint == nil {
+			span.LocalEndpoint = &zipkinmodel.Endpoint{ServiceName: tracetranslator.ResourceNoServiceName}
+		}
+		if span.LocalEndpoint.ServiceName != tracetranslator.ResourceNoServiceName {
+			continue
+		}
+		if ze.defaultServiceName != "" {
+			span.LocalEndpoint.ServiceName = ze.defaultServiceName
+		}
+		// The resource attributes are tags of the spans.
+		for _, attribute := range ze.serviceNameAttributes {
+			if value, ok := span.Tags[attribute]; ok && value != "" {
+				span.LocalEndpoint.ServiceName = value
+				break
+			}
+		}
+	}
+}
+
+// splitSpans serializes the spans, whose first one is at the index start, in requests of at most maxRequestSpans
+// spans and maxRequestSize bytes. The spans exceeding maxRequestSize are split in halves until they fit, or until
+// they are a single span.
+func (ze *zipkinExporter) splitSpans(spans []*zipkinmodel.SpanModel, start int) ([]zipkinRequest, error) {
+	if ze.maxRequestSpans > 0 && len(spans) > ze.maxRequestSpans {
+		var requests []zipkinRequest
+		for i := 0; i < len(spans); i += ze.maxRequestSpans {
+			end := i + ze.maxRequestSpans
+			if end > len(spans) {
+				end = len(spans)
+			}
+			batch, err := ze.splitSpans(spans[i:end], start+i)
+			if err != nil {
+				return nil, err
+			}
+			requests = append(requests, batch...)
+		}
+		return requests, nil
+	}
+
+	body, err := ze.serializer.Serialize(spans)
+	if err != nil {
+		return nil, err
+	}
+	if ze.maxRequestSize <= 0 || len(body) <= ze.maxRequestSize || len(spans) == 1 {
+		return []zipkinRequest{{start: start, body: body}}, nil
+	}
+	half := len(spans) / 2
+	first, err := ze.splitSpans(spans[:half], start)
+	if err != nil {
+		return nil, err
+	}
+	second, err := ze.splitSpans(spans[half:], start+half)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// tracesFromSpan returns the traces of td from the span at the index start, in the order of the resource spans,
+// instrumentation library spans and spans.
+func tracesFromSpan(td pdata.Traces, start int) pdata.Traces {
+	traces := pdata.NewTraces()
+	index := 0
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		var newRS pdata.ResourceSpans
+		hasSpans := false
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			spans := ils.Spans()
+			if index+spans.Len() <= start {
+				index += spans.Len()
+				continue
+			}
+			if !hasSpans {
+				newRS = traces.ResourceSpans().AppendEmpty()
+				rs.Resource().CopyTo(newRS.Resource())
+				hasSpans = true
+			}
+			newILS := newRS.InstrumentationLibrarySpans().AppendEmpty()
+			ils.InstrumentationLibrary().CopyTo(newILS.InstrumentationLibrary())
+			for k := 0; k < spans.Len(); k++ {
+				if index >= start {
+					spans.At(k).CopyTo(newILS.Spans().AppendEmpty())
+				}
+				index++
+			}
+		}
+	}
+	return traces
+}
 
+// send sends the serialized spans.
+func (ze *zipkinExporter) send(ctx context.Context, body []byte) error {
 	req, err := http.NewRequestWithContext(ctx, "POST", ze.url, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("failed to push trace data via Zipkin exporter: %w", err)
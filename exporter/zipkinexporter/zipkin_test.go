@@ -16,13 +16,16 @@ package zipkinexporter
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	zipkinmodel "github.com/openzipkin/zipkin-go/model"
@@ -34,8 +37,11 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/receiver/zipkinreceiver"
 	"go.opentelemetry.io/collector/testutil"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 // This function tests that Zipkin spans that are received then processed roundtrip
@@ -355,3 +361,182 @@ func TestZipkinExporter_roundtripProto(t *testing.T) {
 	_, err = zipkin_proto3.ParseSpans(gotBytes, false)
 	require.NoError(t, err)
 }
+
+// generateTraces returns traces with spans named span-0 to span-n, split between two resources, the first one
+// without service name and the second one with the service name "service".
+func generateTraces(n int) pdata.Traces {
+	td := pdata.NewTraces()
+	rs0 := td.ResourceSpans().AppendEmpty()
+	rs0.Resource().Attributes().InsertString("k8s.pod.name", "pod")
+	rs1 := td.ResourceSpans().AppendEmpty()
+	rs1.Resource().Attributes().InsertString("service.name", "service")
+	for i := 0; i < n; i++ {
+		rs := rs0
+		if i >= n/2 {
+			rs = rs1
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		if ilss.Len() == 0 {
+			ilss.AppendEmpty()
+		}
+		span := ilss.At(0).Spans().AppendEmpty()
+		span.SetName(fmt.Sprintf("span-%d", i))
+		span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{byte(i + 1)}))
+	}
+	return td
+}
+
+func spanNames(td pdata.Traces) []string {
+	var names []string
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				names = append(names, spans.At(k).Name())
+			}
+		}
+	}
+	return names
+}
+
+func TestZipkinExporter_split(t *testing.T) {
+	var mu sync.Mutex
+	var requests [][]*zipkinmodel.SpanModel
+	fail := false
+	cst := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail && len(requests) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var spans []*zipkinmodel.SpanModel
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&spans))
+		requests = append(requests, spans)
+	}))
+	defer cst.Close()
+
+	tests := []struct {
+		name      string
+		maxSpans  int
+		maxSize   int
+		wantSizes []int
+	}{
+		{
+			name:      "no limit",
+			wantSizes: []int{5},
+		},
+		{
+			name:      "max spans",
+			maxSpans:  2,
+			wantSizes: []int{2, 2, 1},
+		},
+		{
+			name:      "max size",
+			maxSize:   1,
+			wantSizes: []int{1, 1, 1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = cst.URL
+			cfg.MaxRequestSpans = tt.maxSpans
+			cfg.MaxRequestSize = tt.maxSize
+			ze, err := createZipkinExporter(cfg)
+			require.NoError(t, err)
+			require.NoError(t, ze.start(context.Background(), componenttest.NewNopHost()))
+
+			require.NoError(t, ze.pushTraces(context.Background(), generateTraces(5)))
+			var sizes []int
+			for _, request := range requests {
+				sizes = append(sizes, len(request))
+			}
+			assert.Equal(t, tt.wantSizes, sizes)
+		})
+	}
+
+	t.Run("retry remaining spans", func(t *testing.T) {
+		requests = nil
+		fail = true
+		cfg := createDefaultConfig().(*Config)
+		cfg.Endpoint = cst.URL
+		cfg.MaxRequestSpans = 2
+		ze, err := createZipkinExporter(cfg)
+		require.NoError(t, err)
+		require.NoError(t, ze.start(context.Background(), componenttest.NewNopHost()))
+
+		err = ze.pushTraces(context.Background(), generateTraces(5))
+		require.Error(t, err)
+		assert.False(t, consumererror.IsPermanent(err))
+		var tracesErr consumererror.Traces
+		require.True(t, consumererror.AsTraces(err, &tracesErr))
+		assert.Equal(t, []string{"span-2", "span-3", "span-4"}, spanNames(tracesErr.GetTraces()))
+	})
+}
+
+func TestZipkinExporter_gzip(t *testing.T) {
+	var spans []*zipkinmodel.SpanModel
+	cst := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		gr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.NewDecoder(gr).Decode(&spans))
+	}))
+	defer cst.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = cst.URL
+	cfg.Compression = "gzip"
+	ze, err := createZipkinExporter(cfg)
+	require.NoError(t, err)
+	require.NoError(t, ze.start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, ze.pushTraces(context.Background(), generateTraces(2)))
+	assert.Len(t, spans, 2)
+}
+
+func TestZipkinExporter_localEndpoint(t *testing.T) {
+	tests := []struct {
+		name               string
+		attributes         []string
+		defaultServiceName string
+		want               string
+	}{
+		{
+			name:               "default service name",
+			defaultServiceName: "default",
+			want:               "default",
+		},
+		{
+			name:               "service name attribute",
+			attributes:         []string{"host.name", "k8s.pod.name"},
+			defaultServiceName: "default",
+			want:               "pod",
+		},
+		{
+			name: "no default service name",
+			want: tracetranslator.ResourceNoServiceName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.DefaultServiceName = tt.defaultServiceName
+			cfg.LocalEndpoint.ServiceNameAttributes = tt.attributes
+			ze, err := createZipkinExporter(cfg)
+			require.NoError(t, err)
+
+			spans, err := translator.FromTraces(generateTraces(2))
+			require.NoError(t, err)
+			ze.setLocalEndpoints(spans)
+			require.Len(t, spans, 2)
+			assert.Equal(t, tt.want, spans[0].LocalEndpoint.ServiceName)
+			assert.Equal(t, "service", spans[1].LocalEndpoint.ServiceName)
+		})
+	}
+}
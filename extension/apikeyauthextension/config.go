@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikeyauthextension
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+var (
+	errNoHeader              = errors.New("\"header\" must be specified")
+	errNoKeysFile            = errors.New("\"keys_file\" must be specified")
+	errInvalidReloadInterval = errors.New("\"reload_interval\" must be positive")
+)
+
+// Config has the configuration for the API key Authenticator extension.
+type Config struct {
+	config.ExtensionSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Header is the name of the request header holding the API key. Default value: "x-api-key".
+	Header string `mapstructure:"header"`
+
+	// KeysFile is the path to the file holding the valid API keys, one per line. Each key may be
+	// followed by whitespace and the subject the key belongs to.
+	// Required.
+	KeysFile string `mapstructure:"keys_file"`
+
+	// ReloadInterval is the interval at which the keys file is checked for changes. Default value: 10s.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+var _ config.Extension = (*Config)(nil)
+
+// Validate checks if the extension configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Header == "" {
+		return errNoHeader
+	}
+	if cfg.KeysFile == "" {
+		return errNoKeysFile
+	}
+	if cfg.ReloadInterval <= 0 {
+		return errInvalidReloadInterval
+	}
+	return nil
+}
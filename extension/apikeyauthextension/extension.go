@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikeyauthextension
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/internal/filewatcher"
+)
+
+var (
+	_ configauth.ServerAuthenticator = (*apiKeyExtension)(nil)
+
+	errNotAuthenticated = errors.New("authentication didn't succeed")
+	errInvalidAPIKey    = errors.New("invalid API key")
+)
+
+type apiKeyExtension struct {
+	cfg               *Config
+	header            string
+	unaryInterceptor  configauth.GRPCUnaryInterceptorFunc
+	streamInterceptor configauth.GRPCStreamInterceptorFunc
+
+	// keys maps the SHA-256 digest of the valid keys to the subject they belong to.
+	// Digests are used so that the lookup time doesn't depend on the key content.
+	keysMu sync.RWMutex
+	keys   map[[sha256.Size]byte]string
+
+	watcher *filewatcher.Watcher
+
+	logger *zap.Logger
+}
+
+func newExtension(cfg *Config, logger *zap.Logger) (*apiKeyExtension, error) {
+	if cfg.KeysFile == "" {
+		return nil, errNoKeysFile
+	}
+
+	header := cfg.Header
+	if header == "" {
+		header = defaultHeader
+	}
+
+	return &apiKeyExtension{
+		cfg: cfg,
+		// headers are passed to the authenticator in lower case
+		header:            strings.ToLower(header),
+		unaryInterceptor:  configauth.DefaultGRPCUnaryServerInterceptor,
+		streamInterceptor: configauth.DefaultGRPCStreamServerInterceptor,
+		logger:            logger,
+	}, nil
+}
+
+// Start loads the keys file and starts watching it for changes.
+func (e *apiKeyExtension) Start(context.Context, component.Host) error {
+	if err := e.loadKeys(); err != nil {
+		return err
+	}
+
+	interval := e.cfg.ReloadInterval
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	e.watcher = filewatcher.New(e.cfg.KeysFile, interval, func() {
+		if err := e.loadKeys(); err != nil {
+			e.logger.Error("Failed to reload the API keys, keeping the previous keys", zap.Error(err))
+			return
+		}
+		e.logger.Info("Reloaded the API keys", zap.String("keys_file", e.cfg.KeysFile))
+	})
+	e.watcher.Start()
+	return nil
+}
+
+// Shutdown stops watching the keys file.
+func (e *apiKeyExtension) Shutdown(context.Context) error {
+	if e.watcher != nil {
+		e.watcher.Stop()
+	}
+	return nil
+}
+
+// Authenticate checks whether the given headers contain a valid API key. Successfully authenticated calls
+// return a context carrying the subject of the key as auth data.
+func (e *apiKeyExtension) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	keyHeaders := headers[e.header]
+	if len(keyHeaders) == 0 || keyHeaders[0] == "" {
+		return ctx, errNotAuthenticated
+	}
+
+	// we only use the first header, if multiple values exist
+	digest := sha256.Sum256([]byte(keyHeaders[0]))
+	e.keysMu.RLock()
+	subject, ok := e.keys[digest]
+	e.keysMu.RUnlock()
+	if !ok {
+		return ctx, errInvalidAPIKey
+	}
+
+	return configauth.NewContextWithAuthData(ctx, &configauth.AuthData{
+		Subject: subject,
+	}), nil
+}
+
+// GRPCUnaryServerInterceptor is a helper method to provide a gRPC-compatible UnaryInterceptor, typically calling the authenticator's Authenticate method.
+func (e *apiKeyExtension) GRPCUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return e.unaryInterceptor(ctx, req, info, handler, e.Authenticate)
+}
+
+// GRPCStreamServerInterceptor is a helper method to provide a gRPC-compatible StreamInterceptor, typically calling the authenticator's Authenticate method.
+func (e *apiKeyExtension) GRPCStreamServerInterceptor(srv interface{}, str grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return e.streamInterceptor(srv, str, info, handler, e.Authenticate)
+}
+
+// loadKeys reads the keys file and replaces the current keys with its content.
+func (e *apiKeyExtension) loadKeys() error {
+	f, err := os.Open(e.cfg.KeysFile)
+	if err != nil {
+		return fmt.Errorf("failed to open the API keys file: %w", err)
+	}
+	defer f.Close()
+
+	keys, err := parseKeys(f)
+	if err != nil {
+		return fmt.Errorf("failed to read the API keys file %q: %w", e.cfg.KeysFile, err)
+	}
+
+	e.keysMu.Lock()
+	e.keys = keys
+	e.keysMu.Unlock()
+	return nil
+}
+
+// parseKeys reads one key per line, optionally followed by whitespace and the subject the key belongs to.
+// Empty lines and lines starting with "#" are ignored.
+func parseKeys(r io.Reader) (map[[sha256.Size]byte]string, error) {
+	keys := map[[sha256.Size]byte]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		subject := ""
+		if len(fields) > 1 {
+			subject = strings.Join(fields[1:], " ")
+		}
+		keys[sha256.Sum256([]byte(fields[0]))] = subject
+	}
+	return keys, scanner.Err()
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikeyauthextension
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
+)
+
+func TestAuthenticate(t *testing.T) {
+	ext, err := newExtension(&Config{KeysFile: "./testdata/keys"}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, ext.Shutdown(context.Background())) }()
+
+	tests := []struct {
+		name    string
+		headers map[string][]string
+		subject string
+		err     error
+	}{
+		{
+			name:    "key with subject",
+			headers: map[string][]string{"x-api-key": {"key-1"}},
+			subject: "tenant-1",
+		},
+		{
+			name:    "key without subject",
+			headers: map[string][]string{"x-api-key": {"key-without-subject"}},
+		},
+		{
+			name:    "unknown key",
+			headers: map[string][]string{"x-api-key": {"key-2"}},
+			err:     errInvalidAPIKey,
+		},
+		{
+			name:    "comment is not a key",
+			headers: map[string][]string{"x-api-key": {"#"}},
+			err:     errInvalidAPIKey,
+		},
+		{
+			name:    "empty key",
+			headers: map[string][]string{"x-api-key": {""}},
+			err:     errNotAuthenticated,
+		},
+		{
+			name:    "no header",
+			headers: map[string][]string{"authorization": {"key-1"}},
+			err:     errNotAuthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := ext.Authenticate(context.Background(), tt.headers)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			authData, ok := configauth.AuthDataFromContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, tt.subject, authData.Subject)
+		})
+	}
+}
+
+func TestAuthenticateCustomHeader(t *testing.T) {
+	ext, err := newExtension(&Config{Header: "Authorization-Key", KeysFile: "./testdata/keys"}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, ext.Shutdown(context.Background())) }()
+
+	_, err = ext.Authenticate(context.Background(), map[string][]string{"authorization-key": {"key-1"}})
+	assert.NoError(t, err)
+}
+
+func TestKeysReload(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, ioutil.WriteFile(keysFile, []byte("old-key\n"), 0600))
+
+	ext, err := newExtension(&Config{KeysFile: keysFile, ReloadInterval: 10 * time.Millisecond}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, ext.Shutdown(context.Background())) }()
+
+	_, err = ext.Authenticate(context.Background(), map[string][]string{"x-api-key": {"old-key"}})
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(keysFile, []byte("new-key tenant\n"), 0600))
+	assert.Eventually(t, func() bool {
+		_, err = ext.Authenticate(context.Background(), map[string][]string{"x-api-key": {"new-key"}})
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = ext.Authenticate(context.Background(), map[string][]string{"x-api-key": {"old-key"}})
+	assert.Equal(t, errInvalidAPIKey, err)
+}
+
+func TestStartMissingKeysFile(t *testing.T) {
+	ext, err := newExtension(&Config{KeysFile: "./testdata/missing"}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, ext.Shutdown(context.Background()))
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	ext, err := newExtension(&Config{KeysFile: "./testdata/keys"}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, ext.Shutdown(context.Background())) }()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "key-1"))
+	var subject string
+	_, err = ext.GRPCUnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		authData, _ := configauth.AuthDataFromContext(ctx)
+		subject = authData.Subject
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-1", subject)
+}
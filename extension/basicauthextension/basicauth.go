@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basicauthextension
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
+)
+
+const authorizationHeader = "authorization"
+
+var (
+	errNoHtpasswdSettings                = errors.New("no htpasswd settings provided")
+	errNotAuthenticated                  = errors.New("authentication didn't succeed")
+	errInvalidAuthenticationHeaderFormat = errors.New("invalid authorization header format")
+	errInvalidCredentials                = errors.New("invalid username or password")
+)
+
+// serverAuth is an implementation of configauth.ServerAuthenticator checking
+// the credentials of the "Basic" authorization scheme against an htpasswd file.
+type serverAuth struct {
+	settings          *HtpasswdSettings
+	unaryInterceptor  configauth.GRPCUnaryInterceptorFunc
+	streamInterceptor configauth.GRPCStreamInterceptorFunc
+
+	// users maps user names to their bcrypt password hash.
+	users map[string][]byte
+
+	logger *zap.Logger
+}
+
+var _ configauth.ServerAuthenticator = (*serverAuth)(nil)
+
+func newServerAuth(settings *HtpasswdSettings, logger *zap.Logger) (*serverAuth, error) {
+	if settings == nil {
+		return nil, errNoHtpasswdSettings
+	}
+	return &serverAuth{
+		settings:          settings,
+		unaryInterceptor:  configauth.DefaultGRPCUnaryServerInterceptor,
+		streamInterceptor: configauth.DefaultGRPCStreamServerInterceptor,
+		logger:            logger,
+	}, nil
+}
+
+// Start loads the users from the htpasswd file and the inline htpasswd content.
+func (s *serverAuth) Start(context.Context, component.Host) error {
+	users := map[string][]byte{}
+	if s.settings.File != "" {
+		f, err := os.Open(s.settings.File)
+		if err != nil {
+			return fmt.Errorf("failed to open htpasswd file: %w", err)
+		}
+		defer f.Close()
+		if err = parseHtpasswd(f, users); err != nil {
+			return fmt.Errorf("failed to read htpasswd file %q: %w", s.settings.File, err)
+		}
+	}
+	if s.settings.Inline != "" {
+		if err := parseHtpasswd(strings.NewReader(s.settings.Inline), users); err != nil {
+			return fmt.Errorf("failed to read inline htpasswd: %w", err)
+		}
+	}
+	s.users = users
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (s *serverAuth) Shutdown(context.Context) error {
+	return nil
+}
+
+// Authenticate checks whether the given headers contain valid "Basic" credentials. Successfully authenticated calls
+// return a context carrying the user name as the subject of the auth data.
+func (s *serverAuth) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	authHeaders := headers[authorizationHeader]
+	if len(authHeaders) == 0 {
+		return ctx, errNotAuthenticated
+	}
+
+	// we only use the first header, if multiple values exist
+	username, password, err := parseBasicAuth(authHeaders[0])
+	if err != nil {
+		return ctx, err
+	}
+
+	hash, ok := s.users[username]
+	if !ok {
+		return ctx, errInvalidCredentials
+	}
+	if err = bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil {
+		return ctx, errInvalidCredentials
+	}
+
+	return configauth.NewContextWithAuthData(ctx, &configauth.AuthData{
+		Subject: username,
+	}), nil
+}
+
+// GRPCUnaryServerInterceptor is a helper method to provide a gRPC-compatible UnaryInterceptor, typically calling the authenticator's Authenticate method.
+func (s *serverAuth) GRPCUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return s.unaryInterceptor(ctx, req, info, handler, s.Authenticate)
+}
+
+// GRPCStreamServerInterceptor is a helper method to provide a gRPC-compatible StreamInterceptor, typically calling the authenticator's Authenticate method.
+func (s *serverAuth) GRPCStreamServerInterceptor(srv interface{}, str grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return s.streamInterceptor(srv, str, info, handler, s.Authenticate)
+}
+
+// parseHtpasswd reads the "user:hash" lines of an htpasswd file into users.
+// Only bcrypt hashes are supported, other hash formats are rejected.
+func parseHtpasswd(r io.Reader, users map[string][]byte) error {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		idx := strings.Index(entry, ":")
+		if idx <= 0 {
+			return fmt.Errorf("invalid entry at line %d", line)
+		}
+		hash := []byte(entry[idx+1:])
+		if _, err := bcrypt.Cost(hash); err != nil {
+			return fmt.Errorf("unsupported password hash at line %d, only bcrypt is supported: %w", line, err)
+		}
+		users[entry[:idx]] = hash
+	}
+	return scanner.Err()
+}
+
+// parseBasicAuth returns the user name and password of a "Basic" authorization header value.
+func parseBasicAuth(value string) (string, string, error) {
+	const prefix = "basic "
+	if len(value) < len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return "", "", errInvalidAuthenticationHeaderFormat
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[len(prefix):]))
+	if err != nil {
+		return "", "", errInvalidAuthenticationHeaderFormat
+	}
+	idx := strings.IndexByte(string(decoded), ':')
+	if idx < 0 {
+		return "", "", errInvalidAuthenticationHeaderFormat
+	}
+	return string(decoded[:idx]), string(decoded[idx+1:]), nil
+}
+
+// clientAuth is an implementation of configauth.HTTPClientAuthenticator and configauth.GRPCClientAuthenticator.
+// It adds "Basic" credentials to every request.
+type clientAuth struct {
+	username string
+	password string
+}
+
+var (
+	_ configauth.HTTPClientAuthenticator = (*clientAuth)(nil)
+	_ configauth.GRPCClientAuthenticator = (*clientAuth)(nil)
+)
+
+func newClientAuth(settings *ClientAuthSettings) *clientAuth {
+	return &clientAuth{
+		username: settings.Username,
+		password: settings.Password,
+	}
+}
+
+// Start of clientAuth does nothing and returns nil
+func (c *clientAuth) Start(context.Context, component.Host) error {
+	return nil
+}
+
+// Shutdown of clientAuth does nothing and returns nil
+func (c *clientAuth) Shutdown(context.Context) error {
+	return nil
+}
+
+// RoundTripper returns an http.RoundTripper setting the "Basic" credentials on every request.
+func (c *clientAuth) RoundTripper(base http.RoundTripper) (http.RoundTripper, error) {
+	return &basicAuthRoundTripper{
+		base:     base,
+		username: c.username,
+		password: c.password,
+	}, nil
+}
+
+// PerRPCCredentials returns an implementation of credentials.PerRPCCredentials sending the "Basic" credentials on every RPC.
+func (c *clientAuth) PerRPCCredentials() (credentials.PerRPCCredentials, error) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.password))
+	return &perRPCAuth{
+		metadata: map[string]string{authorizationHeader: "Basic " + encoded},
+	}, nil
+}
+
+type basicAuthRoundTripper struct {
+	base     http.RoundTripper
+	username string
+	password string
+}
+
+func (b *basicAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the original request.
+	newReq := req.Clone(req.Context())
+	newReq.SetBasicAuth(b.username, b.password)
+	return b.base.RoundTrip(newReq)
+}
+
+var _ credentials.PerRPCCredentials = (*perRPCAuth)(nil)
+
+// perRPCAuth is a gRPC credentials.PerRPCCredentials implementation that returns an 'authorization' header.
+type perRPCAuth struct {
+	metadata map[string]string
+}
+
+// GetRequestMetadata returns the request metadata to be used with the RPC.
+func (p *perRPCAuth) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return p.metadata, nil
+}
+
+// RequireTransportSecurity always returns true for this implementation. Passing credentials in plain-text connections is a bad idea.
+func (p *perRPCAuth) RequireTransportSecurity() bool {
+	return true
+}
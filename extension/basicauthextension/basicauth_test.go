@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basicauthextension
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
+)
+
+func basicHeader(username, password string) map[string][]string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return map[string][]string{"authorization": {"Basic " + encoded}}
+}
+
+func TestServerAuthenticate(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	ext, err := newServerAuth(&HtpasswdSettings{
+		File:   "./testdata/htpasswd",
+		Inline: "# a comment\n\ninline:" + string(hash) + "\n",
+	}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	tests := []struct {
+		name    string
+		headers map[string][]string
+		subject string
+		err     error
+	}{
+		{
+			name:    "file user",
+			headers: basicHeader("username", "password"),
+			subject: "username",
+		},
+		{
+			name:    "inline user",
+			headers: basicHeader("inline", "secret"),
+			subject: "inline",
+		},
+		{
+			name:    "lower case scheme",
+			headers: map[string][]string{"authorization": {"basic " + base64.StdEncoding.EncodeToString([]byte("inline:secret"))}},
+			subject: "inline",
+		},
+		{
+			name:    "wrong password",
+			headers: basicHeader("username", "secret"),
+			err:     errInvalidCredentials,
+		},
+		{
+			name:    "unknown user",
+			headers: basicHeader("unknown", "password"),
+			err:     errInvalidCredentials,
+		},
+		{
+			name:    "no header",
+			headers: map[string][]string{},
+			err:     errNotAuthenticated,
+		},
+		{
+			name:    "bearer scheme",
+			headers: map[string][]string{"authorization": {"Bearer token"}},
+			err:     errInvalidAuthenticationHeaderFormat,
+		},
+		{
+			name:    "invalid base64",
+			headers: map[string][]string{"authorization": {"Basic !!!"}},
+			err:     errInvalidAuthenticationHeaderFormat,
+		},
+		{
+			name:    "no separator",
+			headers: map[string][]string{"authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte("username"))}},
+			err:     errInvalidAuthenticationHeaderFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := ext.Authenticate(context.Background(), tt.headers)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			authData, ok := configauth.AuthDataFromContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, tt.subject, authData.Subject)
+		})
+	}
+
+	assert.NoError(t, ext.Shutdown(context.Background()))
+}
+
+func TestServerStartInvalidHtpasswd(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *HtpasswdSettings
+	}{
+		{
+			name:     "missing file",
+			settings: &HtpasswdSettings{File: "./testdata/missing"},
+		},
+		{
+			name:     "no separator",
+			settings: &HtpasswdSettings{Inline: "username"},
+		},
+		{
+			name:     "not bcrypt",
+			settings: &HtpasswdSettings{Inline: "username:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext, err := newServerAuth(tt.settings, zap.NewNop())
+			require.NoError(t, err)
+			assert.Error(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+		})
+	}
+}
+
+func TestClientRoundTripper(t *testing.T) {
+	ext := newClientAuth(&ClientAuthSettings{Username: "username", Password: "password"})
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	var username, password string
+	var ok bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok = r.BasicAuth()
+	}))
+	defer srv.Close()
+
+	rt, err := ext.RoundTripper(http.DefaultTransport)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.True(t, ok)
+	assert.Equal(t, "username", username)
+	assert.Equal(t, "password", password)
+	// the original request must not be modified
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	assert.NoError(t, ext.Shutdown(context.Background()))
+}
+
+func TestClientPerRPCCredentials(t *testing.T) {
+	ext := newClientAuth(&ClientAuthSettings{Username: "username", Password: "password"})
+
+	creds, err := ext.PerRPCCredentials()
+	require.NoError(t, err)
+	assert.True(t, creds.RequireTransportSecurity())
+
+	md, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, basicHeader("username", "password")["authorization"][0], md["authorization"])
+}
+
+func TestClientServerRoundTrip(t *testing.T) {
+	server, err := newServerAuth(&HtpasswdSettings{File: "./testdata/htpasswd"}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, server.Start(context.Background(), componenttest.NewNopHost()))
+
+	creds, err := newClientAuth(&ClientAuthSettings{Username: "username", Password: "password"}).PerRPCCredentials()
+	require.NoError(t, err)
+	md, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+
+	_, err = server.Authenticate(context.Background(), map[string][]string{"authorization": {md["authorization"]}})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basicauthextension
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+var (
+	errNoCredentialSource   = errors.New("either \"htpasswd\" or \"client_auth\" must be specified")
+	errMultipleAuthModes    = errors.New("\"htpasswd\" and \"client_auth\" cannot be specified at the same time")
+	errNoHtpasswdSource     = errors.New("either \"htpasswd.file\" or \"htpasswd.inline\" must be specified")
+	errNoClientAuthUsername = errors.New("\"client_auth.username\" must be specified")
+)
+
+// HtpasswdSettings defines where the users allowed to authenticate are read from.
+// Both sources can be used at the same time, in which case the inline users take precedence.
+type HtpasswdSettings struct {
+	// File is the path to an htpasswd file with bcrypt hashed passwords.
+	File string `mapstructure:"file"`
+
+	// Inline is the content of an htpasswd file with bcrypt hashed passwords.
+	Inline string `mapstructure:"inline"`
+}
+
+// ClientAuthSettings defines the credentials sent by clients.
+type ClientAuthSettings struct {
+	// Username to send on every request.
+	Username string `mapstructure:"username"`
+
+	// Password to send on every request.
+	Password string `mapstructure:"password"`
+}
+
+// Config has the configuration for the Basic Authenticator extension.
+// Exactly one of Htpasswd, to authenticate incoming requests, or ClientAuth, to
+// authenticate outgoing requests, must be set.
+type Config struct {
+	config.ExtensionSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Htpasswd configures the extension as a server authenticator.
+	Htpasswd *HtpasswdSettings `mapstructure:"htpasswd,omitempty"`
+
+	// ClientAuth configures the extension as a client authenticator.
+	ClientAuth *ClientAuthSettings `mapstructure:"client_auth,omitempty"`
+}
+
+var _ config.Extension = (*Config)(nil)
+
+// Validate checks if the extension configuration is valid
+func (cfg *Config) Validate() error {
+	switch {
+	case cfg.Htpasswd == nil && cfg.ClientAuth == nil:
+		return errNoCredentialSource
+	case cfg.Htpasswd != nil && cfg.ClientAuth != nil:
+		return errMultipleAuthModes
+	case cfg.Htpasswd != nil && cfg.Htpasswd.File == "" && cfg.Htpasswd.Inline == "":
+		return errNoHtpasswdSource
+	case cfg.ClientAuth != nil && cfg.ClientAuth.Username == "":
+		return errNoClientAuthUsername
+	}
+	return nil
+}
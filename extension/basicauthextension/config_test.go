@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basicauthextension
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+	cfg, err := configtest.LoadConfigAndValidate(path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: config.NewExtensionSettings(config.NewIDWithName(typeStr, "server")),
+			Htpasswd: &HtpasswdSettings{
+				File:   "./testdata/htpasswd",
+				Inline: "inline:$2a$04$OrIiq2OTr7poZH.DT2Ooxea3Lb1eCoC9r83nTvwDzolubmQVpJxka\n",
+			},
+		},
+		cfg.Extensions[config.NewIDWithName(typeStr, "server")])
+
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: config.NewExtensionSettings(config.NewIDWithName(typeStr, "client")),
+			ClientAuth: &ClientAuthSettings{
+				Username: "username",
+				Password: "password",
+			},
+		},
+		cfg.Extensions[config.NewIDWithName(typeStr, "client")])
+}
+
+func TestLoadConfigError(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "config_invalid.yaml"), factories)
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		err  error
+	}{
+		{
+			name: "empty",
+			cfg:  &Config{},
+			err:  errNoCredentialSource,
+		},
+		{
+			name: "both modes",
+			cfg:  &Config{Htpasswd: &HtpasswdSettings{File: "file"}, ClientAuth: &ClientAuthSettings{Username: "user"}},
+			err:  errMultipleAuthModes,
+		},
+		{
+			name: "no htpasswd source",
+			cfg:  &Config{Htpasswd: &HtpasswdSettings{}},
+			err:  errNoHtpasswdSource,
+		},
+		{
+			name: "no client username",
+			cfg:  &Config{ClientAuth: &ClientAuthSettings{Password: "password"}},
+			err:  errNoClientAuthUsername,
+		},
+		{
+			name: "client",
+			cfg:  &Config{ClientAuth: &ClientAuthSettings{Username: "user"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.cfg.Validate())
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basicauthextension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/configcheck"
+)
+
+func TestFactory_CreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.Equal(t, &Config{ExtensionSettings: config.NewExtensionSettings(config.NewID(typeStr))}, cfg)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestFactory_CreateExtension(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Htpasswd = &HtpasswdSettings{File: "./testdata/htpasswd"}
+	ext, err := createExtension(context.Background(), componenttest.NewNopExtensionCreateSettings(), cfg)
+	require.NoError(t, err)
+	assert.Implements(t, (*configauth.ServerAuthenticator)(nil), ext)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.ClientAuth = &ClientAuthSettings{Username: "username", Password: "password"}
+	ext, err = createExtension(context.Background(), componenttest.NewNopExtensionCreateSettings(), cfg)
+	require.NoError(t, err)
+	assert.Implements(t, (*configauth.HTTPClientAuthenticator)(nil), ext)
+	assert.Implements(t, (*configauth.GRPCClientAuthenticator)(nil), ext)
+}
+
+func TestFactory_CreateExtensionNoSettings(t *testing.T) {
+	_, err := createExtension(context.Background(), componenttest.NewNopExtensionCreateSettings(), createDefaultConfig())
+	assert.Equal(t, errNoHtpasswdSettings, err)
+}
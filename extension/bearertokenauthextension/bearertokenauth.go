@@ -17,24 +17,33 @@ package bearertokenauthextension
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
 
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/credentials"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/internal/filewatcher"
 )
 
+// reloadInterval is the interval at which the token file is checked for changes.
+var reloadInterval = 10 * time.Second
+
 var _ credentials.PerRPCCredentials = (*PerRPCAuth)(nil)
 
 // PerRPCAuth is a gRPC credentials.PerRPCCredentials implementation that returns an 'authorization' header.
 type PerRPCAuth struct {
-	metadata map[string]string
+	auth *BearerTokenAuth
 }
 
 // GetRequestMetadata returns the request metadata to be used with the RPC.
 func (c *PerRPCAuth) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
-	return c.metadata, nil
+	return map[string]string{"authorization": c.auth.bearerToken()}, nil
 }
 
 // RequireTransportSecurity always returns true for this implementation. Passing bearer tokens in plain-text connections is a bad idea.
@@ -42,34 +51,97 @@ func (c *PerRPCAuth) RequireTransportSecurity() bool {
 	return true
 }
 
-// BearerTokenAuth is an implementation of configauth.GRPCClientAuthenticator. It embeds a static authorization "bearer" token in every rpc call.
+// BearerTokenAuth is an implementation of configauth.GRPCClientAuthenticator and configauth.HTTPClientAuthenticator.
+// It embeds an authorization "bearer" token in every rpc call or request, either static or read from a file.
+// When read from a file, the token is reloaded every time the file changes.
 type BearerTokenAuth struct {
-	tokenString string
+	tokenString *atomic.String
+	filename    string
+	watcher     *filewatcher.Watcher
 	logger      *zap.Logger
 }
 
-var _ configauth.GRPCClientAuthenticator = (*BearerTokenAuth)(nil)
+var (
+	_ configauth.GRPCClientAuthenticator = (*BearerTokenAuth)(nil)
+	_ configauth.HTTPClientAuthenticator = (*BearerTokenAuth)(nil)
+)
 
 func newBearerTokenAuth(cfg *Config, logger *zap.Logger) *BearerTokenAuth {
 	return &BearerTokenAuth{
-		tokenString: cfg.BearerToken,
+		tokenString: atomic.NewString(cfg.BearerToken),
+		filename:    cfg.Filename,
 		logger:      logger,
 	}
 }
 
-// Start of BearerTokenAuth does nothing and returns nil
+// Start of BearerTokenAuth reads the token file and starts watching it, if a file is configured.
 func (b *BearerTokenAuth) Start(ctx context.Context, host component.Host) error {
+	if b.filename == "" {
+		return nil
+	}
+	if err := b.refreshToken(); err != nil {
+		return err
+	}
+	b.watcher = filewatcher.New(b.filename, reloadInterval, func() {
+		if err := b.refreshToken(); err != nil {
+			b.logger.Error("Failed to reload the bearer token, keeping the previous token", zap.Error(err))
+		}
+	})
+	b.watcher.Start()
 	return nil
 }
 
-// Shutdown of BearerTokenAuth does nothing and returns nil
+// Shutdown of BearerTokenAuth stops watching the token file.
 func (b *BearerTokenAuth) Shutdown(ctx context.Context) error {
+	if b.watcher != nil {
+		b.watcher.Stop()
+	}
+	return nil
+}
+
+// refreshToken reads the token from the token file.
+func (b *BearerTokenAuth) refreshToken() error {
+	content, err := ioutil.ReadFile(b.filename)
+	if err != nil {
+		return fmt.Errorf("failed to read the bearer token file: %w", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return fmt.Errorf("bearer token file %q is empty", b.filename)
+	}
+	b.tokenString.Store(token)
 	return nil
 }
 
+func (b *BearerTokenAuth) bearerToken() string {
+	return fmt.Sprintf("Bearer %s", b.tokenString.Load())
+}
+
 // PerRPCCredentials returns PerRPCAuth an implementation of credentials.PerRPCCredentials that
 func (b *BearerTokenAuth) PerRPCCredentials() (credentials.PerRPCCredentials, error) {
 	return &PerRPCAuth{
-		metadata: map[string]string{"authorization": fmt.Sprintf("Bearer %s", b.tokenString)},
+		auth: b,
+	}, nil
+}
+
+// RoundTripper returns BearerAuthRoundTripper an implementation of http.RoundTripper that adds the bearer token
+// to every request.
+func (b *BearerTokenAuth) RoundTripper(base http.RoundTripper) (http.RoundTripper, error) {
+	return &BearerAuthRoundTripper{
+		base: base,
+		auth: b,
 	}, nil
 }
+
+// BearerAuthRoundTripper is an http.RoundTripper that sets an 'Authorization' header on every request.
+type BearerAuthRoundTripper struct {
+	base http.RoundTripper
+	auth *BearerTokenAuth
+}
+
+// RoundTrip modifies a copy of the original request by adding the 'Authorization' header.
+func (r *BearerAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", r.auth.bearerToken())
+	return r.base.RoundTrip(req2)
+}
@@ -17,9 +17,16 @@ package bearertokenauthextension
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component/componenttest"
 )
@@ -30,7 +37,7 @@ func TestPerRPCAuth(t *testing.T) {
 	}
 
 	// test meta data is properly
-	perRPCAuth := &PerRPCAuth{metadata: metadata}
+	perRPCAuth := &PerRPCAuth{auth: newBearerTokenAuth(&Config{BearerToken: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."}, nil)}
 	md, err := perRPCAuth.GetRequestMetadata(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, md, metadata)
@@ -63,3 +70,72 @@ func TestBearerAuthenticator(t *testing.T) {
 	assert.True(t, credential.RequireTransportSecurity())
 	assert.Nil(t, bauth.Shutdown(context.Background()))
 }
+
+func TestBearerAuthenticatorHTTP(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.BearerToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	bauth := newBearerTokenAuth(cfg, nil)
+	rt, err := bauth.RoundTripper(http.DefaultTransport)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, fmt.Sprintf("Bearer %s", cfg.BearerToken), authorization)
+	// the original request must not be modified
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
+
+func TestBearerAuthenticatorFileRotation(t *testing.T) {
+	oldInterval := reloadInterval
+	reloadInterval = 10 * time.Millisecond
+	defer func() { reloadInterval = oldInterval }()
+
+	filename := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("first-token\n"), 0600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Filename = filename
+
+	bauth := newBearerTokenAuth(cfg, zap.NewNop())
+	require.NoError(t, bauth.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, bauth.Shutdown(context.Background())) }()
+
+	credential, err := bauth.PerRPCCredentials()
+	require.NoError(t, err)
+	md, err := credential.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer first-token"}, md)
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte("second-token\n"), 0600))
+	assert.Eventually(t, func() bool {
+		md, err = credential.GetRequestMetadata(context.Background())
+		return err == nil && md["authorization"] == "Bearer second-token"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// an empty file keeps the previous token
+	require.NoError(t, ioutil.WriteFile(filename, nil, 0600))
+	time.Sleep(50 * time.Millisecond)
+	md, err = credential.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer second-token"}, md)
+}
+
+func TestBearerAuthenticatorMissingFile(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Filename = filepath.Join(t.TempDir(), "missing")
+
+	bauth := newBearerTokenAuth(cfg, zap.NewNop())
+	assert.Error(t, bauth.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, bauth.Shutdown(context.Background()))
+}
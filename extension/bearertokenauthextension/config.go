@@ -26,15 +26,23 @@ type Config struct {
 
 	// BearerToken specifies the bearer token to use for every RPC.
 	BearerToken string `mapstructure:"token,omitempty"`
+
+	// Filename points to a file containing the bearer token to use for every RPC.
+	// The file is watched, and the token is reloaded whenever the file changes.
+	Filename string `mapstructure:"filename,omitempty"`
 }
 
 var _ config.Extension = (*Config)(nil)
 var errNoTokenProvided = errors.New("no bearer token provided")
+var errTokenAndFilenameProvided = errors.New("either a bearer token or a filename must be provided, not both")
 
 // Validate checks if the extension configuration is valid
 func (cfg *Config) Validate() error {
-	if cfg.BearerToken == "" {
+	if cfg.BearerToken == "" && cfg.Filename == "" {
 		return errNoTokenProvided
 	}
+	if cfg.BearerToken != "" && cfg.Filename != "" {
+		return errTokenAndFilenameProvided
+	}
 	return nil
 }
@@ -51,6 +51,14 @@ func TestLoadConfig(t *testing.T) {
 		},
 		ext1)
 
+	ext2 := cfg.Extensions[config.NewIDWithName(typeStr, "file")]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: config.NewExtensionSettings(config.NewIDWithName(typeStr, "file")),
+			Filename:          "/var/run/secrets/token",
+		},
+		ext2)
+
 	assert.Equal(t, 1, len(cfg.Service.Extensions))
 	assert.Equal(t, config.NewIDWithName(typeStr, "1"), cfg.Service.Extensions[0])
 }
@@ -63,4 +71,7 @@ func TestLoadConfigError(t *testing.T) {
 	factories.Extensions[typeStr] = factory
 	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "config_missing_token.yaml"), factories)
 	require.Error(t, err)
+
+	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "config_token_and_file.yaml"), factories)
+	require.Error(t, err)
 }
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2clientauthextension
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+	cfg, err := configtest.LoadConfigAndValidate(path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: config.NewExtensionSettings(config.NewID(typeStr)),
+			ClientID:          "someclientid",
+			ClientSecret:      "someclientsecret",
+			TokenURL:          "https://example.com/oauth2/default/v1/token",
+			Scopes:            []string{"api.metrics"},
+			Audience:          "https://example.com/api",
+			Timeout:           time.Second,
+		},
+		cfg.Extensions[config.NewID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: config.NewExtensionSettings(config.NewIDWithName(typeStr, "withtls")),
+			ClientID:          "someclientid2",
+			ClientSecret:      "someclientsecret2",
+			TokenURL:          "https://example2.com/oauth2/default/v1/token",
+			Timeout:           defaultTimeout,
+			TLSSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{
+					CAFile:   "cafile",
+					CertFile: "certfile",
+					KeyFile:  "keyfile",
+				},
+				Insecure: true,
+			},
+		},
+		cfg.Extensions[config.NewIDWithName(typeStr, "withtls")])
+}
+
+func TestLoadConfigError(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "config_missing_client_id.yaml"), factories)
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		err  error
+	}{
+		{
+			name: "missing client id",
+			cfg:  &Config{ClientSecret: "secret", TokenURL: "https://example.com/token"},
+			err:  errNoClientIDProvided,
+		},
+		{
+			name: "missing client secret",
+			cfg:  &Config{ClientID: "id", TokenURL: "https://example.com/token"},
+			err:  errNoClientSecretProvided,
+		},
+		{
+			name: "missing token url",
+			cfg:  &Config{ClientID: "id", ClientSecret: "secret"},
+			err:  errNoTokenURLProvided,
+		},
+		{
+			name: "valid",
+			cfg:  &Config{ClientID: "id", ClientSecret: "secret", TokenURL: "https://example.com/token"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.cfg.Validate())
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2clientauthextension
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/grpc/credentials"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
+)
+
+// clientCredentialsAuthenticator provides implementation for providing client authentication using OAuth2 client credentials
+// workflow for both gRPC and HTTP clients.
+type clientCredentialsAuthenticator struct {
+	// tokenSource fetches the access tokens and caches them until they are about to expire.
+	// It is shared by all the clients using this extension.
+	tokenSource oauth2.TokenSource
+	logger      *zap.Logger
+}
+
+var (
+	_ configauth.HTTPClientAuthenticator = (*clientCredentialsAuthenticator)(nil)
+	_ configauth.GRPCClientAuthenticator = (*clientCredentialsAuthenticator)(nil)
+)
+
+func newClientCredentialsAuthenticator(cfg *Config, logger *zap.Logger) (*clientCredentialsAuthenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsCfg, err := cfg.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsCfg
+
+	var endpointParams url.Values
+	if cfg.Audience != "" {
+		endpointParams = url.Values{"audience": {cfg.Audience}}
+	}
+	clientCredentials := &clientcredentials.Config{
+		ClientID:       cfg.ClientID,
+		ClientSecret:   cfg.ClientSecret,
+		TokenURL:       cfg.TokenURL,
+		Scopes:         cfg.Scopes,
+		EndpointParams: endpointParams,
+	}
+
+	// The HTTP client used for the token requests is passed through the context, see oauth2.HTTPClient.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+	})
+
+	return &clientCredentialsAuthenticator{
+		// The token source returned by clientcredentials caches the token, and fetches
+		// a new one shortly before the current one expires.
+		tokenSource: clientCredentials.TokenSource(ctx),
+		logger:      logger,
+	}, nil
+}
+
+// Start for clientCredentialsAuthenticator extension does nothing
+func (o *clientCredentialsAuthenticator) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+// Shutdown for clientCredentialsAuthenticator extension does nothing
+func (o *clientCredentialsAuthenticator) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// RoundTripper returns oauth2.Transport, an http.RoundTripper that performs "client-credential" OAuth flow and
+// also auto refreshes OAuth tokens as needed.
+func (o *clientCredentialsAuthenticator) RoundTripper(base http.RoundTripper) (http.RoundTripper, error) {
+	return &oauth2.Transport{
+		Source: o.tokenSource,
+		Base:   base,
+	}, nil
+}
+
+// PerRPCCredentials returns gRPC PerRPCCredentials that supports "client-credential" OAuth flow. The underneath
+// oauth2.clientcredentials.Config instance will manage tokens performing auto refresh as necessary.
+func (o *clientCredentialsAuthenticator) PerRPCCredentials() (credentials.PerRPCCredentials, error) {
+	return &perRPCCredentials{tokenSource: o.tokenSource}, nil
+}
+
+var _ credentials.PerRPCCredentials = (*perRPCCredentials)(nil)
+
+// perRPCCredentials is a gRPC credentials.PerRPCCredentials implementation that returns an 'authorization'
+// header with the current access token.
+type perRPCCredentials struct {
+	tokenSource oauth2.TokenSource
+}
+
+// GetRequestMetadata returns the request metadata to be used with the RPC.
+func (c *perRPCCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token, err := c.tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get an OAuth2 access token: %w", err)
+	}
+	return map[string]string{"authorization": token.Type() + " " + token.AccessToken}, nil
+}
+
+// RequireTransportSecurity always returns true for this implementation. Passing access tokens in plain-text connections is a bad idea.
+func (c *perRPCCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2clientauthextension
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// tokenServer is a fake OAuth2 token endpoint issuing sequentially numbered tokens.
+type tokenServer struct {
+	*httptest.Server
+	requests  *atomic.Int32
+	expiresIn int
+	lastForm  map[string]string
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	ts := &tokenServer{requests: atomic.NewInt32(0), expiresIn: expiresIn}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "someclientid" || secret != "someclientsecret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		ts.lastForm = map[string]string{}
+		for k := range r.PostForm {
+			ts.lastForm[k] = r.PostForm.Get(k)
+		}
+		n := ts.requests.Inc()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, n, ts.expiresIn)
+	}))
+	return ts
+}
+
+func newTestAuthenticator(t *testing.T, tokenURL string) *clientCredentialsAuthenticator {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientID = "someclientid"
+	cfg.ClientSecret = "someclientsecret"
+	cfg.TokenURL = tokenURL
+	cfg.Scopes = []string{"api.metrics", "api.traces"}
+	cfg.Audience = "https://example.com/api"
+
+	ext, err := newClientCredentialsAuthenticator(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	return ext
+}
+
+func TestRoundTripper(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	defer ts.Close()
+
+	var authorization string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer backend.Close()
+
+	ext := newTestAuthenticator(t, ts.URL)
+	defer func() { assert.NoError(t, ext.Shutdown(context.Background())) }()
+
+	rt, err := ext.RoundTripper(http.DefaultTransport)
+	require.NoError(t, err)
+	client := &http.Client{Transport: rt}
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(backend.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, "Bearer token-1", authorization)
+	}
+
+	// the token is cached until it expires
+	assert.EqualValues(t, 1, ts.requests.Load())
+	assert.Equal(t, map[string]string{
+		"grant_type": "client_credentials",
+		"scope":      "api.metrics api.traces",
+		"audience":   "https://example.com/api",
+	}, ts.lastForm)
+}
+
+func TestPerRPCCredentials(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	defer ts.Close()
+
+	ext := newTestAuthenticator(t, ts.URL)
+	creds, err := ext.PerRPCCredentials()
+	require.NoError(t, err)
+	assert.True(t, creds.RequireTransportSecurity())
+
+	md, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer token-1"}, md)
+
+	// the token is shared with the HTTP clients
+	rt, err := ext.RoundTripper(http.DefaultTransport)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.EqualValues(t, 1, ts.requests.Load())
+}
+
+func TestTokenRefreshedBeforeExpiry(t *testing.T) {
+	// Tokens expiring within the next 10 seconds are considered expired,
+	// so every call gets a new token.
+	ts := newTokenServer(t, 5)
+	defer ts.Close()
+
+	ext := newTestAuthenticator(t, ts.URL)
+	creds, err := ext.PerRPCCredentials()
+	require.NoError(t, err)
+
+	md, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", md["authorization"])
+
+	md, err = creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", md["authorization"])
+}
+
+func TestTokenRequestFailure(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	defer ts.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientID = "someclientid"
+	cfg.ClientSecret = "wrongsecret"
+	cfg.TokenURL = ts.URL
+	ext, err := newClientCredentialsAuthenticator(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	creds, err := ext.PerRPCCredentials()
+	require.NoError(t, err)
+	_, err = creds.GetRequestMetadata(context.Background())
+	assert.Error(t, err)
+
+	rt, err := ext.RoundTripper(http.DefaultTransport)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	assert.Error(t, err)
+}
+
+func TestInvalidTLSSettings(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientID = "someclientid"
+	cfg.ClientSecret = "someclientsecret"
+	cfg.TokenURL = "https://example.com/token"
+	cfg.TLSSetting = configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: "/nonexistent/ca.pem"},
+	}
+	_, err := newClientCredentialsAuthenticator(cfg, zap.NewNop())
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filewatcher exposes util functionality for components that need to
+// reload the content of a file, such as credentials, when it changes on disk.
+package filewatcher
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// Watcher polls a file at a fixed interval and calls a function every time
+// the modification time or the size of the file changes. Polling is used,
+// rather than file system notifications, so that files replaced through
+// symlinks (e.g. Kubernetes mounted secrets) are also detected.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	onChange func()
+
+	lastMod  time.Time
+	lastSize int64
+
+	stopOnce sync.Once
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// New returns a Watcher for the file at path. The onChange function is called
+// from the Watcher goroutine, so it must be safe for concurrent use with the
+// rest of the component.
+func New(path string, interval time.Duration, onChange func()) *Watcher {
+	return &Watcher{
+		path:     path,
+		interval: interval,
+		onChange: onChange,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start records the current state of the file and starts polling it.
+// The onChange function is not called for the initial state.
+func (w *Watcher) Start() {
+	w.changed()
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if w.changed() {
+					w.onChange()
+				}
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops polling the file and waits for any in-flight onChange call to return.
+// It is safe to call Stop multiple times.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+}
+
+// changed updates the recorded state of the file, and reports whether it
+// differs from the previous one. Files that cannot be read are reported as
+// unchanged, so that the last good content is kept.
+func (w *Watcher) changed() bool {
+	fi, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+	if fi.ModTime().Equal(w.lastMod) && fi.Size() == w.lastSize {
+		return false
+	}
+	w.lastMod = fi.ModTime()
+	w.lastSize = fi.Size()
+	return true
+}
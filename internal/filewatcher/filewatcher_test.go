@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filewatcher
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, ioutil.WriteFile(path, []byte("first"), 0600))
+
+	calls := atomic.NewInt32(0)
+	w := New(path, 10*time.Millisecond, func() { calls.Inc() })
+	w.Start()
+	defer w.Stop()
+
+	// No change, no call.
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 0, calls.Load())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("second content"), 0600))
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestWatcherMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+
+	calls := atomic.NewInt32(0)
+	w := New(path, 10*time.Millisecond, func() { calls.Inc() })
+	w.Start()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 0, calls.Load())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("created"), 0600))
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	w.Stop()
+	// Stopping twice must not panic.
+	w.Stop()
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package goldendataset
+
+import (
+	"path/filepath"
+
+	"go.opentelemetry.io/collector/model/otlp"
+)
+
+// Payload is the encoding of a set of generated telemetry data, e.g. to benchmark the compression of the requests.
+type Payload struct {
+	Name string
+	Data []byte
+}
+
+// GenerateOTLPPayloads returns the OTLP protobuf encoding of all the traces and of all the metrics generated from
+// the PICT-generated pairwise parameters of the testdata directory of this package, found at testdataDir.
+func GenerateOTLPPayloads(testdataDir string) ([]Payload, error) {
+	tds, err := GenerateTraces(
+		filepath.Join(testdataDir, "generated_pict_pairs_traces.txt"),
+		filepath.Join(testdataDir, "generated_pict_pairs_spans.txt"))
+	if err != nil {
+		return nil, err
+	}
+	var traces []byte
+	for _, td := range tds {
+		buf, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(td)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, buf...)
+	}
+
+	mds, err := GenerateMetrics(filepath.Join(testdataDir, "generated_pict_pairs_metrics.txt"))
+	if err != nil {
+		return nil, err
+	}
+	var metrics []byte
+	for _, md := range mds {
+		buf, err := otlp.NewProtobufMetricsMarshaler().MarshalMetrics(md)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, buf...)
+	}
+	return []Payload{{Name: "traces", Data: traces}, {Name: "metrics", Data: metrics}}, nil
+}
@@ -18,23 +18,68 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 )
 
 const (
 	headerContentEncoding = "Content-Encoding"
 	headerValueGZIP       = "gzip"
+	headerValueZstd       = "zstd"
+	headerValueSnappy     = "snappy"
+
+	// MaxZstdDecoderMemory is the maximum window size of the zstd frames, and the maximum decoded size of the zstd
+	// messages decoded as a whole, so that the frames declaring larger sizes are refused before their buffers are
+	// allocated.
+	MaxZstdDecoderMemory = 64 * 1024 * 1024
+
+	// DefaultMaxDecodedSnappySize is the default maximum decoded size of the snappy request bodies, which are
+	// decoded as a whole in a buffer of the size claimed by their header.
+	DefaultMaxDecodedSnappySize = 64 * 1024 * 1024
 )
 
+// errBodyTooLarge is returned for the bodies whose decoded size is larger than the maximum.
+var errBodyTooLarge = errors.New("decompressed request body too large")
+
+// compressors creates the writers compressing the request bodies, by content encoding.
+var compressors = map[string]func(w io.Writer) (io.WriteCloser, error){
+	headerValueGZIP: func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	},
+	headerValueZstd: func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	},
+	headerValueSnappy: func(w io.Writer) (io.WriteCloser, error) {
+		return &snappyWriter{w: w}, nil
+	},
+}
+
 type CompressRoundTripper struct {
 	http.RoundTripper
+	contentEncoding string
+	newWriter       func(w io.Writer) (io.WriteCloser, error)
 }
 
-func NewCompressRoundTripper(rt http.RoundTripper) *CompressRoundTripper {
-	return &CompressRoundTripper{
-		RoundTripper: rt,
+// NewCompressRoundTripper returns a RoundTripper compressing the request bodies with the given
+// compression type, one of "gzip", "zstd" and "snappy".
+func NewCompressRoundTripper(rt http.RoundTripper, compressionType string) (*CompressRoundTripper, error) {
+	contentEncoding := strings.ToLower(compressionType)
+	newWriter, ok := compressors[contentEncoding]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression type %q", compressionType)
 	}
+	return &CompressRoundTripper{
+		RoundTripper:    rt,
+		contentEncoding: contentEncoding,
+		newWriter:       newWriter,
+	}, nil
 }
 
 func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -47,13 +92,16 @@ func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 		return r.RoundTripper.RoundTrip(req)
 	}
 
-	// Gzip the body.
+	// Compress the body.
 	buf := bytes.NewBuffer([]byte{})
-	gzipWriter := gzip.NewWriter(buf)
-	_, copyErr := io.Copy(gzipWriter, req.Body)
+	compressWriter, err := r.newWriter(buf)
+	if err != nil {
+		return nil, err
+	}
+	_, copyErr := io.Copy(compressWriter, req.Body)
 	closeErr := req.Body.Close()
 
-	if err := gzipWriter.Close(); err != nil {
+	if err = compressWriter.Close(); err != nil {
 		return nil, err
 	}
 
@@ -71,9 +119,9 @@ func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 		return nil, err
 	}
 
-	// Clone the headers and add gzip encoding header.
+	// Clone the headers and add the encoding header.
 	cReq.Header = req.Header.Clone()
-	cReq.Header.Add(headerContentEncoding, headerValueGZIP)
+	cReq.Header.Add(headerContentEncoding, r.contentEncoding)
 
 	return r.RoundTripper.RoundTrip(cReq)
 }
@@ -81,7 +129,8 @@ func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 type ErrorHandler func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int)
 
 type decompressor struct {
-	errorHandler ErrorHandler
+	errorHandler   ErrorHandler
+	maxDecodedSize int64
 }
 
 type DecompressorOption func(d *decompressor)
@@ -92,12 +141,21 @@ func WithErrorHandler(e ErrorHandler) DecompressorOption {
 	}
 }
 
+// WithMaxDecodedSize limits the decoded size of the bodies decoded as a whole, i.e. the snappy ones, which are
+// refused with 413 Request Entity Too Large before being decoded if their header claims a larger size.
+// Defaults to DefaultMaxDecodedSnappySize.
+func WithMaxDecodedSize(size int64) DecompressorOption {
+	return func(d *decompressor) {
+		d.maxDecodedSize = size
+	}
+}
+
 // HTTPContentDecompressor is a middleware that offloads the task of handling compressed
 // HTTP requests by identifying the compression format in the "Content-Encoding" header and re-writing
 // request body so that the handlers further in the chain can work on decompressed data.
-// It supports gzip and deflate/zlib compression.
+// It supports gzip, deflate/zlib, zstd and snappy compression.
 func HTTPContentDecompressor(h http.Handler, opts ...DecompressorOption) http.Handler {
-	d := &decompressor{}
+	d := &decompressor{maxDecodedSize: DefaultMaxDecodedSnappySize}
 	for _, o := range opts {
 		o(d)
 	}
@@ -109,7 +167,11 @@ func HTTPContentDecompressor(h http.Handler, opts ...DecompressorOption) http.Ha
 
 func (d *decompressor) wrap(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		newBody, err := newBodyReader(r)
+		newBody, err := d.newBodyReader(r)
+		if errors.Is(err, errBodyTooLarge) {
+			d.errorHandler(w, r, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		if err != nil {
 			d.errorHandler(w, r, err.Error(), http.StatusBadRequest)
 			return
@@ -128,7 +190,7 @@ func (d *decompressor) wrap(h http.Handler) http.Handler {
 	})
 }
 
-func newBodyReader(r *http.Request) (io.ReadCloser, error) {
+func (d *decompressor) newBodyReader(r *http.Request) (io.ReadCloser, error) {
 	switch r.Header.Get("Content-Encoding") {
 	case "gzip":
 		gr, err := gzip.NewReader(r.Body)
@@ -142,10 +204,69 @@ func newBodyReader(r *http.Request) (io.ReadCloser, error) {
 			return nil, err
 		}
 		return zr, nil
+	case headerValueZstd:
+		zr, err := zstd.NewReader(r.Body, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(MaxZstdDecoderMemory))
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	case headerValueSnappy:
+		// The snappy block format, as used by Prometheus remote write, can only be decoded as a whole.
+		body, err := DecodeSnappy(r.Body, d.maxDecodedSize)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
 	}
 	return nil, nil
 }
 
+// DecodeSnappy reads and decodes the snappy block of r, refusing the blocks whose decoded size, as claimed by their
+// header, is larger than maxDecodedSize before allocating it. The returned error wraps errBodyTooLarge for the
+// blocks that are too large, see IsBodyTooLarge.
+func DecodeSnappy(r io.Reader, maxDecodedSize int64) ([]byte, error) {
+	// A block whose compressed size is over the maximum encoded size of maxDecodedSize decodes to more.
+	maxEncodedSize := int64(snappy.MaxEncodedLen(int(maxDecodedSize)))
+	if maxEncodedSize >= 0 {
+		r = io.LimitReader(r, maxEncodedSize+1)
+	}
+	compressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if maxEncodedSize >= 0 && int64(len(compressed)) > maxEncodedSize {
+		return nil, fmt.Errorf("%w: compressed size larger than %d bytes", errBodyTooLarge, maxEncodedSize)
+	}
+	decodedLen, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, err
+	}
+	if int64(decodedLen) > maxDecodedSize {
+		return nil, fmt.Errorf("%w: decoded size of %d bytes larger than the maximum of %d bytes", errBodyTooLarge, decodedLen, maxDecodedSize)
+	}
+	return snappy.Decode(nil, compressed)
+}
+
+// IsBodyTooLarge returns whether the error was returned for a body whose decoded size is too large.
+func IsBodyTooLarge(err error) bool {
+	return errors.Is(err, errBodyTooLarge)
+}
+
+// snappyWriter compresses everything written to it in a single snappy block, written on Close.
+type snappyWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (s *snappyWriter) Write(p []byte) (int, error) {
+	return s.buf.Write(p)
+}
+
+func (s *snappyWriter) Close() error {
+	_, err := s.w.Write(snappy.Encode(nil, s.buf.Bytes()))
+	return err
+}
+
 // defaultErrorHandler writes the error message in plain text.
 func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, errMsg string, statusCode int) {
 	http.Error(w, errMsg, statusCode)
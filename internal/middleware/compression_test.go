@@ -18,22 +18,29 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"encoding/binary"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/internal/goldendataset"
 	"go.opentelemetry.io/collector/testutil"
 )
 
 func TestHTTPClientCompression(t *testing.T) {
 	testBody := []byte("uncompressed_text")
-	compressedBody, _ := compressGzip(testBody)
+	compressedGzipBody, _ := compressGzip(testBody)
+	compressedZstdBody, _ := compressZstd(testBody)
+	compressedSnappyBody, _ := compressSnappy(testBody)
 
 	tests := []struct {
 		name     string
@@ -48,7 +55,17 @@ func TestHTTPClientCompression(t *testing.T) {
 		{
 			name:     "ValidGzip",
 			encoding: "gzip",
-			reqBody:  compressedBody.Bytes(),
+			reqBody:  compressedGzipBody.Bytes(),
+		},
+		{
+			name:     "ValidZstd",
+			encoding: "zstd",
+			reqBody:  compressedZstdBody.Bytes(),
+		},
+		{
+			name:     "ValidSnappy",
+			encoding: "snappy",
+			reqBody:  compressedSnappyBody.Bytes(),
 		},
 	}
 	for _, tt := range tests {
@@ -57,6 +74,7 @@ func TestHTTPClientCompression(t *testing.T) {
 				body, err := ioutil.ReadAll(r.Body)
 				require.NoError(t, err, "failed to read request body: %v", err)
 				assert.EqualValues(t, tt.reqBody, body)
+				assert.Equal(t, tt.encoding, r.Header.Get("Content-Encoding"))
 				w.WriteHeader(200)
 			})
 
@@ -79,8 +97,9 @@ func TestHTTPClientCompression(t *testing.T) {
 			require.NoError(t, err, "failed to create request to test handler")
 
 			client := http.Client{}
-			if tt.encoding == "gzip" {
-				client.Transport = NewCompressRoundTripper(http.DefaultTransport)
+			if tt.encoding != "" {
+				client.Transport, err = NewCompressRoundTripper(http.DefaultTransport, tt.encoding)
+				require.NoError(t, err)
 			}
 			res, err := client.Do(req)
 			require.NoError(t, err)
@@ -93,6 +112,11 @@ func TestHTTPClientCompression(t *testing.T) {
 	}
 }
 
+func TestHTTPClientCompressionUnsupported(t *testing.T) {
+	_, err := NewCompressRoundTripper(http.DefaultTransport, "gzip2")
+	assert.EqualError(t, err, `unsupported compression type "gzip2"`)
+}
+
 func TestHTTPContentDecompressionHandler(t *testing.T) {
 	testBody := []byte("uncompressed_text")
 	tests := []struct {
@@ -126,6 +150,22 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 			},
 			respCode: 200,
 		},
+		{
+			name:     "ValidZstd",
+			encoding: "zstd",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				return compressZstd(testBody)
+			},
+			respCode: 200,
+		},
+		{
+			name:     "ValidSnappy",
+			encoding: "snappy",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				return compressSnappy(testBody)
+			},
+			respCode: 200,
+		},
 		{
 			name:     "InvalidGzip",
 			encoding: "gzip",
@@ -145,6 +185,26 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 			respCode: 400,
 			respBody: "zlib: invalid header\n",
 		},
+		{
+			name:     "InvalidSnappy",
+			encoding: "snappy",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				return bytes.NewBuffer(testBody), nil
+			},
+			respCode: 400,
+			respBody: "snappy: corrupt input\n",
+		},
+		{
+			name:     "SnappyTooLarge",
+			encoding: "snappy",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				// A snappy block header claiming 1 GiB of decoded data, without the data.
+				header := make([]byte, binary.MaxVarintLen64)
+				return bytes.NewBuffer(header[:binary.PutUvarint(header, 1<<30)]), nil
+			},
+			respCode: 413,
+			respBody: "decompressed request body too large: decoded size of 1073741824 bytes larger than the maximum of 67108864 bytes\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -217,3 +277,116 @@ func compressZlib(body []byte) (*bytes.Buffer, error) {
 
 	return &buf, nil
 }
+
+func compressZstd(body []byte) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+
+	zw, err := zstd.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	defer zw.Close()
+
+	_, err = zw.Write(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+func TestZstdMaxWindowSize(t *testing.T) {
+	// A zstd frame declaring a 256 MiB window, with a single raw block of 1 byte.
+	frame := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 18 << 3, 0x09, 0x00, 0x00, 'x'}
+	req, err := http.NewRequest("POST", "http://localhost", bytes.NewReader(frame))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", headerValueZstd)
+
+	body, err := (&decompressor{}).newBodyReader(req)
+	if err == nil {
+		_, err = ioutil.ReadAll(body)
+		require.NoError(t, body.Close())
+	}
+	assert.Error(t, err)
+
+	// The same frame with a 1 MiB window is decoded.
+	frame[5] = 10 << 3
+	req, err = http.NewRequest("POST", "http://localhost", bytes.NewReader(frame))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", headerValueZstd)
+	body, err = (&decompressor{}).newBodyReader(req)
+	require.NoError(t, err)
+	decoded, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "x", string(decoded))
+}
+
+func TestDecodeSnappy(t *testing.T) {
+	testBody := "uncompressed_text"
+	compressed := snappy.Encode(nil, []byte(testBody))
+
+	body, err := DecodeSnappy(bytes.NewReader(compressed), int64(len(testBody)))
+	require.NoError(t, err)
+	assert.Equal(t, testBody, string(body))
+
+	_, err = DecodeSnappy(bytes.NewReader(compressed), int64(len(testBody)-1))
+	assert.True(t, IsBodyTooLarge(err))
+
+	// The compressed size alone is over the maximum encoded size of the maximum decoded size.
+	_, err = DecodeSnappy(bytes.NewReader(bytes.Repeat([]byte{0}, 1024)), 8)
+	assert.True(t, IsBodyTooLarge(err))
+
+	// A corrupt block whose header claims 5 bytes.
+	_, err = DecodeSnappy(bytes.NewReader([]byte{0x05, 0xff}), int64(len(testBody)))
+	assert.Error(t, err)
+	assert.False(t, IsBodyTooLarge(err))
+}
+
+func compressSnappy(body []byte) (*bytes.Buffer, error) {
+	return bytes.NewBuffer(snappy.Encode(nil, body)), nil
+}
+
+func BenchmarkHTTPCompression(b *testing.B) {
+	payloads, err := goldendataset.GenerateOTLPPayloads(filepath.Join("..", "goldendataset", "testdata"))
+	require.NoError(b, err)
+	for _, encoding := range []string{headerValueGZIP, headerValueZstd, headerValueSnappy} {
+		for _, p := range payloads {
+			payload := p.Data
+			b.Run(encoding+"/"+p.Name+"/compress", func(b *testing.B) {
+				b.SetBytes(int64(len(payload)))
+				b.ReportAllocs()
+				var compressedSize int
+				for i := 0; i < b.N; i++ {
+					compressedSize = compressBody(b, encoding, payload).Len()
+				}
+				b.ReportMetric(float64(len(payload))/float64(compressedSize), "ratio")
+			})
+			b.Run(encoding+"/"+p.Name+"/decompress", func(b *testing.B) {
+				compressed := compressBody(b, encoding, payload).Bytes()
+				b.SetBytes(int64(len(payload)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					req, err := http.NewRequest("POST", "http://localhost", bytes.NewReader(compressed))
+					require.NoError(b, err)
+					req.Header.Set("Content-Encoding", encoding)
+					body, err := (&decompressor{maxDecodedSize: DefaultMaxDecodedSnappySize}).newBodyReader(req)
+					require.NoError(b, err)
+					_, err = ioutil.ReadAll(body)
+					require.NoError(b, err)
+					require.NoError(b, body.Close())
+				}
+			})
+		}
+	}
+}
+
+func compressBody(b *testing.B, encoding string, body []byte) *bytes.Buffer {
+	var buf bytes.Buffer
+	w, err := compressors[encoding](&buf)
+	require.NoError(b, err)
+	_, err = w.Write(body)
+	require.NoError(b, err)
+	require.NoError(b, w.Close())
+	return &buf
+}
@@ -43,11 +43,17 @@ const (
 	// RefusedLogRecordsKey used to identify log records refused (ie.: not ingested) by the
 	// Collector.
 	RefusedLogRecordsKey = "refused_log_records"
+
+	// RefusedRequestsKey used to identify requests refused by the Collector before their data was read.
+	RefusedRequestsKey = "refused_requests"
+	// ReasonKey used to identify why a request was refused.
+	ReasonKey = "reason"
 )
 
 var (
 	TagKeyReceiver, _  = tag.NewKey(ReceiverKey)
 	TagKeyTransport, _ = tag.NewKey(TransportKey)
+	TagKeyReason, _    = tag.NewKey(ReasonKey)
 
 	ReceiverPrefix                  = ReceiverKey + NameSep
 	ReceiveTraceDataOperationSuffix = NameSep + "TraceDataReceived"
@@ -83,4 +89,8 @@ var (
 		ReceiverPrefix+RefusedLogRecordsKey,
 		"Number of log records that could not be pushed into the pipeline.",
 		stats.UnitDimensionless)
+	ReceiverRefusedRequests = stats.Int64(
+		ReceiverPrefix+RefusedRequestsKey,
+		"Number of requests refused before their data was read, e.g. because of the server limits.",
+		stats.UnitDimensionless)
 )
@@ -65,6 +65,14 @@ func allViews() *ObsMetrics {
 	}
 	views = append(views, genViews(measures, tagKeys, view.Sum())...)
 
+	measures = []*stats.Int64Measure{
+		obsmetrics.ReceiverRefusedRequests,
+	}
+	tagKeys = []tag.Key{
+		obsmetrics.TagKeyReceiver, obsmetrics.TagKeyTransport, obsmetrics.TagKeyReason,
+	}
+	views = append(views, genViews(measures, tagKeys, view.Sum())...)
+
 	// Scraper views.
 	measures = []*stats.Int64Measure{
 		obsmetrics.ScraperScrapedMetricPoints,
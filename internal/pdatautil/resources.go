@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+// Package pdatautil provides helper functions to handle the pdata of the exporters.
+package pdatautil
+
+import (
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// TracesFrom returns the traces of the resources of td from the index start, e.g. to retry the resources
+// following the ones already exported.
+func TracesFrom(td pdata.Traces, start int) pdata.Traces {
+	remaining := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	for i := start; i < rss.Len(); i++ {
+		rss.At(i).CopyTo(remaining.ResourceSpans().AppendEmpty())
+	}
+	return remaining
+}
+
+// MetricsFrom returns the metrics of the resources of md from the index start, e.g. to retry the resources
+// following the ones already exported.
+func MetricsFrom(md pdata.Metrics, start int) pdata.Metrics {
+	remaining := pdata.NewMetrics()
+	rms := md.ResourceMetrics()
+	for i := start; i < rms.Len(); i++ {
+		rms.At(i).CopyTo(remaining.ResourceMetrics().AppendEmpty())
+	}
+	return remaining
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package pdatautil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestTracesFrom(t *testing.T) {
+	td := pdata.NewTraces()
+	for _, name := range []string{"a", "b", "c"} {
+		td.ResourceSpans().AppendEmpty().Resource().Attributes().InsertString("service.name", name)
+	}
+
+	remaining := TracesFrom(td, 1)
+	assert.Equal(t, 2, remaining.ResourceSpans().Len())
+	assert.Equal(t, td.ResourceSpans().At(1), remaining.ResourceSpans().At(0))
+	assert.Equal(t, td.ResourceSpans().At(2), remaining.ResourceSpans().At(1))
+	assert.Equal(t, 3, td.ResourceSpans().Len())
+
+	assert.Equal(t, 0, TracesFrom(td, 3).ResourceSpans().Len())
+}
+
+func TestMetricsFrom(t *testing.T) {
+	md := pdata.NewMetrics()
+	for _, name := range []string{"a", "b", "c"} {
+		md.ResourceMetrics().AppendEmpty().Resource().Attributes().InsertString("service.name", name)
+	}
+
+	remaining := MetricsFrom(md, 2)
+	assert.Equal(t, 1, remaining.ResourceMetrics().Len())
+	assert.Equal(t, md.ResourceMetrics().At(2), remaining.ResourceMetrics().At(0))
+	assert.Equal(t, 3, md.ResourceMetrics().Len())
+
+	assert.Equal(t, 0, MetricsFrom(md, 3).ResourceMetrics().Len())
+}
@@ -109,6 +109,22 @@ func (rec *Receiver) EndMetricsOp(
 	rec.endOp(receiverCtx, format, numReceivedPoints, err, config.MetricsDataType)
 }
 
+// RecordRefusedRequest records a request refused before its data was read, e.g. because
+// of the server limits, with the reason it was refused.
+func (rec *Receiver) RecordRefusedRequest(ctx context.Context, reason string) {
+	if obsreportconfig.Level == configtelemetry.LevelNone {
+		return
+	}
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{
+			tag.Upsert(obsmetrics.TagKeyReceiver, rec.receiverID.String(), tag.WithTTL(tag.TTLNoPropagation)),
+			tag.Upsert(obsmetrics.TagKeyTransport, rec.transport, tag.WithTTL(tag.TTLNoPropagation)),
+			tag.Upsert(obsmetrics.TagKeyReason, reason, tag.WithTTL(tag.TTLNoPropagation)),
+		},
+		obsmetrics.ReceiverRefusedRequests.M(1))
+}
+
 // ReceiverContext adds the keys used when recording observability metrics to
 // the given context returning the newly created context. This context should
 // be used in related calls to the obsreport functions so metrics are properly
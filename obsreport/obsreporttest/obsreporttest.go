@@ -40,6 +40,7 @@ var (
 	transportTag, _ = tag.NewKey("transport")
 	exporterTag, _  = tag.NewKey("exporter")
 	processorTag, _ = tag.NewKey("processor")
+	reasonTag, _    = tag.NewKey("reason")
 )
 
 // SetupRecordedMetricsTest does setup the testing environment to check the metrics recorded by receivers, producers or exporters.
@@ -132,6 +133,14 @@ func CheckReceiverMetrics(t *testing.T, receiver config.ComponentID, protocol st
 	checkValueForView(t, receiverTags, droppedMetricPoints, "receiver/refused_metric_points")
 }
 
+// CheckReceiverRefusedRequests checks that for the current exported values for the requests refused
+// by the receiver, for the given reason, match the expected value.
+// When this function is called it is required to also call SetupRecordedMetricsTest as first thing.
+func CheckReceiverRefusedRequests(t *testing.T, receiver config.ComponentID, protocol string, reason string, refusedRequests int64) {
+	receiverTags := append(tagsForReceiverView(receiver, protocol), tag.Tag{Key: reasonTag, Value: reason})
+	checkValueForView(t, receiverTags, refusedRequests, "receiver/refused_requests")
+}
+
 // CheckScraperMetrics checks that for the current exported values for metrics scraper metrics match given values.
 // When this function is called it is required to also call SetupRecordedMetricsTest as first thing.
 func CheckScraperMetrics(t *testing.T, receiver config.ComponentID, scraper config.ComponentID, scrapedMetricPoints, erroredMetricPoints int64) {
@@ -40,7 +40,7 @@ func newLogAttributesProcessor(attrProc *processorhelper.AttrProc, include, excl
 }
 
 // ProcessLogs implements the LogsProcessor
-func (a *logAttributesProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+func (a *logAttributesProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
 	rls := ld.ResourceLogs()
 	for i := 0; i < rls.Len(); i++ {
 		rs := rls.At(i)
@@ -56,7 +56,7 @@ func (a *logAttributesProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (
 					continue
 				}
 
-				a.attrProc.Process(lr.Attributes())
+				a.attrProc.Process(ctx, lr.Attributes())
 			}
 		}
 	}
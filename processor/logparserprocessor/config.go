@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparserprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+)
+
+const (
+	// FormatJSON parses the body as a JSON object.
+	FormatJSON = "json"
+	// FormatLogfmt parses the body as a sequence of key=value pairs.
+	FormatLogfmt = "logfmt"
+	// FormatRegex parses the body using a regular expression with named capture groups.
+	FormatRegex = "regex"
+)
+
+const (
+	// LayoutUnix interprets the timestamp field as seconds since the epoch.
+	LayoutUnix = "unix"
+	// LayoutUnixMs interprets the timestamp field as milliseconds since the epoch.
+	LayoutUnixMs = "unix_ms"
+	// LayoutUnixUs interprets the timestamp field as microseconds since the epoch.
+	LayoutUnixUs = "unix_us"
+	// LayoutUnixNs interprets the timestamp field as nanoseconds since the epoch.
+	LayoutUnixNs = "unix_ns"
+)
+
+// Config defines configuration for the log parser processor.
+// Prior to parsing, each log record is compared against the include properties
+// and then the exclude properties if they are specified. Log records whose body
+// is not a string, or cannot be parsed with the configured format, are passed
+// through unchanged.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	filterconfig.MatchConfig `mapstructure:",squash"`
+
+	// Format of the log body. Valid values are "json", "logfmt" and "regex".
+	// Default value is "json".
+	Format string `mapstructure:"format"`
+
+	// Regex is a regular expression with named capture groups, each group
+	// becoming an attribute. Required when Format is "regex".
+	Regex string `mapstructure:"regex"`
+
+	// AttributesPrefix is prepended to the key of every promoted attribute.
+	AttributesPrefix string `mapstructure:"attributes_prefix"`
+
+	// Overwrite controls whether parsed fields replace attributes already
+	// present on the log record. By default existing attributes are kept.
+	Overwrite bool `mapstructure:"overwrite"`
+
+	// Timestamp configures setting the log record timestamp from a parsed field.
+	Timestamp *TimestampConfig `mapstructure:"timestamp"`
+
+	// Severity configures setting the log record severity from a parsed field.
+	Severity *SeverityConfig `mapstructure:"severity"`
+
+	// TraceIDField is the name of the parsed field holding the hex encoded
+	// trace ID of the log record.
+	TraceIDField string `mapstructure:"trace_id_field"`
+
+	// SpanIDField is the name of the parsed field holding the hex encoded
+	// span ID of the log record.
+	SpanIDField string `mapstructure:"span_id_field"`
+}
+
+// TimestampConfig defines how the log record timestamp is read from a parsed field.
+type TimestampConfig struct {
+	// Field is the name of the parsed field holding the timestamp.
+	Field string `mapstructure:"field"`
+
+	// Layout is either a Go time layout (see https://golang.org/pkg/time/#Parse)
+	// or one of "unix", "unix_ms", "unix_us" and "unix_ns" for numeric epoch
+	// timestamps. Default value is RFC3339 with optional fractional seconds.
+	Layout string `mapstructure:"layout"`
+}
+
+// SeverityConfig defines how the log record severity is read from a parsed field.
+// The raw value is always stored as the SeverityText. The SeverityNumber is
+// derived from the value using Mapping first, then the well known level names
+// (e.g. "debug", "info", "warn", "error"), or the value itself if it is a
+// number between 1 and 24.
+type SeverityConfig struct {
+	// Field is the name of the parsed field holding the severity.
+	Field string `mapstructure:"field"`
+
+	// Mapping maps raw severity values to one of the OpenTelemetry severity
+	// names ("trace", "debug2", "info", "warn4", "error", "fatal", ...).
+	Mapping map[string]string `mapstructure:"mapping"`
+}
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	switch cfg.Format {
+	case FormatJSON, FormatLogfmt:
+		if cfg.Regex != "" {
+			return fmt.Errorf("\"regex\" can only be used with format %q", FormatRegex)
+		}
+	case FormatRegex:
+		if cfg.Regex == "" {
+			return fmt.Errorf("missing required field \"regex\" for format %q", FormatRegex)
+		}
+		re, err := regexp.Compile(cfg.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		if !hasNamedGroup(re) {
+			return errors.New("regex must contain at least one named capture group")
+		}
+	default:
+		return fmt.Errorf("unsupported format %q, must be one of %q, %q or %q", cfg.Format, FormatJSON, FormatLogfmt, FormatRegex)
+	}
+
+	if cfg.Timestamp != nil && cfg.Timestamp.Field == "" {
+		return errors.New("missing required field \"timestamp.field\"")
+	}
+
+	if cfg.Severity != nil {
+		if cfg.Severity.Field == "" {
+			return errors.New("missing required field \"severity.field\"")
+		}
+		for k, v := range cfg.Severity.Mapping {
+			if _, ok := severityNames[strings.ToLower(v)]; !ok {
+				return fmt.Errorf("invalid severity %q in mapping for %q", v, k)
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasNamedGroup(re *regexp.Regexp) bool {
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
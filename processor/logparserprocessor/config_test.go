@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparserprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factories.Processors[typeStr] = NewFactory()
+
+	cfg, err := configtest.LoadConfigAndValidate(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
+		Format:            FormatJSON,
+		Timestamp:         &TimestampConfig{Field: "time", Layout: LayoutUnixMs},
+		Severity:          &SeverityConfig{Field: "level"},
+		TraceIDField:      "trace_id",
+		SpanIDField:       "span_id",
+	}, cfg.Processors[config.NewID(typeStr)])
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewIDWithName(typeStr, "logfmt")),
+		Format:            FormatLogfmt,
+		AttributesPrefix:  "log.",
+		Overwrite:         true,
+		Severity: &SeverityConfig{
+			Field:   "lvl",
+			Mapping: map[string]string{"W": "warn", "E": "error"},
+		},
+	}, cfg.Processors[config.NewIDWithName(typeStr, "logfmt")])
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewIDWithName(typeStr, "regex")),
+		MatchConfig: filterconfig.MatchConfig{
+			Include: &filterconfig.MatchProperties{
+				Config:   *createConfig(filterset.Strict),
+				LogNames: []string{"access"},
+			},
+		},
+		Format:    FormatRegex,
+		Regex:     `^(?P<time>\S+) (?P<severity>\w+) (?P<message>.*)$`,
+		Timestamp: &TimestampConfig{Field: "time", Layout: "2006-01-02T15:04:05Z07:00"},
+		Severity:  &SeverityConfig{Field: "severity"},
+	}, cfg.Processors[config.NewIDWithName(typeStr, "regex")])
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr bool
+	}{
+		{
+			name: "json",
+			cfg:  &Config{Format: FormatJSON},
+		},
+		{
+			name:    "unknown format",
+			cfg:     &Config{Format: "xml"},
+			wantErr: true,
+		},
+		{
+			name:    "regex without format regex",
+			cfg:     &Config{Format: FormatLogfmt, Regex: "(?P<a>.*)"},
+			wantErr: true,
+		},
+		{
+			name:    "missing regex",
+			cfg:     &Config{Format: FormatRegex},
+			wantErr: true,
+		},
+		{
+			name:    "invalid regex",
+			cfg:     &Config{Format: FormatRegex, Regex: "(?P<a>"},
+			wantErr: true,
+		},
+		{
+			name:    "regex without named groups",
+			cfg:     &Config{Format: FormatRegex, Regex: "(.*)"},
+			wantErr: true,
+		},
+		{
+			name:    "missing timestamp field",
+			cfg:     &Config{Format: FormatJSON, Timestamp: &TimestampConfig{Layout: LayoutUnix}},
+			wantErr: true,
+		},
+		{
+			name:    "missing severity field",
+			cfg:     &Config{Format: FormatJSON, Severity: &SeverityConfig{}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid severity mapping",
+			cfg:     &Config{Format: FormatJSON, Severity: &SeverityConfig{Field: "level", Mapping: map[string]string{"W": "warning"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func createConfig(matchType filterset.MatchType) *filterset.Config {
+	return &filterset.Config{
+		MatchType: matchType,
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparserprocessor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/internal/processor/filterlog"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+type logParserProcessor struct {
+	logger  *zap.Logger
+	cfg     *Config
+	parser  bodyParser
+	include filterlog.Matcher
+	exclude filterlog.Matcher
+}
+
+// newLogParserProcessor returns a processor that parses the body of log
+// records. To construct the processor, the use of the factory methods are
+// required in order to validate the inputs.
+func newLogParserProcessor(logger *zap.Logger, cfg *Config, parser bodyParser, include, exclude filterlog.Matcher) *logParserProcessor {
+	return &logParserProcessor{
+		logger:  logger,
+		cfg:     cfg,
+		parser:  parser,
+		include: include,
+		exclude: exclude,
+	}
+}
+
+// ProcessLogs implements the LogsProcessor
+func (p *logParserProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rs := rls.At(i)
+		ilss := rs.InstrumentationLibraryLogs()
+		resource := rs.Resource()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			logs := ils.Logs()
+			library := ils.InstrumentationLibrary()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if p.skipLog(lr, resource, library) {
+					continue
+				}
+				if err := p.processLogRecord(lr); err != nil {
+					p.logger.Debug("Failed to parse log body", zap.Error(err))
+				}
+			}
+		}
+	}
+	return ld, nil
+}
+
+// processLogRecord parses the body of lr and updates lr from the parsed
+// fields. Log records with a non string body are left unchanged.
+func (p *logParserProcessor) processLogRecord(lr pdata.LogRecord) error {
+	body := lr.Body()
+	if body.Type() != pdata.AttributeValueTypeString {
+		return nil
+	}
+
+	fields := pdata.NewAttributeMap()
+	if err := p.parser.parse(body.StringVal(), fields); err != nil {
+		return err
+	}
+
+	attrs := lr.Attributes()
+	fields.Range(func(k string, v pdata.AttributeValue) bool {
+		key := p.cfg.AttributesPrefix + k
+		if p.cfg.Overwrite {
+			attrs.Upsert(key, v)
+		} else {
+			attrs.Insert(key, v)
+		}
+		return true
+	})
+
+	// Errors on the individual fields below are reported, but do not prevent
+	// the other fields from being applied.
+	var errs []string
+	if ts := p.cfg.Timestamp; ts != nil {
+		if v, ok := fields.Get(ts.Field); ok {
+			t, err := parseTimestamp(v, ts.Layout)
+			if err != nil {
+				errs = append(errs, err.Error())
+			} else {
+				lr.SetTimestamp(pdata.TimestampFromTime(t))
+			}
+		}
+	}
+	if sev := p.cfg.Severity; sev != nil {
+		if v, ok := fields.Get(sev.Field); ok {
+			if text, ok := attributeValueAsString(v); ok {
+				lr.SetSeverityText(text)
+				lr.SetSeverityNumber(severityNumber(text, sev.Mapping))
+			}
+		}
+	}
+	if p.cfg.TraceIDField != "" {
+		if v, ok := fields.Get(p.cfg.TraceIDField); ok && v.Type() == pdata.AttributeValueTypeString {
+			var id [16]byte
+			if err := decodeHexID(v.StringVal(), id[:]); err != nil {
+				errs = append(errs, err.Error())
+			} else {
+				lr.SetTraceID(pdata.NewTraceID(id))
+			}
+		}
+	}
+	if p.cfg.SpanIDField != "" {
+		if v, ok := fields.Get(p.cfg.SpanIDField); ok && v.Type() == pdata.AttributeValueTypeString {
+			var id [8]byte
+			if err := decodeHexID(v.StringVal(), id[:]); err != nil {
+				errs = append(errs, err.Error())
+			} else {
+				lr.SetSpanID(pdata.NewSpanID(id))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to apply parsed fields: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// skipLog determines if a log should be processed.
+// True is returned when a log should be skipped.
+// False is returned when a log should not be skipped.
+// The logic determining if a log should be processed is set
+// in the configuration with the include and exclude settings.
+// Include properties are checked before exclude settings are checked.
+func (p *logParserProcessor) skipLog(lr pdata.LogRecord, resource pdata.Resource, library pdata.InstrumentationLibrary) bool {
+	if p.include != nil {
+		// A false returned in this case means the log should not be processed.
+		if include := p.include.MatchLogRecord(lr, resource, library); !include {
+			return true
+		}
+	}
+
+	if p.exclude != nil {
+		// A true returned in this case means the log should not be processed.
+		if exclude := p.exclude.MatchLogRecord(lr, resource, library); exclude {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTimestamp converts a parsed field into a time using the given layout.
+func parseTimestamp(v pdata.AttributeValue, layout string) (time.Time, error) {
+	var unit time.Duration
+	switch layout {
+	case LayoutUnix:
+		unit = time.Second
+	case LayoutUnixMs:
+		unit = time.Millisecond
+	case LayoutUnixUs:
+		unit = time.Microsecond
+	case LayoutUnixNs:
+		unit = time.Nanosecond
+	}
+
+	if unit == 0 {
+		if v.Type() != pdata.AttributeValueTypeString {
+			return time.Time{}, fmt.Errorf("timestamp field must be a string, got %v", v.Type())
+		}
+		if layout == "" {
+			layout = time.RFC3339Nano
+		}
+		return time.Parse(layout, v.StringVal())
+	}
+
+	var epoch float64
+	switch v.Type() {
+	case pdata.AttributeValueTypeInt:
+		return time.Unix(0, v.IntVal()*int64(unit)).UTC(), nil
+	case pdata.AttributeValueTypeDouble:
+		epoch = v.DoubleVal()
+	case pdata.AttributeValueTypeString:
+		if i, err := strconv.ParseInt(v.StringVal(), 10, 64); err == nil {
+			return time.Unix(0, i*int64(unit)).UTC(), nil
+		}
+		f, err := strconv.ParseFloat(v.StringVal(), 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid epoch timestamp %q", v.StringVal())
+		}
+		epoch = f
+	default:
+		return time.Time{}, fmt.Errorf("timestamp field must be a number, got %v", v.Type())
+	}
+	sec, frac := math.Modf(epoch * unit.Seconds())
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+}
+
+// severityNumber returns the severity number for a raw severity value.
+func severityNumber(text string, mapping map[string]string) pdata.SeverityNumber {
+	if name, ok := mapping[text]; ok {
+		return severityNames[strings.ToLower(name)]
+	}
+	lower := strings.ToLower(text)
+	if sn, ok := severityNames[lower]; ok {
+		return sn
+	}
+	if sn, ok := severityAliases[lower]; ok {
+		return sn
+	}
+	if n, err := strconv.Atoi(text); err == nil && n >= int(pdata.SeverityNumberTRACE) && n <= int(pdata.SeverityNumberFATAL4) {
+		return pdata.SeverityNumber(n)
+	}
+	return pdata.SeverityNumberUNDEFINED
+}
@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparserprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func generateLogData(name string, body pdata.AttributeValue, attrs map[string]pdata.AttributeValue) pdata.Logs {
+	ld := pdata.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.SetName(name)
+	body.CopyTo(lr.Body())
+	lr.Attributes().InitFromMap(attrs)
+	return ld
+}
+
+func processLog(t *testing.T, cfg *Config, ld pdata.Logs) pdata.LogRecord {
+	require.NoError(t, cfg.Validate())
+	sink := new(consumertest.LogsSink)
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	require.Len(t, sink.AllLogs(), 1)
+	lr := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	lr.Attributes().Sort()
+	return lr
+}
+
+func TestLogParser_NilEmptyData(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  pdata.Logs
+		output pdata.Logs
+	}{
+		{
+			name:   "empty",
+			input:  pdata.NewLogs(),
+			output: pdata.NewLogs(),
+		},
+		{
+			name:   "one-empty-resource-logs",
+			input:  testdata.GenerateLogsOneEmptyResourceLogs(),
+			output: testdata.GenerateLogsOneEmptyResourceLogs(),
+		},
+		{
+			name:   "one-empty-log-record",
+			input:  testdata.GenerateLogsOneEmptyLogRecord(),
+			output: testdata.GenerateLogsOneEmptyLogRecord(),
+		},
+	}
+	factory := NewFactory()
+	lp, err := factory.CreateLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), factory.CreateDefaultConfig(), consumertest.NewNop())
+	require.NoError(t, err)
+	for i := range testCases {
+		tt := testCases[i]
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, lp.ConsumeLogs(context.Background(), tt.input))
+			assert.EqualValues(t, tt.output, tt.input)
+		})
+	}
+}
+
+func TestLogParser_JSON(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Timestamp = &TimestampConfig{Field: "ts", Layout: LayoutUnixMs}
+	cfg.Severity = &SeverityConfig{Field: "level"}
+	cfg.TraceIDField = "trace_id"
+	cfg.SpanIDField = "span_id"
+
+	body := `{"ts":1625047200123,"level":"WARNING","msg":"disk almost full","free":0.05,"retries":3,` +
+		`"ok":false,"ctx":{"disk":"sda"},"tags":["a",1],"none":null,` +
+		`"trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708"}`
+	lr := processLog(t, cfg, generateLogData("test", pdata.NewAttributeValueString(body), map[string]pdata.AttributeValue{
+		"msg": pdata.NewAttributeValueString("existing"),
+	}))
+
+	ctx := pdata.NewAttributeValueMap()
+	ctx.MapVal().InsertString("disk", "sda")
+	tags := pdata.NewAttributeValueArray()
+	tags.ArrayVal().AppendEmpty().SetStringVal("a")
+	tags.ArrayVal().AppendEmpty().SetIntVal(1)
+	expected := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"ts":       pdata.NewAttributeValueInt(1625047200123),
+		"level":    pdata.NewAttributeValueString("WARNING"),
+		"msg":      pdata.NewAttributeValueString("existing"),
+		"free":     pdata.NewAttributeValueDouble(0.05),
+		"retries":  pdata.NewAttributeValueInt(3),
+		"ok":       pdata.NewAttributeValueBool(false),
+		"ctx":      ctx,
+		"tags":     tags,
+		"none":     pdata.NewAttributeValueNull(),
+		"trace_id": pdata.NewAttributeValueString("0102030405060708090a0b0c0d0e0f10"),
+		"span_id":  pdata.NewAttributeValueString("0102030405060708"),
+	}).Sort()
+	assert.Equal(t, expected, lr.Attributes())
+	assert.Equal(t, body, lr.Body().StringVal())
+	assert.Equal(t, time.Date(2021, 6, 30, 10, 0, 0, 123000000, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, "WARNING", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}), lr.TraceID())
+	assert.Equal(t, pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}), lr.SpanID())
+}
+
+func TestLogParser_Logfmt(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Format = FormatLogfmt
+	cfg.AttributesPrefix = "log."
+	cfg.Overwrite = true
+	cfg.Severity = &SeverityConfig{Field: "lvl", Mapping: map[string]string{"E": "error3"}}
+	cfg.Timestamp = &TimestampConfig{Field: "time"}
+
+	body := `time=2021-06-30T10:00:00.5Z lvl=E msg="request \"failed\"" path=/api  empty= flag`
+	lr := processLog(t, cfg, generateLogData("test", pdata.NewAttributeValueString(body), map[string]pdata.AttributeValue{
+		"log.msg": pdata.NewAttributeValueString("existing"),
+	}))
+
+	expected := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"log.time":  pdata.NewAttributeValueString("2021-06-30T10:00:00.5Z"),
+		"log.lvl":   pdata.NewAttributeValueString("E"),
+		"log.msg":   pdata.NewAttributeValueString(`request "failed"`),
+		"log.path":  pdata.NewAttributeValueString("/api"),
+		"log.empty": pdata.NewAttributeValueString(""),
+		"log.flag":  pdata.NewAttributeValueString(""),
+	}).Sort()
+	assert.Equal(t, expected, lr.Attributes())
+	assert.Equal(t, time.Date(2021, 6, 30, 10, 0, 0, 500000000, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, "E", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberERROR3, lr.SeverityNumber())
+}
+
+func TestLogParser_Regex(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Format = FormatRegex
+	cfg.Regex = `^(?P<time>\S+) (?P<severity>\w+)(?: \[(?P<thread>\w+)\])? (?P<message>.*)$`
+	cfg.Timestamp = &TimestampConfig{Field: "time", Layout: LayoutUnix}
+	cfg.Severity = &SeverityConfig{Field: "severity"}
+
+	lr := processLog(t, cfg, generateLogData("test", pdata.NewAttributeValueString("1625047200.25 17 something failed"), nil))
+
+	expected := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"time":     pdata.NewAttributeValueString("1625047200.25"),
+		"severity": pdata.NewAttributeValueString("17"),
+		"message":  pdata.NewAttributeValueString("something failed"),
+	}).Sort()
+	assert.Equal(t, expected, lr.Attributes())
+	assert.Equal(t, time.Date(2021, 6, 30, 10, 0, 0, 250000000, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+}
+
+func TestLogParser_Unparsable(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		body pdata.AttributeValue
+	}{
+		{
+			name: "non string body",
+			cfg:  &Config{Format: FormatJSON},
+			body: pdata.NewAttributeValueInt(5),
+		},
+		{
+			name: "invalid json",
+			cfg:  &Config{Format: FormatJSON},
+			body: pdata.NewAttributeValueString(`{"a":`),
+		},
+		{
+			name: "json array",
+			cfg:  &Config{Format: FormatJSON},
+			body: pdata.NewAttributeValueString(`[1, 2]`),
+		},
+		{
+			name: "unterminated logfmt quote",
+			cfg:  &Config{Format: FormatLogfmt},
+			body: pdata.NewAttributeValueString(`a=1 b="c`),
+		},
+		{
+			name: "regex no match",
+			cfg:  &Config{Format: FormatRegex, Regex: `^(?P<a>\d+)$`},
+			body: pdata.NewAttributeValueString(`abc`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lr := processLog(t, tt.cfg, generateLogData("test", tt.body, nil))
+			assert.Equal(t, 0, lr.Attributes().Len())
+			assert.True(t, tt.body.Equal(lr.Body()))
+		})
+	}
+}
+
+func TestLogParser_InvalidFields(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Timestamp = &TimestampConfig{Field: "ts"}
+	cfg.Severity = &SeverityConfig{Field: "level"}
+	cfg.TraceIDField = "trace_id"
+	cfg.SpanIDField = "span_id"
+
+	body := `{"ts":"yesterday","level":"loud","trace_id":"xyz","span_id":"0102","other":1}`
+	lr := processLog(t, cfg, generateLogData("test", pdata.NewAttributeValueString(body), nil))
+
+	assert.Equal(t, 5, lr.Attributes().Len())
+	assert.Equal(t, pdata.Timestamp(0), lr.Timestamp())
+	assert.Equal(t, "loud", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, lr.SeverityNumber())
+	assert.True(t, lr.TraceID().IsEmpty())
+	assert.True(t, lr.SpanID().IsEmpty())
+}
+
+func TestLogParser_IncludeExclude(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Include = &filterconfig.MatchProperties{
+		Config:   filterset.Config{MatchType: filterset.Strict},
+		LogNames: []string{"parsed"},
+	}
+
+	body := pdata.NewAttributeValueString(`{"a":"b"}`)
+	lr := processLog(t, cfg, generateLogData("parsed", body, nil))
+	assert.Equal(t, 1, lr.Attributes().Len())
+
+	lr = processLog(t, cfg, generateLogData("skipped", body, nil))
+	assert.Equal(t, 0, lr.Attributes().Len())
+}
+
+func TestParseTimestamp(t *testing.T) {
+	expected := time.Date(2021, 6, 30, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  pdata.AttributeValue
+		layout string
+	}{
+		{name: "rfc3339", value: pdata.NewAttributeValueString("2021-06-30T10:00:00Z"), layout: ""},
+		{name: "custom layout", value: pdata.NewAttributeValueString("30/06/2021 10:00"), layout: "02/01/2006 15:04"},
+		{name: "unix int", value: pdata.NewAttributeValueInt(1625047200), layout: LayoutUnix},
+		{name: "unix string", value: pdata.NewAttributeValueString("1625047200"), layout: LayoutUnix},
+		{name: "unix ms double", value: pdata.NewAttributeValueDouble(1625047200000), layout: LayoutUnixMs},
+		{name: "unix us", value: pdata.NewAttributeValueInt(1625047200000000), layout: LayoutUnixUs},
+		{name: "unix ns", value: pdata.NewAttributeValueString("1625047200000000000"), layout: LayoutUnixNs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := parseTimestamp(tt.value, tt.layout)
+			require.NoError(t, err)
+			assert.Equal(t, expected, ts.UTC())
+		})
+	}
+
+	_, err := parseTimestamp(pdata.NewAttributeValueInt(1), "")
+	assert.Error(t, err)
+	_, err = parseTimestamp(pdata.NewAttributeValueBool(true), LayoutUnix)
+	assert.Error(t, err)
+	_, err = parseTimestamp(pdata.NewAttributeValueString("abc"), LayoutUnix)
+	assert.Error(t, err)
+}
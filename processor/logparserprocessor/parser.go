@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparserprocessor
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// bodyParser parses a log body into a set of attributes.
+type bodyParser interface {
+	// parse parses body and stores the resulting fields into dest.
+	parse(body string, dest pdata.AttributeMap) error
+}
+
+func newBodyParser(cfg *Config) (bodyParser, error) {
+	switch cfg.Format {
+	case FormatJSON:
+		return jsonParser{}, nil
+	case FormatLogfmt:
+		return logfmtParser{}, nil
+	case FormatRegex:
+		re, err := regexp.Compile(cfg.Regex)
+		if err != nil {
+			return nil, err
+		}
+		return &regexParser{re: re}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", cfg.Format)
+}
+
+// jsonParser parses a body containing a JSON object. Nested objects and arrays
+// are kept as map and array attribute values.
+type jsonParser struct{}
+
+func (jsonParser) parse(body string, dest pdata.AttributeMap) error {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return err
+	}
+	if fields == nil {
+		return errors.New("body is not a JSON object")
+	}
+	for k, v := range fields {
+		dest.Upsert(k, jsonToAttributeValue(v))
+	}
+	return nil
+}
+
+func jsonToAttributeValue(v interface{}) pdata.AttributeValue {
+	switch val := v.(type) {
+	case string:
+		return pdata.NewAttributeValueString(val)
+	case bool:
+		return pdata.NewAttributeValueBool(val)
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return pdata.NewAttributeValueInt(i)
+		}
+		f, _ := val.Float64()
+		return pdata.NewAttributeValueDouble(f)
+	case map[string]interface{}:
+		av := pdata.NewAttributeValueMap()
+		m := av.MapVal()
+		for k, e := range val {
+			m.Upsert(k, jsonToAttributeValue(e))
+		}
+		return av
+	case []interface{}:
+		av := pdata.NewAttributeValueArray()
+		arr := av.ArrayVal()
+		for _, e := range val {
+			jsonToAttributeValue(e).CopyTo(arr.AppendEmpty())
+		}
+		return av
+	}
+	return pdata.NewAttributeValueNull()
+}
+
+// logfmtParser parses a body made of space separated key=value pairs, where
+// values may be double quoted. A key without a value is stored with an empty
+// string value.
+type logfmtParser struct{}
+
+func (logfmtParser) parse(body string, dest pdata.AttributeMap) error {
+	i := 0
+	for {
+		for i < len(body) && isLogfmtSpace(body[i]) {
+			i++
+		}
+		if i >= len(body) {
+			return nil
+		}
+
+		start := i
+		for i < len(body) && body[i] != '=' && body[i] != '"' && !isLogfmtSpace(body[i]) {
+			i++
+		}
+		key := body[start:i]
+		if key == "" {
+			return fmt.Errorf("unexpected character %q at offset %d", body[i], i)
+		}
+		if i >= len(body) || body[i] != '=' {
+			dest.UpsertString(key, "")
+			continue
+		}
+		i++
+
+		if i < len(body) && body[i] == '"' {
+			end := i + 1
+			for end < len(body) && body[end] != '"' {
+				if body[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(body) {
+				return fmt.Errorf("unterminated quoted value for key %q", key)
+			}
+			val, err := strconv.Unquote(body[i : end+1])
+			if err != nil {
+				return fmt.Errorf("invalid quoted value for key %q: %w", key, err)
+			}
+			dest.UpsertString(key, val)
+			i = end + 1
+			continue
+		}
+
+		start = i
+		for i < len(body) && !isLogfmtSpace(body[i]) {
+			i++
+		}
+		dest.UpsertString(key, body[start:i])
+	}
+}
+
+func isLogfmtSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// regexParser parses a body using a regular expression, storing every named
+// capture group that participated in the match.
+type regexParser struct {
+	re *regexp.Regexp
+}
+
+func (p *regexParser) parse(body string, dest pdata.AttributeMap) error {
+	match := p.re.FindStringSubmatchIndex(body)
+	if match == nil {
+		return errors.New("body does not match regex")
+	}
+	for i, name := range p.re.SubexpNames() {
+		if name == "" || match[2*i] < 0 {
+			continue
+		}
+		dest.UpsertString(name, body[match[2*i]:match[2*i+1]])
+	}
+	return nil
+}
+
+// severityNames maps lower case OpenTelemetry severity names to their number.
+var severityNames = map[string]pdata.SeverityNumber{
+	"trace":  pdata.SeverityNumberTRACE,
+	"trace2": pdata.SeverityNumberTRACE2,
+	"trace3": pdata.SeverityNumberTRACE3,
+	"trace4": pdata.SeverityNumberTRACE4,
+	"debug":  pdata.SeverityNumberDEBUG,
+	"debug2": pdata.SeverityNumberDEBUG2,
+	"debug3": pdata.SeverityNumberDEBUG3,
+	"debug4": pdata.SeverityNumberDEBUG4,
+	"info":   pdata.SeverityNumberINFO,
+	"info2":  pdata.SeverityNumberINFO2,
+	"info3":  pdata.SeverityNumberINFO3,
+	"info4":  pdata.SeverityNumberINFO4,
+	"warn":   pdata.SeverityNumberWARN,
+	"warn2":  pdata.SeverityNumberWARN2,
+	"warn3":  pdata.SeverityNumberWARN3,
+	"warn4":  pdata.SeverityNumberWARN4,
+	"error":  pdata.SeverityNumberERROR,
+	"error2": pdata.SeverityNumberERROR2,
+	"error3": pdata.SeverityNumberERROR3,
+	"error4": pdata.SeverityNumberERROR4,
+	"fatal":  pdata.SeverityNumberFATAL,
+	"fatal2": pdata.SeverityNumberFATAL2,
+	"fatal3": pdata.SeverityNumberFATAL3,
+	"fatal4": pdata.SeverityNumberFATAL4,
+}
+
+// severityAliases maps common level names used by logging libraries that are
+// not OpenTelemetry severity names.
+var severityAliases = map[string]pdata.SeverityNumber{
+	"dbug":        pdata.SeverityNumberDEBUG,
+	"information": pdata.SeverityNumberINFO,
+	"notice":      pdata.SeverityNumberINFO2,
+	"warning":     pdata.SeverityNumberWARN,
+	"err":         pdata.SeverityNumberERROR,
+	"crit":        pdata.SeverityNumberFATAL,
+	"critical":    pdata.SeverityNumberFATAL,
+	"alert":       pdata.SeverityNumberFATAL2,
+	"emerg":       pdata.SeverityNumberFATAL3,
+	"emergency":   pdata.SeverityNumberFATAL3,
+	"panic":       pdata.SeverityNumberFATAL4,
+}
+
+// attributeValueAsString returns the string representation of scalar values.
+func attributeValueAsString(av pdata.AttributeValue) (string, bool) {
+	switch av.Type() {
+	case pdata.AttributeValueTypeString:
+		return av.StringVal(), true
+	case pdata.AttributeValueTypeInt:
+		return strconv.FormatInt(av.IntVal(), 10), true
+	case pdata.AttributeValueTypeDouble:
+		return strconv.FormatFloat(av.DoubleVal(), 'f', -1, 64), true
+	case pdata.AttributeValueTypeBool:
+		return strconv.FormatBool(av.BoolVal()), true
+	}
+	return "", false
+}
+
+// decodeHexID decodes a hex encoded ID into dest, which must have the exact
+// decoded length of the ID.
+func decodeHexID(s string, dest []byte) error {
+	b, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("invalid hex ID %q: %w", s, err)
+	}
+	if len(b) != len(dest) {
+		return fmt.Errorf("invalid ID length %d, expected %d bytes", len(b), len(dest))
+	}
+	copy(dest, b)
+	return nil
+}
@@ -15,10 +15,12 @@
 package processorhelper
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
 
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/internal/processor/filterhelper"
 	"go.opentelemetry.io/collector/model/pdata"
 )
@@ -55,20 +57,26 @@ type ActionKeyValue struct {
 	// the value. If the attribute doesn't exist, no action is performed.
 	FromAttribute string `mapstructure:"from_attribute"`
 
+	// FromContext specifies the value from the request context to use to
+	// populate the value. The set of values are {auth.subject, auth.groups},
+	// taken from the identity of the client as established by the receiver's
+	// authenticator. If the value doesn't exist, no action is performed.
+	FromContext string `mapstructure:"from_context"`
+
 	// Action specifies the type of action to perform.
 	// The set of values are {INSERT, UPDATE, UPSERT, DELETE, HASH}.
 	// Both lower case and upper case are supported.
 	// INSERT -  Inserts the key/value to attributes when the key does not exist.
 	//           No action is applied to attributes where the key already exists.
-	//           Either Value or FromAttribute must be set.
+	//           One of Value, FromAttribute or FromContext must be set.
 	// UPDATE -  Updates an existing key with a value. No action is applied
 	//           to attributes where the key does not exist.
-	//           Either Value or FromAttribute must be set.
+	//           One of Value, FromAttribute or FromContext must be set.
 	// UPSERT -  Performs insert or update action depending on the attributes
 	//           containing the key. The key/value is insert to attributes
 	//           that did not originally have the key. The key/value is updated
 	//           for attributes where the key already existed.
-	//           Either Value or FromAttribute must be set.
+	//           One of Value, FromAttribute or FromContext must be set.
 	// DELETE  - Deletes the attribute. If the key doesn't exist,
 	//           no action is performed.
 	// HASH    - Calculates the SHA-1 hash of an existing value and overwrites the
@@ -111,9 +119,18 @@ const (
 	EXTRACT Action = "extract"
 )
 
+const (
+	// ContextAuthSubject is the FromContext value for the subject of the authenticated client.
+	ContextAuthSubject = "auth.subject"
+
+	// ContextAuthGroups is the FromContext value for the groups of the authenticated client.
+	ContextAuthGroups = "auth.groups"
+)
+
 type attributeAction struct {
 	Key           string
 	FromAttribute string
+	FromContext   string
 	// Compiled regex if provided
 	Regex *regexp.Regexp
 	// Attribute names extracted from the regexp's subexpressions.
@@ -153,13 +170,19 @@ func NewAttrProc(settings *Settings) (*AttrProc, error) {
 
 		switch a.Action {
 		case INSERT, UPDATE, UPSERT:
-			if a.Value == nil && a.FromAttribute == "" {
-				return nil, fmt.Errorf("error creating AttrProc. Either field \"value\" or \"from_attribute\" setting must be specified for %d-th action", i)
+			if a.Value == nil && a.FromAttribute == "" && a.FromContext == "" {
+				return nil, fmt.Errorf("error creating AttrProc. Either field \"value\", \"from_attribute\" or \"from_context\" setting must be specified for %d-th action", i)
 			}
 
 			if a.Value != nil && a.FromAttribute != "" {
 				return nil, fmt.Errorf("error creating AttrProc due to both fields \"value\" and \"from_attribute\" being set at the %d-th actions", i)
 			}
+			if a.FromContext != "" && (a.Value != nil || a.FromAttribute != "") {
+				return nil, fmt.Errorf("error creating AttrProc due to field \"from_context\" being set along with \"value\" or \"from_attribute\" at the %d-th actions", i)
+			}
+			if a.FromContext != "" && a.FromContext != ContextAuthSubject && a.FromContext != ContextAuthGroups {
+				return nil, fmt.Errorf("error creating AttrProc due to unsupported \"from_context\" value %q at the %d-th actions", a.FromContext, i)
+			}
 			if a.RegexPattern != "" {
 				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use the \"pattern\" field. This must not be specified for %d-th action", a.Action, i)
 
@@ -173,13 +196,14 @@ func NewAttrProc(settings *Settings) (*AttrProc, error) {
 				action.AttributeValue = &val
 			} else {
 				action.FromAttribute = a.FromAttribute
+				action.FromContext = a.FromContext
 			}
 		case HASH, DELETE:
-			if a.Value != nil || a.FromAttribute != "" || a.RegexPattern != "" {
+			if a.Value != nil || a.FromAttribute != "" || a.FromContext != "" || a.RegexPattern != "" {
 				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for %d-th action", a.Action, i)
 			}
 		case EXTRACT:
-			if a.Value != nil || a.FromAttribute != "" {
+			if a.Value != nil || a.FromAttribute != "" || a.FromContext != "" {
 				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\" or \"from_attribute\" field. These must not be specified for %d-th action", a.Action, i)
 			}
 			if a.RegexPattern == "" {
@@ -211,8 +235,9 @@ func NewAttrProc(settings *Settings) (*AttrProc, error) {
 	return &AttrProc{actions: attributeActions}, nil
 }
 
-// Process applies the AttrProc to an attribute map.
-func (ap *AttrProc) Process(attrs pdata.AttributeMap) {
+// Process applies the AttrProc to an attribute map. The context is the one
+// the data was received with, used by actions with FromContext.
+func (ap *AttrProc) Process(ctx context.Context, attrs pdata.AttributeMap) {
 	for _, action := range ap.actions {
 		// TODO https://go.opentelemetry.io/collector/issues/296
 		// Do benchmark testing between having action be of type string vs integer.
@@ -222,19 +247,19 @@ func (ap *AttrProc) Process(attrs pdata.AttributeMap) {
 		case DELETE:
 			attrs.Delete(action.Key)
 		case INSERT:
-			av, found := getSourceAttributeValue(action, attrs)
+			av, found := getSourceAttributeValue(ctx, action, attrs)
 			if !found {
 				continue
 			}
 			attrs.Insert(action.Key, av)
 		case UPDATE:
-			av, found := getSourceAttributeValue(action, attrs)
+			av, found := getSourceAttributeValue(ctx, action, attrs)
 			if !found {
 				continue
 			}
 			attrs.Update(action.Key, av)
 		case UPSERT:
-			av, found := getSourceAttributeValue(action, attrs)
+			av, found := getSourceAttributeValue(ctx, action, attrs)
 			if !found {
 				continue
 			}
@@ -247,15 +272,44 @@ func (ap *AttrProc) Process(attrs pdata.AttributeMap) {
 	}
 }
 
-func getSourceAttributeValue(action attributeAction, attrs pdata.AttributeMap) (pdata.AttributeValue, bool) {
+func getSourceAttributeValue(ctx context.Context, action attributeAction, attrs pdata.AttributeMap) (pdata.AttributeValue, bool) {
 	// Set the key with a value from the configuration.
 	if action.AttributeValue != nil {
 		return *action.AttributeValue, true
 	}
 
+	if action.FromContext != "" {
+		return getContextAttributeValue(ctx, action.FromContext)
+	}
+
 	return attrs.Get(action.FromAttribute)
 }
 
+func getContextAttributeValue(ctx context.Context, from string) (pdata.AttributeValue, bool) {
+	ad, ok := configauth.AuthDataFromContext(ctx)
+	if !ok {
+		return pdata.AttributeValue{}, false
+	}
+
+	switch from {
+	case ContextAuthSubject:
+		if ad.Subject == "" {
+			return pdata.AttributeValue{}, false
+		}
+		return pdata.NewAttributeValueString(ad.Subject), true
+	case ContextAuthGroups:
+		if len(ad.Groups) == 0 {
+			return pdata.AttributeValue{}, false
+		}
+		av := pdata.NewAttributeValueArray()
+		for _, g := range ad.Groups {
+			av.ArrayVal().AppendEmpty().SetStringVal(g)
+		}
+		return av, true
+	}
+	return pdata.AttributeValue{}, false
+}
+
 func hashAttribute(action attributeAction, attrs pdata.AttributeMap) {
 	if value, exists := attrs.Get(action.Key); exists {
 		sha1Hasher(value)
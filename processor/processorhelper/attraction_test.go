@@ -15,6 +15,7 @@
 package processorhelper
 
 import (
+	"context"
 	"crypto/sha1" // #nosec
 	"encoding/binary"
 	"errors"
@@ -26,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
@@ -40,7 +42,7 @@ type testCase struct {
 func runIndividualTestCase(t *testing.T, tt testCase, ap *AttrProc) {
 	t.Run(tt.name, func(t *testing.T) {
 		attrMap := pdata.NewAttributeMap().InitFromMap(tt.inputAttributes)
-		ap.Process(attrMap)
+		ap.Process(context.Background(), attrMap)
 		attrMap.Sort()
 		require.Equal(t, pdata.NewAttributeMap().InitFromMap(tt.expectedAttributes).Sort(), attrMap)
 	})
@@ -489,6 +491,67 @@ func TestAttributes_UpsertFromAttribute(t *testing.T) {
 	}
 }
 
+func TestAttributes_FromContext(t *testing.T) {
+	cfg := &Settings{
+		Actions: []ActionKeyValue{
+			{Key: "tenant.id", Action: UPSERT, FromContext: ContextAuthSubject},
+			{Key: "tenant.groups", Action: INSERT, FromContext: ContextAuthGroups},
+		},
+	}
+
+	ap, err := NewAttrProc(cfg)
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	groups := pdata.NewAttributeValueArray()
+	groups.ArrayVal().AppendEmpty().SetStringVal("team-a")
+	testCases := []struct {
+		name               string
+		ctx                context.Context
+		inputAttributes    map[string]pdata.AttributeValue
+		expectedAttributes map[string]pdata.AttributeValue
+	}{
+		{
+			name: "NotAuthenticated",
+			ctx:  context.Background(),
+			inputAttributes: map[string]pdata.AttributeValue{
+				"tenant.id": pdata.NewAttributeValueString("client-supplied"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"tenant.id": pdata.NewAttributeValueString("client-supplied"),
+			},
+		},
+		{
+			name: "SubjectOnly",
+			ctx:  configauth.NewContextWithAuthData(context.Background(), &configauth.AuthData{Subject: "tenant-1"}),
+			inputAttributes: map[string]pdata.AttributeValue{
+				"tenant.id": pdata.NewAttributeValueString("client-supplied"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"tenant.id": pdata.NewAttributeValueString("tenant-1"),
+			},
+		},
+		{
+			name:            "SubjectAndGroups",
+			ctx:             configauth.NewContextWithAuthData(context.Background(), &configauth.AuthData{Subject: "tenant-1", Groups: []string{"team-a"}}),
+			inputAttributes: map[string]pdata.AttributeValue{},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"tenant.id":     pdata.NewAttributeValueString("tenant-1"),
+				"tenant.groups": groups,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			attrMap := pdata.NewAttributeMap().InitFromMap(tt.inputAttributes)
+			ap.Process(tt.ctx, attrMap)
+			attrMap.Sort()
+			require.Equal(t, pdata.NewAttributeMap().InitFromMap(tt.expectedAttributes).Sort(), attrMap)
+		})
+	}
+}
+
 func TestAttributes_Delete(t *testing.T) {
 	testCases := []testCase{
 		// Ensure the span contains no changes.
@@ -771,7 +834,21 @@ func TestInvalidConfig(t *testing.T) {
 			actionLists: []ActionKeyValue{
 				{Key: "MissingValueFromAttributes", Action: INSERT},
 			},
-			errorString: "error creating AttrProc. Either field \"value\" or \"from_attribute\" setting must be specified for 0-th action",
+			errorString: "error creating AttrProc. Either field \"value\", \"from_attribute\" or \"from_context\" setting must be specified for 0-th action",
+		},
+		{
+			name: "both set value and from context",
+			actionLists: []ActionKeyValue{
+				{Key: "BothSet", Value: 123, FromContext: ContextAuthSubject, Action: UPSERT},
+			},
+			errorString: "error creating AttrProc due to field \"from_context\" being set along with \"value\" or \"from_attribute\" at the 0-th actions",
+		},
+		{
+			name: "unsupported from context",
+			actionLists: []ActionKeyValue{
+				{Key: "key", FromContext: "auth.password", Action: UPSERT},
+			},
+			errorString: "error creating AttrProc due to unsupported \"from_context\" value \"auth.password\" at the 0-th actions",
 		},
 		{
 			name: "both set value and from attribute",
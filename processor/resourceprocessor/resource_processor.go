@@ -26,28 +26,28 @@ type resourceProcessor struct {
 }
 
 // ProcessTraces implements the TProcessor interface
-func (rp *resourceProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+func (rp *resourceProcessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
-		rp.attrProc.Process(rss.At(i).Resource().Attributes())
+		rp.attrProc.Process(ctx, rss.At(i).Resource().Attributes())
 	}
 	return td, nil
 }
 
 // ProcessMetrics implements the MProcessor interface
-func (rp *resourceProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+func (rp *resourceProcessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
 	rms := md.ResourceMetrics()
 	for i := 0; i < rms.Len(); i++ {
-		rp.attrProc.Process(rms.At(i).Resource().Attributes())
+		rp.attrProc.Process(ctx, rms.At(i).Resource().Attributes())
 	}
 	return md, nil
 }
 
 // ProcessLogs implements the LProcessor interface
-func (rp *resourceProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+func (rp *resourceProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
 	rls := ld.ResourceLogs()
 	for i := 0; i < rls.Len(); i++ {
-		rp.attrProc.Process(rls.At(i).Resource().Attributes())
+		rp.attrProc.Process(ctx, rls.At(i).Resource().Attributes())
 	}
 	return ld, nil
 }
@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerreceiver
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	// maxProbabilityIncrease is the maximum factor the probability of an operation is increased by at each
+	// calculation, so that a burst of traffic following a quiet interval isn't fully sampled.
+	maxProbabilityIncrease = 2
+	// maxIdleIntervals is the number of calculation intervals an operation is forgotten after, if no span of it is
+	// observed.
+	maxIdleIntervals = 10
+)
+
+// adaptiveSampler calculates per-operation sampling probabilities from the observed throughput of the services, so
+// that about TargetSpansPerSecond traces of each operation are sampled. Only the root spans are counted, as the
+// sampling decision is made when the root span of a trace starts.
+type adaptiveSampler struct {
+	config AdaptiveSamplingConfig
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu sync.Mutex
+	// counts are the numbers of sampled root spans observed during the current interval, by service and operation.
+	counts map[string]map[string]int64
+	// services are the operation probabilities, by service.
+	services map[string]map[string]*operationProbability
+}
+
+// operationProbability is the sampling probability of an operation.
+type operationProbability struct {
+	probability float64
+	// idleIntervals is the number of intervals without any span of the operation.
+	idleIntervals int
+}
+
+func newAdaptiveSampler(config AdaptiveSamplingConfig) *adaptiveSampler {
+	return &adaptiveSampler{
+		config:   config,
+		done:     make(chan struct{}),
+		counts:   map[string]map[string]int64{},
+		services: map[string]map[string]*operationProbability{},
+	}
+}
+
+// start starts recalculating the probabilities at each calculation interval.
+func (a *adaptiveSampler) start() {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.config.CalculationInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.calculate(a.config.CalculationInterval)
+			case <-a.done:
+				return
+			}
+		}
+	}()
+}
+
+// shutdown stops recalculating the probabilities.
+func (a *adaptiveSampler) shutdown() {
+	select {
+	case <-a.done:
+	default:
+		close(a.done)
+	}
+	a.wg.Wait()
+}
+
+// observe counts the root spans of td by service and operation. The operations of a service over
+// MaxOperationsPerService are ignored, as the span names are sent by the clients.
+func (a *adaptiveSampler) observe(td pdata.Traces) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		service := ""
+		if v, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName); ok {
+			service = v.StringVal()
+		}
+		if service == "" {
+			continue
+		}
+		counts, ok := a.counts[service]
+		if !ok {
+			counts = map[string]int64{}
+			a.counts[service] = counts
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !span.ParentSpanID().IsEmpty() {
+					continue
+				}
+				if _, ok := counts[span.Name()]; !ok && !a.tracked(service, span.Name(), len(counts)) {
+					continue
+				}
+				counts[span.Name()]++
+			}
+		}
+	}
+}
+
+// tracked returns whether the operation of the service is tracked, or can be, with count operations already counted
+// during the interval.
+func (a *adaptiveSampler) tracked(service, operation string, count int) bool {
+	operations := a.services[service]
+	if _, ok := operations[operation]; ok {
+		return true
+	}
+	return len(operations) < a.config.MaxOperationsPerService && count < a.config.MaxOperationsPerService
+}
+
+// calculate adjusts the probabilities to the spans observed during the interval. The sampled spans of an operation
+// are proportional to its probability, so the probability sampling the target throughput is the current one scaled
+// by the ratio of the target to the observed throughput.
+func (a *adaptiveSampler) calculate(interval time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for service, counts := range a.counts {
+		if _, ok := a.services[service]; !ok {
+			a.services[service] = map[string]*operationProbability{}
+		}
+		for operation := range counts {
+			if _, ok := a.services[service][operation]; !ok {
+				if len(a.services[service]) >= a.config.MaxOperationsPerService {
+					continue
+				}
+				a.services[service][operation] = &operationProbability{probability: a.config.InitialSamplingProbability}
+			}
+		}
+	}
+	for service, operations := range a.services {
+		for operation, p := range operations {
+			count := a.counts[service][operation]
+			if count == 0 {
+				p.idleIntervals++
+				if p.idleIntervals >= maxIdleIntervals {
+					delete(operations, operation)
+				}
+				continue
+			}
+			p.idleIntervals = 0
+			observed := float64(count) / interval.Seconds()
+			probability := p.probability * a.config.TargetSpansPerSecond / observed
+			probability = math.Min(probability, p.probability*maxProbabilityIncrease)
+			p.probability = math.Max(a.config.MinSamplingProbability, math.Min(1, probability))
+		}
+		if len(operations) == 0 {
+			delete(a.services, service)
+		}
+	}
+	a.counts = map[string]map[string]int64{}
+}
+
+// strategy returns the per-operation sampling strategy of the service.
+func (a *adaptiveSampler) strategy(service string) *sampling.SamplingStrategyResponse {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	operations := &sampling.PerOperationSamplingStrategies{
+		DefaultSamplingProbability: a.config.InitialSamplingProbability,
+	}
+	for operation, p := range a.services[service] {
+		operations.PerOperationStrategies = append(operations.PerOperationStrategies, &sampling.OperationSamplingStrategy{
+			Operation:             operation,
+			ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: p.probability},
+		})
+	}
+	sort.Slice(operations.PerOperationStrategies, func(i, j int) bool {
+		return operations.PerOperationStrategies[i].Operation < operations.PerOperationStrategies[j].Operation
+	})
+	return &sampling.SamplingStrategyResponse{
+		StrategyType: sampling.SamplingStrategyType_PROBABILISTIC,
+		ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{
+			SamplingRate: a.config.InitialSamplingProbability,
+		},
+		OperationSampling: operations,
+	}
+}
+
+// observedTraces is a consumer.Traces recording the consumed spans for the adaptive sampling.
+type observedTraces struct {
+	consumer.Traces
+	strategyStore *strategyStore
+}
+
+func (o *observedTraces) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	o.strategyStore.observe(td)
+	return o.Traces.ConsumeTraces(ctx, td)
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestAdaptiveSamplerCalculate(t *testing.T) {
+	a := newAdaptiveSampler(AdaptiveSamplingConfig{
+		TargetSpansPerSecond:       1,
+		CalculationInterval:        time.Minute,
+		InitialSamplingProbability: 0.01,
+		MinSamplingProbability:     0.0001,
+		MaxOperationsPerService:    10,
+	})
+	probability := func(service, operation string) float64 {
+		for _, s := range a.strategy(service).OperationSampling.PerOperationStrategies {
+			if s.Operation == operation {
+				return s.ProbabilisticSampling.SamplingRate
+			}
+		}
+		return -1
+	}
+
+	// 10 spans per second are sampled with 0.01, 0.001 samples 1 per second
+	a.observe(generateServiceTraces("foo", "a", 100))
+	a.observe(generateServiceTraces("foo", "b", 5))
+	a.calculate(10 * time.Second)
+	assert.InDelta(t, 0.001, probability("foo", "a"), 1e-9)
+	// the increase is capped
+	assert.InDelta(t, 0.02, probability("foo", "b"), 1e-9)
+	assert.Equal(t, -1.0, probability("bar", "a"))
+
+	// the probability is clamped to the minimum
+	a.observe(generateServiceTraces("foo", "a", 10000))
+	a.calculate(10 * time.Second)
+	assert.InDelta(t, 0.0001, probability("foo", "a"), 1e-9)
+
+	// the probability is kept without spans, and the operation forgotten after maxIdleIntervals
+	for i := 0; i < maxIdleIntervals-1; i++ {
+		a.calculate(10 * time.Second)
+	}
+	assert.InDelta(t, 0.0001, probability("foo", "a"), 1e-9)
+	a.calculate(10 * time.Second)
+	assert.Equal(t, -1.0, probability("foo", "a"))
+	assert.Empty(t, a.services)
+}
+
+func TestAdaptiveSamplerObserve(t *testing.T) {
+	config := DefaultAdaptiveSamplingConfig()
+	config.TargetSpansPerSecond = 1
+	config.MaxOperationsPerService = 2
+	a := newAdaptiveSampler(config)
+
+	// Only the root spans are counted.
+	td := generateServiceTraces("foo", "a", 3)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.At(0).SetParentSpanID(pdata.NewSpanID([8]byte{1}))
+	a.observe(td)
+	assert.Equal(t, map[string]map[string]int64{"foo": {"a": 2}}, a.counts)
+
+	// The operations over the maximum are ignored, in the counts and in the probabilities.
+	a.observe(generateServiceTraces("foo", "b", 1))
+	a.observe(generateServiceTraces("foo", "c", 1))
+	a.observe(generateServiceTraces("bar", "c", 1))
+	assert.Equal(t, map[string]map[string]int64{"foo": {"a": 2, "b": 1}, "bar": {"c": 1}}, a.counts)
+	a.calculate(time.Second)
+	assert.Len(t, a.services["foo"], 2)
+
+	a.observe(generateServiceTraces("foo", "c", 1))
+	a.observe(generateServiceTraces("foo", "a", 1))
+	assert.Equal(t, map[string]map[string]int64{"foo": {"a": 1}}, a.counts)
+	strategies := a.strategy("foo").OperationSampling.PerOperationStrategies
+	require.Len(t, strategies, 2)
+	assert.Equal(t, "a", strategies[0].Operation)
+	assert.Equal(t, "b", strategies[1].Operation)
+}
+
+func TestAdaptiveSamplingObservesReceivedSpans(t *testing.T) {
+	adaptive := DefaultAdaptiveSamplingConfig()
+	adaptive.TargetSpansPerSecond = 1
+	sink := new(consumertest.TracesSink)
+	ss := newStrategyStore("", 0, &adaptive, zap.NewNop())
+	next := &observedTraces{Traces: sink, strategyStore: ss}
+
+	require.NoError(t, next.ConsumeTraces(context.Background(), generateServiceTraces("foo", "op", 3)))
+	assert.Equal(t, 3, sink.SpansCount())
+	assert.Equal(t, map[string]map[string]int64{"foo": {"op": 3}}, ss.adaptive.counts)
+}
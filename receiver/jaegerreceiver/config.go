@@ -15,7 +15,9 @@
 package jaegerreceiver
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
@@ -26,21 +28,95 @@ import (
 const (
 	// The config field id to load the protocol map from
 	protocolsFieldName = "protocols"
+	// The config field id of the adaptive sampling
+	adaptiveSamplingFieldName = "remote_sampling::adaptive"
 
 	// Default UDP server options
 	defaultQueueSize        = 1_000
 	defaultMaxPacketSize    = 65_000
 	defaultServerWorkers    = 10
 	defaultSocketBufferSize = 0
+
+	// Default adaptive sampling options
+	defaultCalculationInterval        = time.Minute
+	defaultInitialSamplingProbability = 0.001
+	defaultMinSamplingProbability     = 1e-5
+	defaultMaxOperationsPerService    = 2000
 )
 
 // RemoteSamplingConfig defines config key for remote sampling fetch endpoint
 type RemoteSamplingConfig struct {
-	HostEndpoint                  string `mapstructure:"host_endpoint"`
-	StrategyFile                  string `mapstructure:"strategy_file"`
+	HostEndpoint string `mapstructure:"host_endpoint"`
+	StrategyFile string `mapstructure:"strategy_file"`
+	// StrategyFileReloadInterval is the interval the strategy file is checked for changes at, 10s if 0.
+	StrategyFileReloadInterval time.Duration `mapstructure:"strategy_file_reload_interval"`
+	// Adaptive enables the adaptive sampling of the services without strategy in the strategy file.
+	Adaptive                      *AdaptiveSamplingConfig `mapstructure:"adaptive"`
 	configgrpc.GRPCClientSettings `mapstructure:",squash"`
 }
 
+// AdaptiveSamplingConfig defines the adaptive sampling, which adjusts the sampling probability of each operation
+// of the services to the target throughput.
+type AdaptiveSamplingConfig struct {
+	// TargetSpansPerSecond is the number of spans per second to sample for each operation.
+	TargetSpansPerSecond float64 `mapstructure:"target_spans_per_second"`
+	// CalculationInterval is the interval the probabilities are recalculated at.
+	CalculationInterval time.Duration `mapstructure:"calculation_interval"`
+	// InitialSamplingProbability is the probability of the operations that have not been observed yet.
+	InitialSamplingProbability float64 `mapstructure:"initial_sampling_probability"`
+	// MinSamplingProbability is the minimum probability of the operations.
+	MinSamplingProbability float64 `mapstructure:"min_sampling_probability"`
+	// MaxOperationsPerService is the maximum number of operations tracked for each service. The spans of the
+	// other operations are ignored, and the operations get the initial sampling probability.
+	MaxOperationsPerService int `mapstructure:"max_operations_per_service"`
+}
+
+// DefaultAdaptiveSamplingConfig creates the default AdaptiveSamplingConfig.
+func DefaultAdaptiveSamplingConfig() AdaptiveSamplingConfig {
+	return AdaptiveSamplingConfig{
+		CalculationInterval:        defaultCalculationInterval,
+		InitialSamplingProbability: defaultInitialSamplingProbability,
+		MinSamplingProbability:     defaultMinSamplingProbability,
+		MaxOperationsPerService:    defaultMaxOperationsPerService,
+	}
+}
+
+// Validate checks the remote sampling configuration is valid
+func (cfg *RemoteSamplingConfig) Validate() error {
+	if err := cfg.GRPCClientSettings.Validate(); err != nil {
+		return err
+	}
+	if cfg.StrategyFileReloadInterval < 0 {
+		return errors.New("strategy_file_reload_interval can't be negative")
+	}
+	if cfg.Adaptive != nil {
+		if err := cfg.Adaptive.Validate(); err != nil {
+			return fmt.Errorf("invalid adaptive settings: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the adaptive sampling configuration is valid
+func (cfg *AdaptiveSamplingConfig) Validate() error {
+	if cfg.TargetSpansPerSecond <= 0 {
+		return errors.New("target_spans_per_second must be positive")
+	}
+	if cfg.CalculationInterval <= 0 {
+		return errors.New("calculation_interval must be positive")
+	}
+	if cfg.MinSamplingProbability <= 0 || cfg.MinSamplingProbability > 1 {
+		return errors.New("min_sampling_probability must be in (0, 1]")
+	}
+	if cfg.InitialSamplingProbability < cfg.MinSamplingProbability || cfg.InitialSamplingProbability > 1 {
+		return errors.New("initial_sampling_probability must be between min_sampling_probability and 1")
+	}
+	if cfg.MaxOperationsPerService <= 0 {
+		return errors.New("max_operations_per_service must be positive")
+	}
+	return nil
+}
+
 // Protocols is the configuration for the supported protocols.
 type Protocols struct {
 	GRPC          *configgrpc.GRPCServerSettings `mapstructure:"grpc"`
@@ -91,6 +167,21 @@ func (cfg *Config) Validate() error {
 		cfg.ThriftCompact == nil {
 		return fmt.Errorf("must specify at least one protocol when using the Jaeger receiver")
 	}
+	if cfg.GRPC != nil {
+		if err := cfg.GRPC.Validate(); err != nil {
+			return fmt.Errorf("invalid grpc settings: %w", err)
+		}
+	}
+	if cfg.ThriftHTTP != nil {
+		if err := cfg.ThriftHTTP.Validate(); err != nil {
+			return fmt.Errorf("invalid thrift_http settings: %w", err)
+		}
+	}
+	if cfg.RemoteSampling != nil {
+		if err := cfg.RemoteSampling.Validate(); err != nil {
+			return fmt.Errorf("invalid remote_sampling settings: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -100,6 +191,15 @@ func (cfg *Config) Unmarshal(componentParser *configparser.Parser) error {
 		return fmt.Errorf("empty config for Jaeger receiver")
 	}
 
+	// The adaptive sampling is disabled by default, set its defaults when it is enabled.
+	if componentParser.IsSet(adaptiveSamplingFieldName) {
+		if cfg.RemoteSampling == nil {
+			cfg.RemoteSampling = &RemoteSamplingConfig{}
+		}
+		adaptive := DefaultAdaptiveSamplingConfig()
+		cfg.RemoteSampling.Adaptive = &adaptive
+	}
+
 	// UnmarshalExact will not set struct properties to nil even if no key is provided,
 	// so set the protocol structs to nil where the keys were omitted.
 	err := componentParser.UnmarshalExact(cfg)
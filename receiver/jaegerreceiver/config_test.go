@@ -17,6 +17,7 @@ package jaegerreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -81,7 +82,15 @@ func TestLoadConfig(t *testing.T) {
 				GRPCClientSettings: configgrpc.GRPCClientSettings{
 					Endpoint: "jaeger-collector:1234",
 				},
-				StrategyFile: "/etc/strategies.json",
+				StrategyFile:               "/etc/strategies.json",
+				StrategyFileReloadInterval: 30 * time.Second,
+				Adaptive: &AdaptiveSamplingConfig{
+					TargetSpansPerSecond:       2,
+					CalculationInterval:        30 * time.Second,
+					InitialSamplingProbability: defaultInitialSamplingProbability,
+					MinSamplingProbability:     defaultMinSamplingProbability,
+					MaxOperationsPerService:    defaultMaxOperationsPerService,
+				},
 			},
 		})
 
@@ -170,4 +179,7 @@ func TestFailedLoadConfig(t *testing.T) {
 
 	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "bad_empty_config.yaml"), factories)
 	assert.EqualError(t, err, "error reading receivers configuration for jaeger: empty config for Jaeger receiver")
+
+	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "bad_adaptive_config.yaml"), factories)
+	assert.EqualError(t, err, "receiver \"jaeger\" has invalid configuration: invalid remote_sampling settings: invalid adaptive settings: target_spans_per_second must be positive")
 }
@@ -159,6 +159,16 @@ func createTracesReceiver(
 
 			config.RemoteSamplingStrategyFile = remoteSamplingConfig.StrategyFile
 		}
+		config.RemoteSamplingStrategyFileReloadInterval = remoteSamplingConfig.StrategyFileReloadInterval
+
+		// the adaptive strategies are also served over grpc
+		if remoteSamplingConfig.Adaptive != nil {
+			if config.CollectorGRPCPort == 0 {
+				return nil, fmt.Errorf("adaptive sampling requires the GRPC protocol to be enabled")
+			}
+
+			config.RemoteSamplingAdaptive = remoteSamplingConfig.Adaptive
+		}
 	}
 
 	if (rCfg.Protocols.GRPC == nil && rCfg.Protocols.ThriftHTTP == nil && rCfg.Protocols.ThriftBinary == nil && rCfg.Protocols.ThriftCompact == nil) ||
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -315,6 +316,9 @@ func TestRemoteSamplingConfigPropagation(t *testing.T) {
 	hostPort := 5778
 	endpoint := "localhost:1234"
 	strategyFile := "strategies.json"
+	reloadInterval := time.Minute
+	adaptive := DefaultAdaptiveSamplingConfig()
+	adaptive.TargetSpansPerSecond = 1
 	rCfg.Protocols.GRPC = &configgrpc.GRPCServerSettings{
 		NetAddr: confignet.NetAddr{
 			Endpoint:  defaultGRPCBindEndpoint,
@@ -325,8 +329,10 @@ func TestRemoteSamplingConfigPropagation(t *testing.T) {
 		GRPCClientSettings: configgrpc.GRPCClientSettings{
 			Endpoint: endpoint,
 		},
-		HostEndpoint: fmt.Sprintf("localhost:%d", hostPort),
-		StrategyFile: strategyFile,
+		HostEndpoint:               fmt.Sprintf("localhost:%d", hostPort),
+		StrategyFile:               strategyFile,
+		StrategyFileReloadInterval: reloadInterval,
+		Adaptive:                   &adaptive,
 	}
 	set := componenttest.NewNopReceiverCreateSettings()
 	r, err := factory.CreateTracesReceiver(context.Background(), set, cfg, nil)
@@ -335,6 +341,8 @@ func TestRemoteSamplingConfigPropagation(t *testing.T) {
 	assert.Equal(t, endpoint, r.(*jReceiver).config.RemoteSamplingClientSettings.Endpoint)
 	assert.Equal(t, hostPort, r.(*jReceiver).config.AgentHTTPPort, "agent http port should be configured value")
 	assert.Equal(t, strategyFile, r.(*jReceiver).config.RemoteSamplingStrategyFile)
+	assert.Equal(t, reloadInterval, r.(*jReceiver).config.RemoteSamplingStrategyFileReloadInterval)
+	assert.Equal(t, &adaptive, r.(*jReceiver).config.RemoteSamplingAdaptive)
 }
 
 func TestRemoteSamplingFileRequiresGRPC(t *testing.T) {
@@ -355,3 +363,24 @@ func TestRemoteSamplingFileRequiresGRPC(t *testing.T) {
 
 	assert.Error(t, err, "create trace receiver should error")
 }
+
+func TestRemoteSamplingAdaptiveRequiresGRPC(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	// Remove all default protocols
+	rCfg.Protocols = Protocols{}
+	rCfg.Protocols.ThriftCompact = &ProtocolUDP{
+		Endpoint: defaultThriftCompactBindEndpoint,
+	}
+	adaptive := DefaultAdaptiveSamplingConfig()
+	adaptive.TargetSpansPerSecond = 1
+	rCfg.RemoteSampling = &RemoteSamplingConfig{
+		Adaptive: &adaptive,
+	}
+	set := componenttest.NewNopReceiverCreateSettings()
+	_, err := factory.CreateTracesReceiver(context.Background(), set, cfg, nil)
+
+	assert.Error(t, err, "create trace receiver should error")
+}
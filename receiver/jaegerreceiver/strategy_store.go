@@ -0,0 +1,342 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/internal/filewatcher"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	samplerTypeProbabilistic = "probabilistic"
+	samplerTypeRateLimiting  = "ratelimiting"
+
+	// defaultSamplingProbability is the probability of the services without strategy, if the strategy file has no
+	// default strategy.
+	defaultSamplingProbability = 0.001
+
+	defaultStrategyFileReloadInterval = 10 * time.Second
+)
+
+// strategy is a sampling strategy of the strategy file, in the format of the Jaeger strategy files. Param is the
+// sampling probability of the probabilistic strategies, and the maximum number of traces per second of the rate
+// limiting ones.
+type strategy struct {
+	Type  string  `json:"type"`
+	Param float64 `json:"param"`
+}
+
+// operationStrategy is the strategy of an operation of a service.
+type operationStrategy struct {
+	Operation string `json:"operation"`
+	strategy
+}
+
+// serviceStrategy is the strategy of a service, and of its operations.
+type serviceStrategy struct {
+	Service             string               `json:"service"`
+	OperationStrategies []*operationStrategy `json:"operation_strategies"`
+	strategy
+}
+
+// strategies is the content of the strategy file.
+type strategies struct {
+	DefaultStrategy   *serviceStrategy   `json:"default_strategy"`
+	ServiceStrategies []*serviceStrategy `json:"service_strategies"`
+}
+
+// storedStrategies are the sampling strategies of the services, and the default one of the other services.
+type storedStrategies struct {
+	defaultStrategy   *sampling.SamplingStrategyResponse
+	serviceStrategies map[string]*sampling.SamplingStrategyResponse
+}
+
+// strategyStore serves the sampling strategies of the strategy file, which is reloaded when it changes. With
+// adaptive sampling, the services without strategy in the file get per-operation probabilities adjusted to their
+// observed throughput.
+type strategyStore struct {
+	file           string
+	reloadInterval time.Duration
+	logger         *zap.Logger
+	watcher        *filewatcher.Watcher
+	adaptive       *adaptiveSampler
+
+	mu         sync.RWMutex
+	strategies *storedStrategies
+}
+
+func newStrategyStore(file string, reloadInterval time.Duration, adaptive *AdaptiveSamplingConfig, logger *zap.Logger) *strategyStore {
+	if reloadInterval <= 0 {
+		reloadInterval = defaultStrategyFileReloadInterval
+	}
+	s := &strategyStore{
+		file:           file,
+		reloadInterval: reloadInterval,
+		logger:         logger,
+		strategies:     defaultStrategies(),
+	}
+	if adaptive != nil {
+		s.adaptive = newAdaptiveSampler(*adaptive)
+	}
+	return s
+}
+
+// start loads the strategy file, and starts watching it for changes.
+func (s *strategyStore) start() error {
+	if s.file != "" {
+		stored, err := loadStrategies(s.file)
+		if err != nil {
+			return err
+		}
+		s.strategies = stored
+		s.watcher = filewatcher.New(s.file, s.reloadInterval, s.reload)
+		s.watcher.Start()
+	}
+	if s.adaptive != nil {
+		s.adaptive.start()
+	}
+	return nil
+}
+
+// shutdown stops watching the strategy file.
+func (s *strategyStore) shutdown() {
+	if s.watcher != nil {
+		s.watcher.Stop()
+	}
+	if s.adaptive != nil {
+		s.adaptive.shutdown()
+	}
+}
+
+// reload loads the strategy file, the current strategies are kept if it is invalid.
+func (s *strategyStore) reload() {
+	stored, err := loadStrategies(s.file)
+	if err != nil {
+		s.logger.Error("Failed to reload the sampling strategy file, keeping the current strategies", zap.Error(err))
+		return
+	}
+	s.mu.Lock()
+	s.strategies = stored
+	s.mu.Unlock()
+	s.logger.Info("Reloaded the sampling strategy file", zap.String("strategy_file", s.file))
+}
+
+// GetSamplingStrategy returns the sampling strategy of the service.
+func (s *strategyStore) GetSamplingStrategy(_ context.Context, serviceName string) (*sampling.SamplingStrategyResponse, error) {
+	s.mu.RLock()
+	stored := s.strategies
+	s.mu.RUnlock()
+	if strategy, ok := stored.serviceStrategies[serviceName]; ok {
+		return strategy, nil
+	}
+	if s.adaptive != nil {
+		return s.adaptive.strategy(serviceName), nil
+	}
+	return stored.defaultStrategy, nil
+}
+
+// observe records the spans of td for the adaptive sampling.
+func (s *strategyStore) observe(td pdata.Traces) {
+	if s.adaptive != nil {
+		s.adaptive.observe(td)
+	}
+}
+
+func defaultStrategies() *storedStrategies {
+	return &storedStrategies{
+		defaultStrategy: &sampling.SamplingStrategyResponse{
+			StrategyType: sampling.SamplingStrategyType_PROBABILISTIC,
+			ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{
+				SamplingRate: defaultSamplingProbability,
+			},
+		},
+		serviceStrategies: map[string]*sampling.SamplingStrategyResponse{},
+	}
+}
+
+// loadStrategies reads and validates the strategy file. The operation strategies of the default strategy apply to
+// the operations of the services that have no strategy of their own, as in Jaeger.
+func loadStrategies(file string) (*storedStrategies, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the strategy file: %w", err)
+	}
+	var parsed strategies
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse the strategy file %s: %w", file, err)
+	}
+	if err = parsed.validate(); err != nil {
+		return nil, fmt.Errorf("invalid strategy file %s: %w", file, err)
+	}
+
+	stored := defaultStrategies()
+	if parsed.DefaultStrategy != nil {
+		stored.defaultStrategy = parsed.DefaultStrategy.response()
+	}
+	defaultOperations := stored.defaultStrategy.OperationSampling
+	for _, s := range parsed.ServiceStrategies {
+		response := s.response()
+		stored.serviceStrategies[s.Service] = response
+		if defaultOperations == nil {
+			continue
+		}
+		if response.OperationSampling == nil {
+			if response.ProbabilisticSampling == nil {
+				continue
+			}
+			operations := *defaultOperations
+			operations.DefaultSamplingProbability = response.ProbabilisticSampling.SamplingRate
+			response.OperationSampling = &operations
+			continue
+		}
+		response.OperationSampling.PerOperationStrategies = mergeOperationStrategies(
+			response.OperationSampling.PerOperationStrategies, defaultOperations.PerOperationStrategies)
+	}
+	return stored, nil
+}
+
+func (s *strategies) validate() error {
+	if s.DefaultStrategy != nil {
+		if s.DefaultStrategy.Service != "" {
+			return errors.New("the default strategy can't have a service")
+		}
+		if err := s.DefaultStrategy.validate(); err != nil {
+			return fmt.Errorf("default strategy: %w", err)
+		}
+	}
+	services := map[string]bool{}
+	for _, service := range s.ServiceStrategies {
+		if service.Service == "" {
+			return errors.New("service strategies must have a service")
+		}
+		if services[service.Service] {
+			return fmt.Errorf("duplicate strategy of service %q", service.Service)
+		}
+		services[service.Service] = true
+		if err := service.validate(); err != nil {
+			return fmt.Errorf("strategy of service %q: %w", service.Service, err)
+		}
+	}
+	return nil
+}
+
+func (s *serviceStrategy) validate() error {
+	if err := s.strategy.validate(); err != nil {
+		return err
+	}
+	operations := map[string]bool{}
+	for _, operation := range s.OperationStrategies {
+		if operation.Operation == "" {
+			return errors.New("operation strategies must have an operation")
+		}
+		if operations[operation.Operation] {
+			return fmt.Errorf("duplicate strategy of operation %q", operation.Operation)
+		}
+		operations[operation.Operation] = true
+		// The per-operation strategies of the Jaeger clients are probabilistic.
+		if operation.Type != samplerTypeProbabilistic {
+			return fmt.Errorf("strategy of operation %q must be %s", operation.Operation, samplerTypeProbabilistic)
+		}
+		if err := operation.strategy.validate(); err != nil {
+			return fmt.Errorf("strategy of operation %q: %w", operation.Operation, err)
+		}
+	}
+	return nil
+}
+
+func (s *strategy) validate() error {
+	switch s.Type {
+	case samplerTypeProbabilistic:
+		if s.Param < 0 || s.Param > 1 {
+			return fmt.Errorf("probabilistic param %v must be between 0 and 1", s.Param)
+		}
+	case samplerTypeRateLimiting:
+		if s.Param < 0 || s.Param > math.MaxInt16 {
+			return fmt.Errorf("ratelimiting param %v must be between 0 and %d", s.Param, math.MaxInt16)
+		}
+	default:
+		return fmt.Errorf("unknown strategy type %q, must be %s or %s", s.Type, samplerTypeProbabilistic, samplerTypeRateLimiting)
+	}
+	return nil
+}
+
+// response returns the sampling strategy response of the service strategy.
+func (s *serviceStrategy) response() *sampling.SamplingStrategyResponse {
+	response := s.strategy.response()
+	if len(s.OperationStrategies) == 0 {
+		return response
+	}
+	operations := &sampling.PerOperationSamplingStrategies{
+		DefaultSamplingProbability: defaultSamplingProbability,
+	}
+	if response.ProbabilisticSampling != nil {
+		operations.DefaultSamplingProbability = response.ProbabilisticSampling.SamplingRate
+	}
+	for _, operation := range s.OperationStrategies {
+		operations.PerOperationStrategies = append(operations.PerOperationStrategies, &sampling.OperationSamplingStrategy{
+			Operation:             operation.Operation,
+			ProbabilisticSampling: operation.strategy.response().ProbabilisticSampling,
+		})
+	}
+	response.OperationSampling = operations
+	return response
+}
+
+// response returns the sampling strategy response of the strategy.
+func (s *strategy) response() *sampling.SamplingStrategyResponse {
+	if s.Type == samplerTypeRateLimiting {
+		return &sampling.SamplingStrategyResponse{
+			StrategyType: sampling.SamplingStrategyType_RATE_LIMITING,
+			RateLimitingSampling: &sampling.RateLimitingSamplingStrategy{
+				MaxTracesPerSecond: int16(s.Param),
+			},
+		}
+	}
+	return &sampling.SamplingStrategyResponse{
+		StrategyType: sampling.SamplingStrategyType_PROBABILISTIC,
+		ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{
+			SamplingRate: s.Param,
+		},
+	}
+}
+
+// mergeOperationStrategies merges the operation strategies a and b, a taking precedence over b.
+func mergeOperationStrategies(a, b []*sampling.OperationSamplingStrategy) []*sampling.OperationSamplingStrategy {
+	operations := map[string]bool{}
+	for _, operation := range a {
+		operations[operation.Operation] = true
+	}
+	for _, operation := range b {
+		if !operations[operation.Operation] {
+			a = append(a, operation)
+		}
+	}
+	return a
+}
@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func probabilisticResponse(rate float64) *sampling.SamplingStrategyResponse {
+	return &sampling.SamplingStrategyResponse{
+		StrategyType:          sampling.SamplingStrategyType_PROBABILISTIC,
+		ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: rate},
+	}
+}
+
+func TestStrategyStore(t *testing.T) {
+	ss := newStrategyStore(path.Join(".", "testdata", "strategies.json"), 0, nil, zap.NewNop())
+	require.NoError(t, ss.start())
+	defer ss.shutdown()
+
+	resp, err := ss.GetSamplingStrategy(context.Background(), "bar")
+	require.NoError(t, err)
+	assert.Equal(t, &sampling.SamplingStrategyResponse{
+		StrategyType:         sampling.SamplingStrategyType_RATE_LIMITING,
+		RateLimitingSampling: &sampling.RateLimitingSamplingStrategy{MaxTracesPerSecond: 5},
+	}, resp)
+
+	resp, err = ss.GetSamplingStrategy(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.Equal(t, probabilisticResponse(0.5), resp)
+}
+
+func TestStrategyStoreDefaultOperations(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "strategies.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{
+  "default_strategy": {
+    "type": "probabilistic",
+    "param": 0.5,
+    "operation_strategies": [
+      {"operation": "/health", "type": "probabilistic", "param": 0}
+    ]
+  },
+  "service_strategies": [
+    {"service": "foo", "type": "probabilistic", "param": 0.8},
+    {"service": "bar", "type": "probabilistic", "param": 0.2, "operation_strategies": [
+      {"operation": "op", "type": "probabilistic", "param": 1}
+    ]}
+  ]
+}`), 0600))
+	ss := newStrategyStore(file, 0, nil, zap.NewNop())
+	require.NoError(t, ss.start())
+	defer ss.shutdown()
+
+	health := &sampling.OperationSamplingStrategy{
+		Operation:             "/health",
+		ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: 0},
+	}
+	resp, err := ss.GetSamplingStrategy(context.Background(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, resp.OperationSampling.DefaultSamplingProbability)
+	assert.Equal(t, []*sampling.OperationSamplingStrategy{health}, resp.OperationSampling.PerOperationStrategies)
+
+	resp, err = ss.GetSamplingStrategy(context.Background(), "bar")
+	require.NoError(t, err)
+	assert.Equal(t, 0.2, resp.OperationSampling.DefaultSamplingProbability)
+	assert.Equal(t, []*sampling.OperationSamplingStrategy{
+		{
+			Operation:             "op",
+			ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: 1},
+		},
+		health,
+	}, resp.OperationSampling.PerOperationStrategies)
+}
+
+func TestLoadStrategiesInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "invalid json",
+			content: `{`,
+			err:     "failed to parse the strategy file",
+		},
+		{
+			name:    "unknown field",
+			content: `{"default_strategy": {"type": "probabilistic", "parm": 0.1}}`,
+			err:     `unknown field "parm"`,
+		},
+		{
+			name:    "unknown type",
+			content: `{"default_strategy": {"type": "adaptive", "param": 0.1}}`,
+			err:     `unknown strategy type "adaptive"`,
+		},
+		{
+			name:    "probability out of range",
+			content: `{"service_strategies": [{"service": "foo", "type": "probabilistic", "param": 1.5}]}`,
+			err:     "probabilistic param 1.5 must be between 0 and 1",
+		},
+		{
+			name:    "negative rate",
+			content: `{"service_strategies": [{"service": "foo", "type": "ratelimiting", "param": -1}]}`,
+			err:     "ratelimiting param -1 must be between 0 and 32767",
+		},
+		{
+			name:    "missing service",
+			content: `{"service_strategies": [{"type": "probabilistic", "param": 0.1}]}`,
+			err:     "service strategies must have a service",
+		},
+		{
+			name: "duplicate service",
+			content: `{"service_strategies": [
+				{"service": "foo", "type": "probabilistic", "param": 0.1},
+				{"service": "foo", "type": "probabilistic", "param": 0.2}
+			]}`,
+			err: `duplicate strategy of service "foo"`,
+		},
+		{
+			name: "rate limiting operation",
+			content: `{"service_strategies": [{"service": "foo", "type": "probabilistic", "param": 0.1, "operation_strategies": [
+				{"operation": "op", "type": "ratelimiting", "param": 1}
+			]}]}`,
+			err: `strategy of operation "op" must be probabilistic`,
+		},
+		{
+			name: "duplicate operation",
+			content: `{"service_strategies": [{"service": "foo", "type": "probabilistic", "param": 0.1, "operation_strategies": [
+				{"operation": "op", "type": "probabilistic", "param": 1},
+				{"operation": "op", "type": "probabilistic", "param": 0}
+			]}]}`,
+			err: `duplicate strategy of operation "op"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "strategies.json")
+			require.NoError(t, ioutil.WriteFile(file, []byte(tt.content), 0600))
+			_, err := loadStrategies(file)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestStrategyStoreReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "strategies.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"default_strategy": {"type": "probabilistic", "param": 0.5}}`), 0600))
+	ss := newStrategyStore(file, 10*time.Millisecond, nil, zap.NewNop())
+	require.NoError(t, ss.start())
+	defer ss.shutdown()
+
+	getRate := func() float64 {
+		resp, err := ss.GetSamplingStrategy(context.Background(), "foo")
+		require.NoError(t, err)
+		return resp.ProbabilisticSampling.SamplingRate
+	}
+	assert.Equal(t, 0.5, getRate())
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"default_strategy": {"type": "probabilistic", "param": 0.25}}`), 0600))
+	assert.Eventually(t, func() bool { return getRate() == 0.25 }, 5*time.Second, 10*time.Millisecond)
+
+	// an invalid file keeps the current strategies
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"default_strategy": {"type": "probabilistic", "param": 2}}`), 0600))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 0.25, getRate())
+}
+
+func TestStrategyStoreAdaptive(t *testing.T) {
+	adaptive := DefaultAdaptiveSamplingConfig()
+	adaptive.TargetSpansPerSecond = 1
+	adaptive.InitialSamplingProbability = 0.1
+	ss := newStrategyStore(path.Join(".", "testdata", "strategies.json"), 0, &adaptive, zap.NewNop())
+	require.NoError(t, ss.start())
+	defer ss.shutdown()
+
+	td := generateServiceTraces("baz", "op", 20)
+	ss.observe(td)
+	ss.adaptive.calculate(time.Second)
+
+	// the services of the strategy file keep their strategies
+	resp, err := ss.GetSamplingStrategy(context.Background(), "bar")
+	require.NoError(t, err)
+	assert.Equal(t, sampling.SamplingStrategyType_RATE_LIMITING, resp.StrategyType)
+
+	resp, err = ss.GetSamplingStrategy(context.Background(), "baz")
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, resp.ProbabilisticSampling.SamplingRate)
+	assert.Equal(t, 0.1, resp.OperationSampling.DefaultSamplingProbability)
+	require.Len(t, resp.OperationSampling.PerOperationStrategies, 1)
+	assert.Equal(t, "op", resp.OperationSampling.PerOperationStrategies[0].Operation)
+	assert.InDelta(t, 0.005, resp.OperationSampling.PerOperationStrategies[0].ProbabilisticSampling.SamplingRate, 1e-9)
+}
+
+func generateServiceTraces(service, operation string, spans int) pdata.Traces {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", service)
+	ss := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for i := 0; i < spans; i++ {
+		ss.AppendEmpty().SetName(operation)
+	}
+	return td
+}
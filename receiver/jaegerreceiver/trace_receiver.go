@@ -23,6 +23,7 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"time"
 
 	apacheThrift "github.com/apache/thrift/lib/go/thrift"
 	"github.com/gorilla/mux"
@@ -34,7 +35,6 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/agent/app/servers/thriftudp"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	collectorSampling "github.com/jaegertracing/jaeger/cmd/collector/app/sampling"
-	staticStrategyStore "github.com/jaegertracing/jaeger/plugin/sampling/strategystore/static"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/jaegertracing/jaeger/thrift-gen/agent"
 	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
@@ -65,13 +65,15 @@ type configuration struct {
 	CollectorGRPCPort           int
 	CollectorGRPCServerSettings configgrpc.GRPCServerSettings
 
-	AgentCompactThriftPort       int
-	AgentCompactThriftConfig     ServerConfigUDP
-	AgentBinaryThriftPort        int
-	AgentBinaryThriftConfig      ServerConfigUDP
-	AgentHTTPPort                int
-	RemoteSamplingClientSettings configgrpc.GRPCClientSettings
-	RemoteSamplingStrategyFile   string
+	AgentCompactThriftPort                   int
+	AgentCompactThriftConfig                 ServerConfigUDP
+	AgentBinaryThriftPort                    int
+	AgentBinaryThriftConfig                  ServerConfigUDP
+	AgentHTTPPort                            int
+	RemoteSamplingClientSettings             configgrpc.GRPCClientSettings
+	RemoteSamplingStrategyFile               string
+	RemoteSamplingStrategyFileReloadInterval time.Duration
+	RemoteSamplingAdaptive                   *AdaptiveSamplingConfig
 }
 
 // Receiver type is used to receive spans that were originally intended to be sent to Jaeger.
@@ -86,6 +88,7 @@ type jReceiver struct {
 	collectorServer *http.Server
 
 	agentSamplingManager *jSamplingConfig.SamplingManager
+	strategyStore        *strategyStore
 	agentProcessors      []processors.Processor
 	agentServer          *http.Server
 
@@ -122,13 +125,20 @@ func newJaegerReceiver(
 	nextConsumer consumer.Traces,
 	set component.ReceiverCreateSettings,
 ) *jReceiver {
+	ss := newStrategyStore(config.RemoteSamplingStrategyFile, config.RemoteSamplingStrategyFileReloadInterval,
+		config.RemoteSamplingAdaptive, set.Logger)
+	if config.RemoteSamplingAdaptive != nil {
+		// the adaptive sampling observes the spans received by all the protocols
+		nextConsumer = &observedTraces{Traces: nextConsumer, strategyStore: ss}
+	}
 	return &jReceiver{
-		config:       config,
-		nextConsumer: nextConsumer,
-		id:           id,
-		logger:       set.Logger,
-		grpcObsrecv:  obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: id, Transport: grpcTransport}),
-		httpObsrecv:  obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: id, Transport: collectorHTTPTransport}),
+		config:        config,
+		nextConsumer:  nextConsumer,
+		strategyStore: ss,
+		id:            id,
+		logger:        set.Logger,
+		grpcObsrecv:   obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: id, Transport: grpcTransport}),
+		httpObsrecv:   obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: id, Transport: collectorHTTPTransport}),
 	}
 }
 
@@ -212,6 +222,7 @@ func (jr *jReceiver) Shutdown(ctx context.Context) error {
 	if jr.grpc != nil {
 		jr.grpc.GracefulStop()
 	}
+	jr.strategyStore.shutdown()
 
 	jr.goroutines.Wait()
 	return consumererror.Combine(errs)
@@ -444,15 +455,19 @@ func (jr *jReceiver) startCollector(host component.Host) error {
 	}
 
 	if jr.collectorHTTPEnabled() {
+		nr := mux.NewRouter()
+		nr.HandleFunc("/api/traces", jr.HandleThriftHTTPBatch).Methods(http.MethodPost)
+		var err error
+		jr.collectorServer, err = jr.config.CollectorHTTPSettings.ToServer(host.GetExtensions(), nr, confighttp.WithObsReport(jr.httpObsrecv))
+		if err != nil {
+			return fmt.Errorf("failed to build the Jaeger HTTP Collector server: %v", err)
+		}
+
 		cln, cerr := jr.config.CollectorHTTPSettings.ToListener()
 		if cerr != nil {
 			return fmt.Errorf("failed to bind to Collector address %q: %v",
 				jr.config.CollectorHTTPSettings.Endpoint, cerr)
 		}
-
-		nr := mux.NewRouter()
-		nr.HandleFunc("/api/traces", jr.HandleThriftHTTPBatch).Methods(http.MethodPost)
-		jr.collectorServer = jr.config.CollectorHTTPSettings.ToServer(nr)
 		jr.goroutines.Add(1)
 		go func() {
 			defer jr.goroutines.Done()
@@ -463,7 +478,7 @@ func (jr *jReceiver) startCollector(host component.Host) error {
 	}
 
 	if jr.collectorGRPCEnabled() {
-		opts, err := jr.config.CollectorGRPCServerSettings.ToServerOption(host.GetExtensions())
+		opts, err := jr.config.CollectorGRPCServerSettings.ToServerOption(host.GetExtensions(), configgrpc.WithObsReport(jr.grpcObsrecv))
 		if err != nil {
 			return fmt.Errorf("failed to build the options for the Jaeger gRPC Collector: %v", err)
 		}
@@ -474,17 +489,15 @@ func (jr *jReceiver) startCollector(host component.Host) error {
 		if gerr != nil {
 			return fmt.Errorf("failed to bind to gRPC address %q: %v", gaddr, gerr)
 		}
+		gln = jr.config.CollectorGRPCServerSettings.LimitListener(gln)
 
 		api_v2.RegisterCollectorServiceServer(jr.grpc, jr)
 
 		// init and register sampling strategy store
-		ss, gerr := staticStrategyStore.NewStrategyStore(staticStrategyStore.Options{
-			StrategiesFile: jr.config.RemoteSamplingStrategyFile,
-		}, jr.logger)
-		if gerr != nil {
-			return fmt.Errorf("failed to create collector strategy store: %v", gerr)
+		if serr := jr.strategyStore.start(); serr != nil {
+			return fmt.Errorf("failed to create collector strategy store: %v", serr)
 		}
-		api_v2.RegisterSamplingManagerServer(jr.grpc, collectorSampling.NewGRPCHandler(ss))
+		api_v2.RegisterSamplingManagerServer(jr.grpc, collectorSampling.NewGRPCHandler(jr.strategyStore))
 
 		jr.goroutines.Add(1)
 		go func() {
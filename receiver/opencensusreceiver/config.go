@@ -47,5 +47,5 @@ var _ config.Receiver = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
-	return nil
+	return cfg.GRPCServerSettings.Validate()
 }
@@ -33,14 +33,19 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/ocmetrics"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/octrace"
 )
 
+// grpcTransport is used for the requests refused before their data is read.
+const grpcTransport = "grpc"
+
 // ocReceiver is the type that exposes Trace and Metrics reception.
 type ocReceiver struct {
 	mu                 sync.Mutex
 	ln                 net.Listener
+	gatewayEndpoint    string
 	serverGRPC         *grpc.Server
 	serverHTTP         *http.Server
 	gatewayMux         *gatewayruntime.ServeMux
@@ -88,6 +93,13 @@ func newOpenCensusReceiver(
 	for _, opt := range opts {
 		opt.withReceiver(ocr)
 	}
+	// The grpc-gateway dials the gRPC server through the listener, so its endpoint is found
+	// before the listener is wrapped by the connection limits.
+	ocr.gatewayEndpoint = ln.Addr().String()
+	if _, ok := ln.(*net.UnixListener); ok {
+		ocr.gatewayEndpoint = "unix:" + ocr.gatewayEndpoint
+	}
+	ocr.ln = ocr.grpcServerSettings.LimitListener(ln)
 
 	return ocr, nil
 }
@@ -170,7 +182,8 @@ func (ocr *ocReceiver) grpcServer(host component.Host) (*grpc.Server, error) {
 	defer ocr.mu.Unlock()
 
 	if ocr.serverGRPC == nil {
-		opts, err := ocr.grpcServerSettings.ToServerOption(host.GetExtensions())
+		opts, err := ocr.grpcServerSettings.ToServerOption(host.GetExtensions(),
+			configgrpc.WithObsReport(obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: ocr.id, Transport: grpcTransport})))
 		if err != nil {
 			return nil, err
 		}
@@ -224,12 +237,7 @@ func (ocr *ocReceiver) startServer(host component.Host) error {
 	// Register the grpc-gateway on the HTTP server mux
 	c := context.Background()
 	opts := []grpc.DialOption{grpc.WithInsecure()}
-	endpoint := ocr.ln.Addr().String()
-
-	_, ok := ocr.ln.(*net.UnixListener)
-	if ok {
-		endpoint = "unix:" + endpoint
-	}
+	endpoint := ocr.gatewayEndpoint
 
 	if err := agenttracepb.RegisterTraceServiceHandlerFromEndpoint(c, ocr.gatewayMux, endpoint, opts); err != nil {
 		return err
@@ -287,9 +287,20 @@ func tempSocketName(t *testing.T) string {
 }
 
 func TestReceiveOnUnixDomainSocket_endToEnd(t *testing.T) {
+	t.Run("no limits", func(t *testing.T) {
+		testReceiveOnUnixDomainSocket(t)
+	})
+	// The grpc-gateway still dials the unix socket once the listener is wrapped by the connection limits,
+	// with one connection for traces and one for metrics, next to the one of the HTTP client.
+	t.Run("connection limits", func(t *testing.T) {
+		testReceiveOnUnixDomainSocket(t, withGRPCServerSettings(configgrpc.GRPCServerSettings{MaxConnectionsPerPeer: 3}))
+	})
+}
+
+func testReceiveOnUnixDomainSocket(t *testing.T, opts ...ocOption) {
 	socketName := tempSocketName(t)
 	cbts := consumertest.NewNop()
-	r, err := newOpenCensusReceiver(ocReceiverID, "unix", socketName, cbts, nil)
+	r, err := newOpenCensusReceiver(ocReceiverID, "unix", socketName, cbts, nil, opts...)
 	require.NoError(t, err)
 	require.NotNil(t, r)
 	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
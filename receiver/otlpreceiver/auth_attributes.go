@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerhelper"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// setResourceAttributes sets the configured attributes on attrs from the AuthData in ctx.
+func (aa *AuthAttributes) setResourceAttributes(ctx context.Context, attrs pdata.AttributeMap) {
+	ad, ok := configauth.AuthDataFromContext(ctx)
+
+	if aa.SubjectKey != "" {
+		if ok && ad.Subject != "" {
+			attrs.UpsertString(aa.SubjectKey, ad.Subject)
+		} else {
+			attrs.Delete(aa.SubjectKey)
+		}
+	}
+
+	if aa.GroupsKey != "" {
+		if ok && len(ad.Groups) > 0 {
+			groups := pdata.NewAttributeValueArray()
+			for _, g := range ad.Groups {
+				groups.ArrayVal().AppendEmpty().SetStringVal(g)
+			}
+			attrs.Upsert(aa.GroupsKey, groups)
+		} else {
+			attrs.Delete(aa.GroupsKey)
+		}
+	}
+}
+
+// wrapTraces returns a consumer.Traces setting the auth attributes before passing the data to next.
+func (aa *AuthAttributes) wrapTraces(next consumer.Traces) (consumer.Traces, error) {
+	return consumerhelper.NewTraces(func(ctx context.Context, td pdata.Traces) error {
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			aa.setResourceAttributes(ctx, rss.At(i).Resource().Attributes())
+		}
+		return next.ConsumeTraces(ctx, td)
+	})
+}
+
+// wrapMetrics returns a consumer.Metrics setting the auth attributes before passing the data to next.
+func (aa *AuthAttributes) wrapMetrics(next consumer.Metrics) (consumer.Metrics, error) {
+	return consumerhelper.NewMetrics(func(ctx context.Context, md pdata.Metrics) error {
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			aa.setResourceAttributes(ctx, rms.At(i).Resource().Attributes())
+		}
+		return next.ConsumeMetrics(ctx, md)
+	})
+}
+
+// wrapLogs returns a consumer.Logs setting the auth attributes before passing the data to next.
+func (aa *AuthAttributes) wrapLogs(next consumer.Logs) (consumer.Logs, error) {
+	return consumerhelper.NewLogs(func(ctx context.Context, ld pdata.Logs) error {
+		rls := ld.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			aa.setResourceAttributes(ctx, rls.At(i).Resource().Attributes())
+		}
+		return next.ConsumeLogs(ctx, ld)
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestAuthAttributesTraces(t *testing.T) {
+	aa := &AuthAttributes{SubjectKey: "tenant.id", GroupsKey: "tenant.groups"}
+	sink := new(consumertest.TracesSink)
+	tc, err := aa.wrapTraces(sink)
+	require.NoError(t, err)
+
+	ctx := configauth.NewContextWithAuthData(context.Background(), &configauth.AuthData{
+		Subject: "tenant-1",
+		Groups:  []string{"team-a", "team-b"},
+	})
+	td := testdata.GenerateTracesOneSpan()
+	td.ResourceSpans().At(0).Resource().Attributes().UpsertString("tenant.id", "spoofed")
+	require.NoError(t, tc.ConsumeTraces(ctx, td))
+
+	require.Len(t, sink.AllTraces(), 1)
+	attrs := sink.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes()
+	subject, ok := attrs.Get("tenant.id")
+	require.True(t, ok)
+	assert.Equal(t, "tenant-1", subject.StringVal())
+	groups, ok := attrs.Get("tenant.groups")
+	require.True(t, ok)
+	require.Equal(t, pdata.AttributeValueTypeArray, groups.Type())
+	assert.Equal(t, 2, groups.ArrayVal().Len())
+	assert.Equal(t, "team-b", groups.ArrayVal().At(1).StringVal())
+}
+
+func TestAuthAttributesNotAuthenticated(t *testing.T) {
+	aa := &AuthAttributes{SubjectKey: "tenant.id", GroupsKey: "tenant.groups"}
+
+	msink := new(consumertest.MetricsSink)
+	mc, err := aa.wrapMetrics(msink)
+	require.NoError(t, err)
+	md := testdata.GenerateMetricsOneMetric()
+	md.ResourceMetrics().At(0).Resource().Attributes().UpsertString("tenant.id", "spoofed")
+	require.NoError(t, mc.ConsumeMetrics(context.Background(), md))
+	require.Len(t, msink.AllMetrics(), 1)
+	_, ok := msink.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes().Get("tenant.id")
+	assert.False(t, ok)
+
+	lsink := new(consumertest.LogsSink)
+	lc, err := aa.wrapLogs(lsink)
+	require.NoError(t, err)
+	ld := testdata.GenerateLogsOneLogRecord()
+	ld.ResourceLogs().At(0).Resource().Attributes().UpsertString("tenant.groups", "spoofed")
+	require.NoError(t, lc.ConsumeLogs(context.Background(), ld))
+	require.Len(t, lsink.AllLogs(), 1)
+	_, ok = lsink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Get("tenant.groups")
+	assert.False(t, ok)
+}
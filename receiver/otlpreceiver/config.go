@@ -16,6 +16,7 @@ package otlpreceiver
 
 import (
 	"fmt"
+	"strings"
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
@@ -33,7 +34,40 @@ const (
 // Protocols is the configuration for the supported protocols.
 type Protocols struct {
 	GRPC *configgrpc.GRPCServerSettings `mapstructure:"grpc"`
-	HTTP *confighttp.HTTPServerSettings `mapstructure:"http"`
+	HTTP *HTTPConfig                    `mapstructure:"http"`
+}
+
+// HTTPConfig is the configuration of the OTLP/HTTP protocol.
+type HTTPConfig struct {
+	confighttp.HTTPServerSettings `mapstructure:",squash"`
+
+	// TracesURLPath is the URL path the traces are received on, "/v1/traces" by default.
+	TracesURLPath string `mapstructure:"traces_url_path"`
+
+	// MetricsURLPath is the URL path the metrics are received on, "/v1/metrics" by default.
+	MetricsURLPath string `mapstructure:"metrics_url_path"`
+
+	// LogsURLPath is the URL path the logs are received on, "/v1/logs" by default.
+	LogsURLPath string `mapstructure:"logs_url_path"`
+}
+
+// Validate checks the HTTP settings and URL paths.
+func (hc *HTTPConfig) Validate() error {
+	paths := map[string]string{}
+	for _, p := range []struct{ name, path string }{
+		{"traces_url_path", hc.TracesURLPath},
+		{"metrics_url_path", hc.MetricsURLPath},
+		{"logs_url_path", hc.LogsURLPath},
+	} {
+		if !strings.HasPrefix(p.path, "/") {
+			return fmt.Errorf("%s %q must start with \"/\"", p.name, p.path)
+		}
+		if other, ok := paths[p.path]; ok {
+			return fmt.Errorf("%s and %s must be different, both are %q", other, p.name, p.path)
+		}
+		paths[p.path] = p.name
+	}
+	return hc.HTTPServerSettings.Validate()
 }
 
 // Config defines configuration for OTLP receiver.
@@ -41,6 +75,19 @@ type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 	// Protocols is the configuration for the supported protocols, currently gRPC and HTTP (Proto and JSON).
 	Protocols `mapstructure:"protocols"`
+	// AuthAttributes configures the resource attributes set from the identity of the authenticated client.
+	AuthAttributes *AuthAttributes `mapstructure:"auth_attributes"`
+}
+
+// AuthAttributes configures setting resource attributes from the identity of the client,
+// as established by the authenticator configured for the protocol the data was received on.
+// The attributes are always overwritten, and removed when the request was not authenticated,
+// so that downstream components never see identity attributes supplied by the client.
+type AuthAttributes struct {
+	// SubjectKey is the resource attribute key set to the authenticated subject, e.g. "tenant.id".
+	SubjectKey string `mapstructure:"subject_key"`
+	// GroupsKey is the resource attribute key set to the groups of the authenticated subject.
+	GroupsKey string `mapstructure:"groups_key"`
 }
 
 var _ config.Receiver = (*Config)(nil)
@@ -52,6 +99,19 @@ func (cfg *Config) Validate() error {
 		cfg.HTTP == nil {
 		return fmt.Errorf("must specify at least one protocol when using the OTLP receiver")
 	}
+	if cfg.AuthAttributes != nil && cfg.AuthAttributes.SubjectKey == "" && cfg.AuthAttributes.GroupsKey == "" {
+		return fmt.Errorf("must specify at least one of subject_key or groups_key in auth_attributes")
+	}
+	if cfg.GRPC != nil {
+		if err := cfg.GRPC.Validate(); err != nil {
+			return fmt.Errorf("invalid grpc settings: %w", err)
+		}
+	}
+	if cfg.HTTP != nil {
+		if err := cfg.HTTP.Validate(); err != nil {
+			return fmt.Errorf("invalid http settings: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -24,6 +24,7 @@ import (
 
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/confignet"
@@ -42,7 +43,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 10)
+	assert.Equal(t, len(cfg.Receivers), 12)
 
 	assert.Equal(t, cfg.Receivers[config.NewID(typeStr)], factory.CreateDefaultConfig())
 
@@ -138,14 +139,19 @@ func TestLoadConfig(t *testing.T) {
 					},
 					ReadBufferSize: 512 * 1024,
 				},
-				HTTP: &confighttp.HTTPServerSettings{
-					Endpoint: "0.0.0.0:55681",
-					TLSSetting: &configtls.TLSServerSetting{
-						TLSSetting: configtls.TLSSetting{
-							CertFile: "test.crt",
-							KeyFile:  "test.key",
+				HTTP: &HTTPConfig{
+					HTTPServerSettings: confighttp.HTTPServerSettings{
+						Endpoint: "0.0.0.0:55681",
+						TLSSetting: &configtls.TLSServerSetting{
+							TLSSetting: configtls.TLSSetting{
+								CertFile: "test.crt",
+								KeyFile:  "test.key",
+							},
 						},
 					},
+					TracesURLPath:  defaultTracesURLPath,
+					MetricsURLPath: defaultMetricsURLPath,
+					LogsURLPath:    defaultLogsURLPath,
 				},
 			},
 		})
@@ -154,9 +160,14 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "cors")),
 			Protocols: Protocols{
-				HTTP: &confighttp.HTTPServerSettings{
-					Endpoint:    "0.0.0.0:55681",
-					CorsOrigins: []string{"https://*.test.com", "https://test.com"},
+				HTTP: &HTTPConfig{
+					HTTPServerSettings: confighttp.HTTPServerSettings{
+						Endpoint:    "0.0.0.0:55681",
+						CorsOrigins: []string{"https://*.test.com", "https://test.com"},
+					},
+					TracesURLPath:  defaultTracesURLPath,
+					MetricsURLPath: defaultMetricsURLPath,
+					LogsURLPath:    defaultLogsURLPath,
 				},
 			},
 		})
@@ -165,10 +176,15 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "corsheader")),
 			Protocols: Protocols{
-				HTTP: &confighttp.HTTPServerSettings{
-					Endpoint:    "0.0.0.0:55681",
-					CorsOrigins: []string{"https://*.test.com", "https://test.com"},
-					CorsHeaders: []string{"ExampleHeader"},
+				HTTP: &HTTPConfig{
+					HTTPServerSettings: confighttp.HTTPServerSettings{
+						Endpoint:    "0.0.0.0:55681",
+						CorsOrigins: []string{"https://*.test.com", "https://test.com"},
+						CorsHeaders: []string{"ExampleHeader"},
+					},
+					TracesURLPath:  defaultTracesURLPath,
+					MetricsURLPath: defaultMetricsURLPath,
+					LogsURLPath:    defaultLogsURLPath,
 				},
 			},
 		})
@@ -184,9 +200,57 @@ func TestLoadConfig(t *testing.T) {
 					},
 					ReadBufferSize: 512 * 1024,
 				},
-				HTTP: &confighttp.HTTPServerSettings{
-					Endpoint: "/tmp/http_otlp.sock",
-					// Transport: "unix",
+				HTTP: &HTTPConfig{
+					HTTPServerSettings: confighttp.HTTPServerSettings{
+						Endpoint: "/tmp/http_otlp.sock",
+						// Transport: "unix",
+					},
+					TracesURLPath:  defaultTracesURLPath,
+					MetricsURLPath: defaultMetricsURLPath,
+					LogsURLPath:    defaultLogsURLPath,
+				},
+			},
+		})
+
+	assert.Equal(t, cfg.Receivers[config.NewIDWithName(typeStr, "auth")],
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "auth")),
+			Protocols: Protocols{
+				GRPC: &configgrpc.GRPCServerSettings{
+					NetAddr: confignet.NetAddr{
+						Endpoint:  "0.0.0.0:4317",
+						Transport: "tcp",
+					},
+					ReadBufferSize: 512 * 1024,
+					Auth:           &configauth.Authentication{AuthenticatorName: "oidc"},
+				},
+				HTTP: &HTTPConfig{
+					HTTPServerSettings: confighttp.HTTPServerSettings{
+						Endpoint: "0.0.0.0:55681",
+						Auth:     &configauth.Authentication{AuthenticatorName: "oidc"},
+					},
+					TracesURLPath:  defaultTracesURLPath,
+					MetricsURLPath: defaultMetricsURLPath,
+					LogsURLPath:    defaultLogsURLPath,
+				},
+			},
+			AuthAttributes: &AuthAttributes{
+				SubjectKey: "tenant.id",
+				GroupsKey:  "tenant.groups",
+			},
+		})
+
+	assert.Equal(t, cfg.Receivers[config.NewIDWithName(typeStr, "url_paths")],
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "url_paths")),
+			Protocols: Protocols{
+				HTTP: &HTTPConfig{
+					HTTPServerSettings: confighttp.HTTPServerSettings{
+						Endpoint: "0.0.0.0:55681",
+					},
+					TracesURLPath:  "/otlp/v1/traces",
+					MetricsURLPath: "/otlp/v1/metrics",
+					LogsURLPath:    "/otlp/v1/logs",
 				},
 			},
 		})
@@ -209,4 +273,24 @@ func TestFailedLoadConfig(t *testing.T) {
 
 	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "bad_empty_config.yaml"), factories)
 	assert.EqualError(t, err, "error reading receivers configuration for otlp: empty config for OTLP receiver")
+
+	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "bad_auth_attributes_config.yaml"), factories)
+	assert.EqualError(t, err, "receiver \"otlp\" has invalid configuration: must specify at least one of subject_key or groups_key in auth_attributes")
+
+	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "bad_url_path_config.yaml"), factories)
+	assert.EqualError(t, err, "receiver \"otlp\" has invalid configuration: invalid http settings: metrics_url_path \"v1/metrics\" must start with \"/\"")
+
+	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "bad_tls_config.yaml"), factories)
+	assert.EqualError(t, err, "receiver \"otlp\" has invalid configuration: invalid http settings: invalid min_version: unsupported TLS version \"1.4\", must be one of \"1.0\", \"1.1\", \"1.2\" or \"1.3\"")
+}
+
+func TestHTTPConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.HTTP.Validate())
+
+	cfg.HTTP.LogsURLPath = cfg.HTTP.TracesURLPath
+	assert.EqualError(t, cfg.HTTP.Validate(), `traces_url_path and logs_url_path must be different, both are "/v1/traces"`)
+
+	cfg.HTTP.LogsURLPath = ""
+	assert.EqualError(t, cfg.HTTP.Validate(), `logs_url_path "" must start with "/"`)
 }
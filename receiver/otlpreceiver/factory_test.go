@@ -63,8 +63,13 @@ func TestCreateTracesReceiver(t *testing.T) {
 			Transport: "tcp",
 		},
 	}
-	defaultHTTPSettings := &confighttp.HTTPServerSettings{
-		Endpoint: testutil.GetAvailableLocalAddress(t),
+	defaultHTTPSettings := &HTTPConfig{
+		HTTPServerSettings: confighttp.HTTPServerSettings{
+			Endpoint: testutil.GetAvailableLocalAddress(t),
+		},
+		TracesURLPath:  defaultTracesURLPath,
+		MetricsURLPath: defaultMetricsURLPath,
+		LogsURLPath:    defaultLogsURLPath,
 	}
 
 	tests := []struct {
@@ -104,8 +109,13 @@ func TestCreateTracesReceiver(t *testing.T) {
 				ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
 				Protocols: Protocols{
 					GRPC: defaultGRPCSettings,
-					HTTP: &confighttp.HTTPServerSettings{
-						Endpoint: "localhost:112233",
+					HTTP: &HTTPConfig{
+						HTTPServerSettings: confighttp.HTTPServerSettings{
+							Endpoint: "localhost:112233",
+						},
+						TracesURLPath:  defaultTracesURLPath,
+						MetricsURLPath: defaultMetricsURLPath,
+						LogsURLPath:    defaultLogsURLPath,
 					},
 				},
 			},
@@ -139,8 +149,13 @@ func TestCreateMetricReceiver(t *testing.T) {
 			Transport: "tcp",
 		},
 	}
-	defaultHTTPSettings := &confighttp.HTTPServerSettings{
-		Endpoint: testutil.GetAvailableLocalAddress(t),
+	defaultHTTPSettings := &HTTPConfig{
+		HTTPServerSettings: confighttp.HTTPServerSettings{
+			Endpoint: testutil.GetAvailableLocalAddress(t),
+		},
+		TracesURLPath:  defaultTracesURLPath,
+		MetricsURLPath: defaultMetricsURLPath,
+		LogsURLPath:    defaultLogsURLPath,
 	}
 
 	tests := []struct {
@@ -180,8 +195,13 @@ func TestCreateMetricReceiver(t *testing.T) {
 				ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
 				Protocols: Protocols{
 					GRPC: defaultGRPCSettings,
-					HTTP: &confighttp.HTTPServerSettings{
-						Endpoint: "327.0.0.1:1122",
+					HTTP: &HTTPConfig{
+						HTTPServerSettings: confighttp.HTTPServerSettings{
+							Endpoint: "327.0.0.1:1122",
+						},
+						TracesURLPath:  defaultTracesURLPath,
+						MetricsURLPath: defaultMetricsURLPath,
+						LogsURLPath:    defaultLogsURLPath,
 					},
 				},
 			},
@@ -214,8 +234,13 @@ func TestCreateLogReceiver(t *testing.T) {
 			Transport: "tcp",
 		},
 	}
-	defaultHTTPSettings := &confighttp.HTTPServerSettings{
-		Endpoint: testutil.GetAvailableLocalAddress(t),
+	defaultHTTPSettings := &HTTPConfig{
+		HTTPServerSettings: confighttp.HTTPServerSettings{
+			Endpoint: testutil.GetAvailableLocalAddress(t),
+		},
+		TracesURLPath:  defaultTracesURLPath,
+		MetricsURLPath: defaultMetricsURLPath,
+		LogsURLPath:    defaultLogsURLPath,
 	}
 
 	tests := []struct {
@@ -259,8 +284,13 @@ func TestCreateLogReceiver(t *testing.T) {
 				ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
 				Protocols: Protocols{
 					GRPC: defaultGRPCSettings,
-					HTTP: &confighttp.HTTPServerSettings{
-						Endpoint: "327.0.0.1:1122",
+					HTTP: &HTTPConfig{
+						HTTPServerSettings: confighttp.HTTPServerSettings{
+							Endpoint: "327.0.0.1:1122",
+						},
+						TracesURLPath:  defaultTracesURLPath,
+						MetricsURLPath: defaultMetricsURLPath,
+						LogsURLPath:    defaultLogsURLPath,
 					},
 				},
 			},
@@ -273,8 +303,13 @@ func TestCreateLogReceiver(t *testing.T) {
 				ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
 				Protocols: Protocols{
 					GRPC: defaultGRPCSettings,
-					HTTP: &confighttp.HTTPServerSettings{
-						Endpoint: "327.0.0.1:1122",
+					HTTP: &HTTPConfig{
+						HTTPServerSettings: confighttp.HTTPServerSettings{
+							Endpoint: "327.0.0.1:1122",
+						},
+						TracesURLPath:  defaultTracesURLPath,
+						MetricsURLPath: defaultMetricsURLPath,
+						LogsURLPath:    defaultLogsURLPath,
 					},
 				},
 			},
@@ -29,16 +29,17 @@ import (
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/logs"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/metrics"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/trace"
 )
 
 const (
-	pbContentType   = "application/x-protobuf"
-	jsonContentType = "application/json"
+	// grpcTransport and httpTransport are used for the requests refused before their data is read.
+	grpcTransport = "grpc"
+	httpTransport = "http"
 )
 
 // otlpReceiver is the type that exposes Trace and Metrics reception.
@@ -111,7 +112,8 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 	var err error
 	if r.cfg.GRPC != nil {
 		var opts []grpc.ServerOption
-		opts, err = r.cfg.GRPC.ToServerOption(host.GetExtensions())
+		opts, err = r.cfg.GRPC.ToServerOption(host.GetExtensions(),
+			configgrpc.WithObsReport(obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: r.cfg.ID(), Transport: grpcTransport})))
 		if err != nil {
 			return err
 		}
@@ -147,11 +149,16 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 		}
 	}
 	if r.cfg.HTTP != nil {
-		r.serverHTTP = r.cfg.HTTP.ToServer(
+		r.serverHTTP, err = r.cfg.HTTP.ToServer(
+			host.GetExtensions(),
 			r.httpMux,
 			confighttp.WithErrorHandler(errorHandler),
+			confighttp.WithObsReport(obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: r.cfg.ID(), Transport: httpTransport})),
 		)
-		err = r.startHTTPServer(r.cfg.HTTP, host)
+		if err != nil {
+			return err
+		}
+		err = r.startHTTPServer(&r.cfg.HTTP.HTTPServerSettings, host)
 		if err != nil {
 			return err
 		}
@@ -182,67 +189,64 @@ func (r *otlpReceiver) Shutdown(ctx context.Context) error {
 	return err
 }
 
-var tracesPbUnmarshaler = otlp.NewProtobufTracesUnmarshaler()
-var tracesJSONUnmarshaler = otlp.NewJSONTracesUnmarshaler()
-
 func (r *otlpReceiver) registerTraceConsumer(tc consumer.Traces) error {
 	if tc == nil {
 		return componenterror.ErrNilNextConsumer
 	}
+	if r.cfg.AuthAttributes != nil {
+		var err error
+		if tc, err = r.cfg.AuthAttributes.wrapTraces(tc); err != nil {
+			return err
+		}
+	}
 	r.traceReceiver = trace.New(r.cfg.ID(), tc)
 	if r.httpMux != nil {
-		r.httpMux.HandleFunc("/v1/traces", func(resp http.ResponseWriter, req *http.Request) {
-			handleTraces(resp, req, pbContentType, r.traceReceiver, tracesPbUnmarshaler)
-		}).Methods(http.MethodPost).Headers("Content-Type", pbContentType)
-		// For backwards compatibility see https://github.com/open-telemetry/opentelemetry-collector/issues/1968
-		r.httpMux.HandleFunc("/v1/trace", func(resp http.ResponseWriter, req *http.Request) {
-			handleTraces(resp, req, pbContentType, r.traceReceiver, tracesPbUnmarshaler)
-		}).Methods(http.MethodPost).Headers("Content-Type", pbContentType)
-		r.httpMux.HandleFunc("/v1/traces", func(resp http.ResponseWriter, req *http.Request) {
-			handleTraces(resp, req, jsonContentType, r.traceReceiver, tracesJSONUnmarshaler)
-		}).Methods(http.MethodPost).Headers("Content-Type", jsonContentType)
-		// For backwards compatibility see https://github.com/open-telemetry/opentelemetry-collector/issues/1968
-		r.httpMux.HandleFunc("/v1/trace", func(resp http.ResponseWriter, req *http.Request) {
-			handleTraces(resp, req, jsonContentType, r.traceReceiver, tracesJSONUnmarshaler)
-		}).Methods(http.MethodPost).Headers("Content-Type", jsonContentType)
+		handler := func(resp http.ResponseWriter, req *http.Request) {
+			handleTraces(resp, req, r.traceReceiver)
+		}
+		r.httpMux.HandleFunc(r.cfg.HTTP.TracesURLPath, handler).Methods(http.MethodPost)
+		if r.cfg.HTTP.TracesURLPath == defaultTracesURLPath {
+			// For backwards compatibility see https://github.com/open-telemetry/opentelemetry-collector/issues/1968
+			r.httpMux.HandleFunc("/v1/trace", handler).Methods(http.MethodPost)
+		}
 	}
 	return nil
 }
 
-var metricsPbUnmarshaler = otlp.NewProtobufMetricsUnmarshaler()
-var metricsJSONUnmarshaler = otlp.NewJSONMetricsUnmarshaler()
-
 func (r *otlpReceiver) registerMetricsConsumer(mc consumer.Metrics) error {
 	if mc == nil {
 		return componenterror.ErrNilNextConsumer
 	}
+	if r.cfg.AuthAttributes != nil {
+		var err error
+		if mc, err = r.cfg.AuthAttributes.wrapMetrics(mc); err != nil {
+			return err
+		}
+	}
 	r.metricsReceiver = metrics.New(r.cfg.ID(), mc)
 	if r.httpMux != nil {
-		r.httpMux.HandleFunc("/v1/metrics", func(resp http.ResponseWriter, req *http.Request) {
-			handleMetrics(resp, req, pbContentType, r.metricsReceiver, metricsPbUnmarshaler)
-		}).Methods(http.MethodPost).Headers("Content-Type", pbContentType)
-		r.httpMux.HandleFunc("/v1/metrics", func(resp http.ResponseWriter, req *http.Request) {
-			handleMetrics(resp, req, jsonContentType, r.metricsReceiver, metricsJSONUnmarshaler)
-		}).Methods(http.MethodPost).Headers("Content-Type", jsonContentType)
+		r.httpMux.HandleFunc(r.cfg.HTTP.MetricsURLPath, func(resp http.ResponseWriter, req *http.Request) {
+			handleMetrics(resp, req, r.metricsReceiver)
+		}).Methods(http.MethodPost)
 	}
 	return nil
 }
 
-var logsPbUnmarshaler = otlp.NewProtobufLogsUnmarshaler()
-var logsJSONUnmarshaler = otlp.NewJSONLogsUnmarshaler()
-
 func (r *otlpReceiver) registerLogsConsumer(lc consumer.Logs) error {
 	if lc == nil {
 		return componenterror.ErrNilNextConsumer
 	}
+	if r.cfg.AuthAttributes != nil {
+		var err error
+		if lc, err = r.cfg.AuthAttributes.wrapLogs(lc); err != nil {
+			return err
+		}
+	}
 	r.logReceiver = logs.New(r.cfg.ID(), lc)
 	if r.httpMux != nil {
-		r.httpMux.HandleFunc("/v1/logs", func(w http.ResponseWriter, req *http.Request) {
-			handleLogs(w, req, pbContentType, r.logReceiver, logsPbUnmarshaler)
-		}).Methods(http.MethodPost).Headers("Content-Type", pbContentType)
-		r.httpMux.HandleFunc("/v1/logs", func(w http.ResponseWriter, req *http.Request) {
-			handleLogs(w, req, jsonContentType, r.logReceiver, logsJSONUnmarshaler)
-		}).Methods(http.MethodPost).Headers("Content-Type", jsonContentType)
+		r.httpMux.HandleFunc(r.cfg.HTTP.LogsURLPath, func(resp http.ResponseWriter, req *http.Request) {
+			handleLogs(resp, req, r.logReceiver)
+		}).Methods(http.MethodPost)
 	}
 	return nil
 }
@@ -44,6 +44,7 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/internal/internalconsumertest"
 	"go.opentelemetry.io/collector/internal/testdata"
@@ -125,6 +126,11 @@ func TestJsonHttp(t *testing.T) {
 			encoding: "",
 			err:      errors.New("my error"),
 		},
+		{
+			name:     "PermanentError",
+			encoding: "",
+			err:      consumererror.Permanent(errors.New("my error")),
+		},
 		{
 			name:     "GRPCError",
 			encoding: "",
@@ -208,9 +214,12 @@ func testHTTPJSONRequest(t *testing.T, url string, sink *internalconsumertest.Er
 		if s, ok := status.FromError(expectedErr); ok {
 			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 			assert.True(t, proto.Equal(errStatus, s.Proto()))
+		} else if consumererror.IsPermanent(expectedErr) {
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.True(t, proto.Equal(errStatus, &spb.Status{Code: int32(codes.InvalidArgument), Message: expectedErr.Error()}))
 		} else {
-			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-			assert.True(t, proto.Equal(errStatus, &spb.Status{Code: int32(codes.Unknown), Message: "my error"}))
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+			assert.True(t, proto.Equal(errStatus, &spb.Status{Code: int32(codes.Unavailable), Message: "my error"}))
 		}
 		require.Len(t, allTraces, 0)
 	}
@@ -235,6 +244,11 @@ func TestProtoHttp(t *testing.T) {
 			encoding: "",
 			err:      errors.New("my error"),
 		},
+		{
+			name:     "PermanentError",
+			encoding: "",
+			err:      consumererror.Permanent(errors.New("my error")),
+		},
 		{
 			name:     "GRPCError",
 			encoding: "",
@@ -339,9 +353,12 @@ func testHTTPProtobufRequest(
 		if s, ok := status.FromError(expectedErr); ok {
 			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 			assert.True(t, proto.Equal(errStatus, s.Proto()))
+		} else if consumererror.IsPermanent(expectedErr) {
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.True(t, proto.Equal(errStatus, &spb.Status{Code: int32(codes.InvalidArgument), Message: expectedErr.Error()}))
 		} else {
-			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-			assert.True(t, proto.Equal(errStatus, &spb.Status{Code: int32(codes.Unknown), Message: "my error"}))
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+			assert.True(t, proto.Equal(errStatus, &spb.Status{Code: int32(codes.Unavailable), Message: "my error"}))
 		}
 		require.Len(t, allTraces, 0)
 	}
@@ -423,6 +440,114 @@ func TestOTLPReceiverInvalidContentEncoding(t *testing.T) {
 	}
 }
 
+func TestHTTPCustomURLPaths(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetIDName(otlpReceiverName)
+	cfg.GRPC = nil
+	cfg.HTTP.Endpoint = addr
+	cfg.HTTP.TracesURLPath = "/otlp/v1/traces"
+	tSink := new(consumertest.TracesSink)
+	ocr := newReceiver(t, factory, cfg, tSink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{path: "/otlp/v1/traces", status: http.StatusOK},
+		{path: "/v1/traces", status: http.StatusNotFound},
+		{path: "/v1/trace", status: http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			resp, err := http.Post(fmt.Sprintf("http://%s%s", addr, test.path), jsonContentType, bytes.NewReader(traceJSON))
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, test.status, resp.StatusCode)
+		})
+	}
+	assert.Equal(t, 1, tSink.SpansCount())
+}
+
+func TestHTTPContentTypes(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	ocr := newHTTPReceiver(t, addr, new(consumertest.TracesSink), nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	tests := []struct {
+		name                string
+		contentType         string
+		body                []byte
+		status              int
+		responseContentType string
+		response            proto.Message
+	}{
+		{
+			name:                "JSONWithCharset",
+			contentType:         "application/json; charset=utf-8",
+			body:                traceJSON,
+			status:              http.StatusOK,
+			responseContentType: jsonContentType,
+		},
+		{
+			name:                "InvalidJSON",
+			contentType:         jsonContentType,
+			body:                []byte("{"),
+			status:              http.StatusBadRequest,
+			responseContentType: jsonContentType,
+			response:            &spb.Status{Code: int32(codes.InvalidArgument)},
+		},
+		{
+			name:                "InvalidProtobuf",
+			contentType:         pbContentType,
+			body:                []byte{0xff},
+			status:              http.StatusBadRequest,
+			responseContentType: pbContentType,
+			response:            &spb.Status{Code: int32(codes.InvalidArgument)},
+		},
+		{
+			name:                "UnsupportedContentType",
+			contentType:         "text/plain",
+			body:                traceJSON,
+			status:              http.StatusUnsupportedMediaType,
+			responseContentType: jsonContentType,
+			response: &spb.Status{
+				Code:    int32(codes.InvalidArgument),
+				Message: `unsupported content type "text/plain", must be "application/x-protobuf" or "application/json"`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Post(fmt.Sprintf("http://%s/v1/traces", addr), test.contentType, bytes.NewReader(test.body))
+			require.NoError(t, err)
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, test.status, resp.StatusCode)
+			assert.Equal(t, test.responseContentType, resp.Header.Get("Content-Type"))
+			if test.response == nil {
+				return
+			}
+			errStatus := &spb.Status{}
+			if test.responseContentType == jsonContentType {
+				require.NoError(t, json.Unmarshal(respBytes, errStatus))
+			} else {
+				require.NoError(t, proto.Unmarshal(respBytes, errStatus))
+			}
+			assert.Equal(t, test.response.(*spb.Status).Code, errStatus.Code)
+			if msg := test.response.(*spb.Status).Message; msg != "" {
+				assert.Equal(t, msg, errStatus.Message)
+			}
+		})
+	}
+}
+
 func TestGRPCNewPortAlreadyUsed(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	ln, err := net.Listen("tcp", addr)
@@ -578,13 +703,18 @@ func TestHTTPInvalidTLSCredentials(t *testing.T) {
 	cfg := &Config{
 		ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
 		Protocols: Protocols{
-			HTTP: &confighttp.HTTPServerSettings{
-				Endpoint: testutil.GetAvailableLocalAddress(t),
-				TLSSetting: &configtls.TLSServerSetting{
-					TLSSetting: configtls.TLSSetting{
-						CertFile: "willfail",
+			HTTP: &HTTPConfig{
+				HTTPServerSettings: confighttp.HTTPServerSettings{
+					Endpoint: testutil.GetAvailableLocalAddress(t),
+					TLSSetting: &configtls.TLSServerSetting{
+						TLSSetting: configtls.TLSSetting{
+							CertFile: "willfail",
+						},
 					},
 				},
+				TracesURLPath:  defaultTracesURLPath,
+				MetricsURLPath: defaultMetricsURLPath,
+				LogsURLPath:    defaultLogsURLPath,
 			},
 		},
 	}
@@ -17,6 +17,7 @@ package otlpreceiver
 import (
 	"bytes"
 	"io/ioutil"
+	"mime"
 	"net/http"
 
 	"github.com/gogo/protobuf/jsonpb"
@@ -25,135 +26,243 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/logs"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/metrics"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/trace"
 )
 
+const (
+	pbContentType   = "application/x-protobuf"
+	jsonContentType = "application/json"
+)
+
 var jsonMarshaler = &jsonpb.Marshaler{}
 
-func handleTraces(
-	resp http.ResponseWriter,
-	req *http.Request,
-	contentType string,
-	tracesReceiver *trace.Receiver,
-	tracesUnmarshaler pdata.TracesUnmarshaler) {
-	body, ok := readAndCloseBody(resp, req, contentType)
+// encoder decodes the requests and encodes the responses of an OTLP/HTTP content type,
+// responses always use the content type of the request.
+type encoder struct {
+	contentType        string
+	tracesUnmarshaler  pdata.TracesUnmarshaler
+	metricsUnmarshaler pdata.MetricsUnmarshaler
+	logsUnmarshaler    pdata.LogsUnmarshaler
+	marshal            func(proto.Message) ([]byte, error)
+}
+
+var (
+	pbEncoder = &encoder{
+		contentType:        pbContentType,
+		tracesUnmarshaler:  otlp.NewProtobufTracesUnmarshaler(),
+		metricsUnmarshaler: otlp.NewProtobufMetricsUnmarshaler(),
+		logsUnmarshaler:    otlp.NewProtobufLogsUnmarshaler(),
+		marshal:            proto.Marshal,
+	}
+	jsonEncoder = &encoder{
+		contentType:        jsonContentType,
+		tracesUnmarshaler:  otlp.NewJSONTracesUnmarshaler(),
+		metricsUnmarshaler: otlp.NewJSONMetricsUnmarshaler(),
+		logsUnmarshaler:    otlp.NewJSONLogsUnmarshaler(),
+		marshal: func(msg proto.Message) ([]byte, error) {
+			buf := new(bytes.Buffer)
+			err := jsonMarshaler.Marshal(buf, msg)
+			return buf.Bytes(), err
+		},
+	}
+)
+
+// encoderForContentType returns the encoder of the given Content-Type header, ignoring its
+// parameters, e.g. "application/json; charset=utf-8", or nil if the content type is not supported.
+func encoderForContentType(contentType string) *encoder {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	switch mediaType {
+	case pbContentType:
+		return pbEncoder
+	case jsonContentType:
+		return jsonEncoder
+	}
+	return nil
+}
+
+// requestEncoder returns the encoder of the request content type. If it is not supported, an
+// Unsupported Media Type error is written, using the JSON encoding, and nil is returned.
+func requestEncoder(resp http.ResponseWriter, req *http.Request) *encoder {
+	enc := encoderForContentType(req.Header.Get("Content-Type"))
+	if enc == nil {
+		writeStatus(resp, jsonEncoder, http.StatusUnsupportedMediaType,
+			status.Newf(codes.InvalidArgument, "unsupported content type %q, must be %q or %q", req.Header.Get("Content-Type"), pbContentType, jsonContentType))
+	}
+	return enc
+}
+
+func handleTraces(resp http.ResponseWriter, req *http.Request, tracesReceiver *trace.Receiver) {
+	enc := requestEncoder(resp, req)
+	if enc == nil {
+		return
+	}
+	body, ok := readAndCloseBody(resp, req, enc)
 	if !ok {
 		return
 	}
 
-	td, err := tracesUnmarshaler.UnmarshalTraces(body)
+	td, err := enc.tracesUnmarshaler.UnmarshalTraces(body)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusBadRequest)
+		writeError(resp, enc, err, http.StatusBadRequest)
 		return
 	}
 
 	_, err = tracesReceiver.Export(req.Context(), td)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusInternalServerError)
+		writeExportError(resp, enc, err)
 		return
 	}
 
 	// TODO: Pass response from grpc handler when otlpgrpc returns concrete type.
-	writeResponse(resp, contentType, http.StatusOK, &types.Empty{})
+	writeResponse(resp, enc, http.StatusOK, &types.Empty{})
 }
 
-func handleMetrics(
-	resp http.ResponseWriter,
-	req *http.Request,
-	contentType string,
-	metricsReceiver *metrics.Receiver,
-	metricsUnmarshaler pdata.MetricsUnmarshaler) {
-	body, ok := readAndCloseBody(resp, req, contentType)
+func handleMetrics(resp http.ResponseWriter, req *http.Request, metricsReceiver *metrics.Receiver) {
+	enc := requestEncoder(resp, req)
+	if enc == nil {
+		return
+	}
+	body, ok := readAndCloseBody(resp, req, enc)
 	if !ok {
 		return
 	}
 
-	md, err := metricsUnmarshaler.UnmarshalMetrics(body)
+	md, err := enc.metricsUnmarshaler.UnmarshalMetrics(body)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusBadRequest)
+		writeError(resp, enc, err, http.StatusBadRequest)
 		return
 	}
 
 	_, err = metricsReceiver.Export(req.Context(), md)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusInternalServerError)
+		writeExportError(resp, enc, err)
 		return
 	}
 
 	// TODO: Pass response from grpc handler when otlpgrpc returns concrete type.
-	writeResponse(resp, contentType, http.StatusOK, &types.Empty{})
+	writeResponse(resp, enc, http.StatusOK, &types.Empty{})
 }
 
-func handleLogs(
-	resp http.ResponseWriter,
-	req *http.Request,
-	contentType string,
-	logsReceiver *logs.Receiver,
-	logsUnmarshaler pdata.LogsUnmarshaler) {
-	body, ok := readAndCloseBody(resp, req, contentType)
+func handleLogs(resp http.ResponseWriter, req *http.Request, logsReceiver *logs.Receiver) {
+	enc := requestEncoder(resp, req)
+	if enc == nil {
+		return
+	}
+	body, ok := readAndCloseBody(resp, req, enc)
 	if !ok {
 		return
 	}
 
-	ld, err := logsUnmarshaler.UnmarshalLogs(body)
+	ld, err := enc.logsUnmarshaler.UnmarshalLogs(body)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusBadRequest)
+		writeError(resp, enc, err, http.StatusBadRequest)
 		return
 	}
 
 	_, err = logsReceiver.Export(req.Context(), ld)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusInternalServerError)
+		writeExportError(resp, enc, err)
 		return
 	}
 
 	// TODO: Pass response from grpc handler when otlpgrpc returns concrete type.
-	writeResponse(resp, contentType, http.StatusOK, &types.Empty{})
+	writeResponse(resp, enc, http.StatusOK, &types.Empty{})
 }
 
-func readAndCloseBody(resp http.ResponseWriter, req *http.Request, contentType string) ([]byte, bool) {
+func readAndCloseBody(resp http.ResponseWriter, req *http.Request, enc *encoder) ([]byte, bool) {
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusBadRequest)
+		writeError(resp, enc, err, http.StatusBadRequest)
 		return nil, false
 	}
 	if err = req.Body.Close(); err != nil {
-		writeError(resp, contentType, err, http.StatusBadRequest)
+		writeError(resp, enc, err, http.StatusBadRequest)
 		return nil, false
 	}
 	return body, true
 }
 
+// writeExportError writes the error returned by the pipeline with a status telling the client
+// whether the request can be retried, as defined by the OTLP/HTTP specification: permanent errors,
+// see consumererror.Permanent, are reported as Bad Request, and the others as Service Unavailable.
+func writeExportError(w http.ResponseWriter, enc *encoder, err error) {
+	if consumererror.IsPermanent(err) {
+		writeStatus(w, enc, http.StatusBadRequest, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+	if s, ok := status.FromError(err); ok {
+		writeStatus(w, enc, httpStatusFromCode(s.Code()), s)
+		return
+	}
+	writeStatus(w, enc, http.StatusServiceUnavailable, status.New(codes.Unavailable, err.Error()))
+}
+
 // writeError encodes the HTTP error inside a rpc.Status message as required by the OTLP protocol.
-func writeError(w http.ResponseWriter, contentType string, err error, statusCode int) {
+func writeError(w http.ResponseWriter, enc *encoder, err error, statusCode int) {
 	s, ok := status.FromError(err)
-	if ok {
-		writeResponse(w, contentType, statusCode, s.Proto())
-	} else {
-		writeErrorMsg(w, contentType, err.Error(), statusCode)
+	if !ok {
+		s = status.New(codeFromHTTPStatus(statusCode), err.Error())
 	}
+	writeStatus(w, enc, statusCode, s)
 }
 
-// writeErrorMsg encodes the HTTP error message inside a rpc.Status message as required
+// errorHandler encodes the HTTP error message inside a rpc.Status message as required
 // by the OTLP protocol.
-func writeErrorMsg(w http.ResponseWriter, contentType string, errMsg string, statusCode int) {
-	var s *status.Status
-	if statusCode == http.StatusBadRequest {
-		s = status.New(codes.InvalidArgument, errMsg)
-	} else {
-		s = status.New(codes.Unknown, errMsg)
+func errorHandler(w http.ResponseWriter, r *http.Request, errMsg string, statusCode int) {
+	enc := encoderForContentType(r.Header.Get("Content-Type"))
+	if enc == nil {
+		enc = jsonEncoder
 	}
+	writeStatus(w, enc, statusCode, status.New(codeFromHTTPStatus(statusCode), errMsg))
+}
 
-	writeResponse(w, contentType, statusCode, s.Proto())
+// codeFromHTTPStatus returns the gRPC code of the rpc.Status sent with the given HTTP status code.
+func codeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+	return codes.Unknown
 }
 
-// errorHandler encodes the HTTP error message inside a rpc.Status message as required
-// by the OTLP protocol.
-func errorHandler(w http.ResponseWriter, r *http.Request, errMsg string, statusCode int) {
-	writeErrorMsg(w, r.Header.Get("Content-Type"), errMsg, statusCode)
+// httpStatusFromCode returns the HTTP status code of the errors with the given gRPC code. Only
+// 429, 502, 503 and 504 are retried by the clients, as defined by the OTLP/HTTP specification.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable, codes.Aborted, codes.Canceled:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+func writeStatus(w http.ResponseWriter, enc *encoder, statusCode int, s *status.Status) {
+	writeResponse(w, enc, statusCode, s.Proto())
 }
 
 // Pre-computed status with code=Internal to be used in case of a marshaling error.
@@ -161,17 +270,9 @@ var fallbackMsg = []byte(`{"code": 13, "message": "failed to marshal error messa
 
 const fallbackContentType = "application/json"
 
-func writeResponse(w http.ResponseWriter, contentType string, statusCode int, rsp proto.Message) {
-	var err error
-	var msg []byte
-	if contentType == "application/json" {
-		buf := new(bytes.Buffer)
-		err = jsonMarshaler.Marshal(buf, rsp)
-		msg = buf.Bytes()
-	} else {
-		msg, err = proto.Marshal(rsp)
-	}
-
+func writeResponse(w http.ResponseWriter, enc *encoder, statusCode int, rsp proto.Message) {
+	contentType := enc.contentType
+	msg, err := enc.marshal(rsp)
 	if err != nil {
 		msg = fallbackMsg
 		contentType = fallbackContentType
@@ -40,6 +40,19 @@ type Config struct {
 	UseStartTimeMetric      bool                     `mapstructure:"use_start_time_metric"`
 	StartTimeMetricRegex    string                   `mapstructure:"start_time_metric_regex"`
 
+	// ReportStalenessMarkers exports the Prometheus staleness markers, with the stale NaN value, for the series
+	// missing from a scrape and the targets that disappear. They are dropped by default, as the consumers that
+	// don't know about them would take them as regular NaN values.
+	ReportStalenessMarkers bool `mapstructure:"report_staleness_markers"`
+
+	// ConfigFile is the path of a Prometheus configuration file to read the scrape configs from,
+	// instead of the inline "config". The file is watched, and its scrape configs re-applied
+	// without restarting the receiver when it changes.
+	ConfigFile string `mapstructure:"config_file"`
+
+	// ConfigReloadInterval is the interval at which ConfigFile is checked for changes.
+	ConfigReloadInterval time.Duration `mapstructure:"config_reload_interval"`
+
 	// ConfigPlaceholder is just an entry to make the configuration pass a check
 	// that requires that all keys present in the config actually exist on the
 	// structure, ie.: it will error if an unknown key is present.
@@ -51,16 +64,30 @@ var _ config.CustomUnmarshable = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
-	if cfg.PrometheusConfig != nil {
-		if len(cfg.PrometheusConfig.ScrapeConfigs) == 0 {
-			return errNilScrapeConfig
+	if cfg.ConfigFile != "" {
+		if cfg.PrometheusConfig != nil {
+			return errConfigAndConfigFile
+		}
+		if cfg.ConfigReloadInterval <= 0 {
+			return errNonPositiveReloadInterval
 		}
-		for _, sc := range cfg.PrometheusConfig.ScrapeConfigs {
-			for _, rc := range sc.MetricRelabelConfigs {
-				if rc.TargetLabel == "__name__" {
-					// TODO(#2297): Remove validation after renaming is fixed
-					return fmt.Errorf("error validating scrapeconfig for job %v: %w", sc.JobName, errRenamingDisallowed)
-				}
+	}
+	if cfg.PrometheusConfig != nil {
+		return validatePromConfig(cfg.PrometheusConfig)
+	}
+	return nil
+}
+
+// validatePromConfig checks the given Prometheus configuration is supported by the receiver.
+func validatePromConfig(promCfg *promconfig.Config) error {
+	if len(promCfg.ScrapeConfigs) == 0 {
+		return errNilScrapeConfig
+	}
+	for _, sc := range promCfg.ScrapeConfigs {
+		for _, rc := range sc.MetricRelabelConfigs {
+			if rc.TargetLabel == "__name__" {
+				// TODO(#2297): Remove validation after renaming is fixed
+				return fmt.Errorf("error validating scrapeconfig for job %v: %w", sc.JobName, errRenamingDisallowed)
 			}
 		}
 	}
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery/kubernetes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,7 +41,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 2)
+	assert.Equal(t, len(cfg.Receivers), 3)
 
 	r0 := cfg.Receivers[config.NewID(typeStr)]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -51,6 +52,25 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, time.Duration(r1.PrometheusConfig.ScrapeConfigs[0].ScrapeInterval), 5*time.Second)
 	assert.Equal(t, r1.UseStartTimeMetric, true)
 	assert.Equal(t, r1.StartTimeMetricRegex, "^(.+_)*process_start_time_seconds$")
+	assert.True(t, r1.ReportStalenessMarkers)
+
+	r2 := cfg.Receivers[config.NewIDWithName(typeStr, "config_file")].(*Config)
+	assert.Nil(t, r2.PrometheusConfig)
+	assert.Equal(t, "./testdata/scrape_configs.yaml", r2.ConfigFile)
+	assert.Equal(t, 10*time.Second, r2.ConfigReloadInterval)
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ConfigFile = "./testdata/scrape_configs.yaml"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ConfigReloadInterval = 0
+	assert.Equal(t, errNonPositiveReloadInterval, cfg.Validate())
+
+	cfg.ConfigReloadInterval = time.Second
+	cfg.PrometheusConfig = &promconfig.Config{}
+	assert.Equal(t, errConfigAndConfigFile, cfg.Validate())
 }
 
 func TestLoadConfigWithEnvVar(t *testing.T) {
@@ -17,6 +17,7 @@ package prometheusreceiver
 import (
 	"context"
 	"errors"
+	"time"
 
 	_ "github.com/prometheus/prometheus/discovery/install" // init() of this package registers service discovery impl.
 
@@ -30,11 +31,16 @@ import (
 
 const (
 	typeStr = "prometheus"
+
+	defaultConfigReloadInterval = 30 * time.Second
 )
 
 var (
 	errNilScrapeConfig    = errors.New("expecting a non-nil ScrapeConfig")
 	errRenamingDisallowed = errors.New("metric renaming using metric_relabel_configs is disallowed")
+
+	errConfigAndConfigFile       = errors.New("only one of config and config_file can be set")
+	errNonPositiveReloadInterval = errors.New("config_reload_interval must be positive")
 )
 
 // NewFactory creates a new Prometheus receiver factory.
@@ -47,7 +53,8 @@ func NewFactory() component.ReceiverFactory {
 
 func createDefaultConfig() config.Receiver {
 	return &Config{
-		ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
+		ReceiverSettings:     config.NewReceiverSettings(config.NewID(typeStr)),
+		ConfigReloadInterval: defaultConfigReloadInterval,
 	}
 }
 
@@ -17,6 +17,7 @@ package internal
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
 
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
@@ -35,39 +36,124 @@ type ScrapeManager interface {
 
 type metadataService struct {
 	sync.Mutex
-	stopped bool
+	// stopped is set, atomically and with the lock held, once closed.
+	stopped int32
 	sm      ScrapeManager
+
+	// targets caches the targets by job and instance, so that the targets already known are found
+	// without calling scrapeManager.TargetsAll(). The scrape manager lock is held while its scrape
+	// configs are applied at runtime, until the in-flight scrapes completed.
+	targetsMu sync.RWMutex
+	targets   map[string]map[string]*scrape.Target
+	// removedTargets are the cached targets missing from the last call to scrapeManager.TargetsAll(), kept
+	// until the next one for the staleness markers appended once a target disappeared.
+	removedTargets map[string]map[string]*scrape.Target
+	// invalidated is set when the scrape configs are reloaded, so that the cached targets are refreshed
+	// by the next call to Get.
+	invalidated bool
 }
 
 func (s *metadataService) Close() {
 	s.Lock()
-	s.stopped = true
+	atomic.StoreInt32(&s.stopped, 1)
 	s.Unlock()
 }
 
+// Invalidate discards the cached targets, as the scrape configs were reloaded. The targets removed by
+// the reload are still found by the next calls, for the staleness markers of their series.
+func (s *metadataService) Invalidate() {
+	s.targetsMu.Lock()
+	s.invalidated = true
+	s.targetsMu.Unlock()
+}
+
 func (s *metadataService) Get(job, instance string) (MetadataCache, error) {
+	// Nothing is returned once stopped, even the cached targets.
+	if atomic.LoadInt32(&s.stopped) != 0 {
+		return nil, errAlreadyStopped
+	}
+	if target, ok := s.cachedTarget(job, instance); ok {
+		return &mCache{target}, nil
+	}
+
 	s.Lock()
 	defer s.Unlock()
 
 	// If we're already stopped return early so that we don't call scrapeManager.TargetsAll()
 	// which will result in deadlock if scrapeManager is being stopped.
-	if s.stopped {
+	if atomic.LoadInt32(&s.stopped) != 0 {
 		return nil, errAlreadyStopped
 	}
 
-	targetGroup, ok := s.sm.TargetsAll()[job]
-	if !ok {
+	targets := s.refreshTargets()
+	if target, ok := s.target(job, instance); ok {
+		return &mCache{target}, nil
+	}
+	if _, ok := targets[job]; !ok {
 		return nil, errors.New("unable to find a target group with job=" + job)
 	}
 
-	// from the same targetGroup, instance is not going to be duplicated
-	for _, target := range targetGroup {
-		if target.Labels().Get(model.InstanceLabel) == instance {
-			return &mCache{target}, nil
+	return nil, errors.New("unable to find a target with job=" + job + ", and instance=" + instance)
+}
+
+// refreshTargets caches the targets of the scrape manager, and returns them by job and instance.
+func (s *metadataService) refreshTargets() map[string]map[string]*scrape.Target {
+	allTargets := s.sm.TargetsAll()
+	targets := make(map[string]map[string]*scrape.Target, len(allTargets))
+	for j, targetGroup := range allTargets {
+		// from the same targetGroup, instance is not going to be duplicated
+		byInstance := make(map[string]*scrape.Target, len(targetGroup))
+		for _, target := range targetGroup {
+			if i := target.Labels().Get(model.InstanceLabel); i != "" {
+				byInstance[i] = target
+			}
 		}
+		targets[j] = byInstance
 	}
+	s.targetsMu.Lock()
+	removedTargets := make(map[string]map[string]*scrape.Target)
+	for j, byInstance := range s.targets {
+		for i, target := range byInstance {
+			if _, ok := targets[j][i]; ok {
+				continue
+			}
+			if removedTargets[j] == nil {
+				removedTargets[j] = make(map[string]*scrape.Target)
+			}
+			removedTargets[j][i] = target
+		}
+	}
+	s.targets = targets
+	s.removedTargets = removedTargets
+	s.invalidated = false
+	s.targetsMu.Unlock()
+	return targets
+}
 
-	return nil, errors.New("unable to find a target with job=" + job + ", and instance=" + instance)
+// cachedTarget returns the cached target of the job and instance, unless the cache was invalidated.
+func (s *metadataService) cachedTarget(job, instance string) (*scrape.Target, bool) {
+	s.targetsMu.RLock()
+	defer s.targetsMu.RUnlock()
+	if s.invalidated {
+		return nil, false
+	}
+	return s.lookupTarget(job, instance)
+}
+
+// target returns the target of the job and instance, from the last refreshed targets.
+func (s *metadataService) target(job, instance string) (*scrape.Target, bool) {
+	s.targetsMu.RLock()
+	defer s.targetsMu.RUnlock()
+	return s.lookupTarget(job, instance)
+}
+
+// lookupTarget returns the target of the job and instance, targetsMu must be held.
+func (s *metadataService) lookupTarget(job, instance string) (*scrape.Target, bool) {
+	if target, ok := s.targets[job][instance]; ok {
+		return target, true
+	}
+	target, ok := s.removedTargets[job][instance]
+	return target, ok
 }
 
 // adapter to get metadata from scrape.Target
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedScrapeManager is a ScrapeManager whose TargetsAll blocks while it is locked, like
+// scrape.Manager while it applies a configuration.
+type lockedScrapeManager struct {
+	sync.Mutex
+	mockScrapeManager
+	calls int
+}
+
+func (sm *lockedScrapeManager) TargetsAll() map[string][]*scrape.Target {
+	sm.Lock()
+	defer sm.Unlock()
+	sm.calls++
+	return sm.mockScrapeManager.TargetsAll()
+}
+
+func TestMetadataServiceCachesTargets(t *testing.T) {
+	target := scrape.NewTarget(labels.FromStrings(model.InstanceLabel, "localhost:8080"), labels.FromStrings(model.SchemeLabel, "http"), nil)
+	sm := &lockedScrapeManager{mockScrapeManager: mockScrapeManager{targets: map[string][]*scrape.Target{"test": {target}}}}
+	ms := &metadataService{sm: sm}
+
+	mc, err := ms.Get("test", "localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, "http", mc.SharedLabels().Get(model.SchemeLabel))
+	assert.Equal(t, 1, sm.calls)
+
+	// The known targets are found while the scrape manager is locked.
+	sm.Lock()
+	mc, err = ms.Get("test", "localhost:8080")
+	sm.Unlock()
+	require.NoError(t, err)
+	assert.Equal(t, "http", mc.SharedLabels().Get(model.SchemeLabel))
+	assert.Equal(t, 1, sm.calls)
+
+	// The unknown targets are looked up again.
+	_, err = ms.Get("test", "localhost:9090")
+	assert.Error(t, err)
+	_, err = ms.Get("test2", "localhost:8080")
+	assert.Error(t, err)
+	assert.Equal(t, 3, sm.calls)
+
+	ms.Close()
+	_, err = ms.Get("test2", "localhost:8080")
+	assert.Equal(t, errAlreadyStopped, err)
+	// The cached targets aren't returned either once stopped.
+	_, err = ms.Get("test", "localhost:8080")
+	assert.Equal(t, errAlreadyStopped, err)
+	assert.Equal(t, 3, sm.calls)
+}
+
+func TestMetadataServiceInvalidate(t *testing.T) {
+	target := scrape.NewTarget(labels.FromStrings(model.InstanceLabel, "localhost:8080"), labels.FromStrings(model.SchemeLabel, "http"), nil)
+	removed := scrape.NewTarget(labels.FromStrings(model.InstanceLabel, "localhost:9090"), labels.FromStrings(model.SchemeLabel, "http"), nil)
+	sm := &lockedScrapeManager{mockScrapeManager: mockScrapeManager{targets: map[string][]*scrape.Target{"test": {target, removed}}}}
+	ms := &metadataService{sm: sm}
+
+	_, err := ms.Get("test", "localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, 1, sm.calls)
+
+	// The scrape configs are reloaded, with new targets.
+	reloaded := scrape.NewTarget(labels.FromStrings(model.InstanceLabel, "localhost:8080"), labels.FromStrings(model.SchemeLabel, "https"), nil)
+	sm.Lock()
+	sm.targets = map[string][]*scrape.Target{"test": {reloaded}}
+	sm.Unlock()
+	ms.Invalidate()
+
+	mc, err := ms.Get("test", "localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, "https", mc.SharedLabels().Get(model.SchemeLabel))
+	assert.Equal(t, 2, sm.calls)
+
+	// The removed targets are still found, and the cache is used again.
+	mc, err = ms.Get("test", "localhost:9090")
+	require.NoError(t, err)
+	assert.Equal(t, "http", mc.SharedLabels().Get(model.SchemeLabel))
+	_, err = ms.Get("test", "localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, 2, sm.calls)
+}
@@ -16,12 +16,14 @@ package internal
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/scrape"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -191,6 +193,13 @@ func (mf *metricFamily) Add(metricName string, ls labels.Labels, t int64, v floa
 	case metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION:
 		fallthrough
 	case metricspb.MetricDescriptor_SUMMARY:
+		// The group has no recorded value only if all its series are stale, e.g. when the target disappeared.
+		// The stale series of a group that has other values, e.g. removed buckets, are ignored.
+		if value.IsStaleNaN(v) {
+			mg.stale = !mg.hasSum && !mg.hasCount && len(mg.complexValue) == 0
+			return nil
+		}
+		mg.stale = false
 		switch {
 		case strings.HasSuffix(metricName, metricsSuffixSum):
 			// always use the timestamp from sum (count is ok too), because the startTs from quantiles won't be reliable
@@ -211,6 +220,7 @@ func (mf *metricFamily) Add(metricName string, ls labels.Labels, t int64, v floa
 		}
 	default:
 		mg.value = v
+		mg.stale = value.IsStaleNaN(v)
 	}
 
 	return nil
@@ -270,6 +280,14 @@ func (mf *metricFamily) ToMetric() (*metricspb.Metric, int, int) {
 	return nil, mf.droppedTimeseries, mf.droppedTimeseries
 }
 
+// staleNaN is the value of the Prometheus staleness markers, appended when a series disappears from a
+// target, or when the target itself disappears or fails to be scraped.
+//
+// The data points of this version of OTLP have no flags, so the data points without a recorded value
+// are emitted with this value: as the value of gauges and sums, and as the sum of histograms and
+// summaries, that have no count, buckets or quantiles.
+var staleNaN = math.Float64frombits(value.StaleNaN)
+
 type dataPoint struct {
 	value    float64
 	boundary float64
@@ -288,6 +306,8 @@ type metricGroup struct {
 	hasSum       bool
 	value        float64
 	complexValue []*dataPoint
+	// stale is true if only staleness markers were added to the group, see staleNaN.
+	stale bool
 }
 
 func (mg *metricGroup) sortPoints() {
@@ -297,6 +317,17 @@ func (mg *metricGroup) sortPoints() {
 }
 
 func (mg *metricGroup) toDistributionTimeSeries(orderedLabelKeys []string) *metricspb.TimeSeries {
+	if mg.stale {
+		// The staleness marker is a distribution without count nor buckets, and a stale NaN sum.
+		return &metricspb.TimeSeries{
+			StartTimestamp: timestampFromMs(mg.ts),
+			LabelValues:    populateLabelValues(orderedLabelKeys, mg.ls),
+			Points: []*metricspb.Point{{
+				Timestamp: timestampFromMs(mg.ts),
+				Value:     &metricspb.Point_DistributionValue{DistributionValue: &metricspb.DistributionValue{Sum: staleNaN}},
+			}},
+		}
+	}
 	if !(mg.hasCount) || len(mg.complexValue) == 0 {
 		return nil
 	}
@@ -344,6 +375,20 @@ func (mg *metricGroup) toDistributionTimeSeries(orderedLabelKeys []string) *metr
 }
 
 func (mg *metricGroup) toSummaryTimeSeries(orderedLabelKeys []string) *metricspb.TimeSeries {
+	if mg.stale {
+		// The staleness marker is a summary without count nor quantiles, and a stale NaN sum.
+		return &metricspb.TimeSeries{
+			StartTimestamp: timestampFromMs(mg.ts),
+			LabelValues:    populateLabelValues(orderedLabelKeys, mg.ls),
+			Points: []*metricspb.Point{{
+				Timestamp: timestampFromMs(mg.ts),
+				Value: &metricspb.Point_SummaryValue{SummaryValue: &metricspb.SummaryValue{
+					Sum:   &wrapperspb.DoubleValue{Value: staleNaN},
+					Count: &wrapperspb.Int64Value{},
+				}},
+			}},
+		}
+	}
 	// expecting count to be provided, however, in the following two cases, they can be missed.
 	// 1. data is corrupted
 	// 2. ignored by startValue evaluation
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/prometheus/prometheus/pkg/value"
 	"go.uber.org/zap"
 )
 
@@ -240,6 +241,15 @@ func (ma *MetricsAdjuster) adjustMetricTimeseries(metric *metricspb.Metric) int
 	filtered := make([]*metricspb.TimeSeries, 0, len(metric.GetTimeseries()))
 	for _, current := range metric.GetTimeseries() {
 		tsi := ma.tsm.get(metric, current.GetLabelValues())
+		if isStaleTimeseries(metric.MetricDescriptor.Type, current) {
+			// The staleness marker has no value to adjust from, and is not the previous point of the next
+			// one, which is still adjusted from the initial point if the timeseries comes back without reset.
+			if tsi.initial != nil {
+				current.StartTimestamp = tsi.initial.StartTimestamp
+			}
+			filtered = append(filtered, current)
+			continue
+		}
 		if tsi.initial == nil || !ma.adjustTimeseries(metric.MetricDescriptor.Type, current, tsi.initial, tsi.previous) {
 			// initial || reset timeseries
 			tsi.initial = current
@@ -252,6 +262,22 @@ func (ma *MetricsAdjuster) adjustMetricTimeseries(metric *metricspb.Metric) int
 	return resets
 }
 
+// isStaleTimeseries returns whether the point of the timeseries is a staleness marker, see staleNaN.
+func isStaleTimeseries(metricType metricspb.MetricDescriptor_Type, ts *metricspb.TimeSeries) bool {
+	points := ts.GetPoints()
+	if len(points) != 1 {
+		return false
+	}
+	switch metricType {
+	case metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION:
+		return value.IsStaleNaN(points[0].GetDistributionValue().GetSum())
+	case metricspb.MetricDescriptor_SUMMARY:
+		return value.IsStaleNaN(points[0].GetSummaryValue().GetSum().GetValue())
+	default:
+		return value.IsStaleNaN(points[0].GetDoubleValue())
+	}
+}
+
 // Returns true if 'current' was adjusted and false if 'current' is an the initial occurrence or a
 // reset of the timeseries.
 func (ma *MetricsAdjuster) adjustTimeseries(metricType metricspb.MetricDescriptor_Type,
@@ -15,13 +15,16 @@
 package internal
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	mtu "go.opentelemetry.io/collector/testutil/metricstestutil"
 )
@@ -91,6 +94,25 @@ func Test_cumulative(t *testing.T) {
 	runScript(t, NewJobsMap(time.Minute).get("job", "0"), script)
 }
 
+func Test_cumulativeStale(t *testing.T) {
+	staleNaN := math.Float64frombits(value.StaleNaN)
+	ma := NewMetricsAdjuster(NewJobsMap(time.Minute).get("job", "0"), zap.NewNop())
+
+	_, resets := ma.AdjustMetrics([]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t1Ms, v1v2, mtu.Double(t1Ms, 44)))})
+	assert.Equal(t, 1, resets)
+
+	// The staleness marker is adjusted, but the next point is adjusted from the point before it.
+	adjusted, resets := ma.AdjustMetrics([]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t2Ms, v1v2, mtu.Double(t2Ms, staleNaN)))})
+	assert.Equal(t, 0, resets)
+	require.Len(t, adjusted, 1)
+	assert.Equal(t, timestamppb.New(t1Ms), adjusted[0].Timeseries[0].StartTimestamp)
+	assert.True(t, value.IsStaleNaN(adjusted[0].Timeseries[0].Points[0].GetDoubleValue()))
+
+	adjusted, resets = ma.AdjustMetrics([]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t3Ms, v1v2, mtu.Double(t3Ms, 66)))})
+	assert.Equal(t, 0, resets)
+	assert.EqualValues(t, []*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t1Ms, v1v2, mtu.Double(t3Ms, 66)))}, adjusted)
+}
+
 func Test_cumulativeDistribution(t *testing.T) {
 	script := []*metricsAdjusterTest{{
 		"CumulativeDist: round 1 - initial instance, start time is established",
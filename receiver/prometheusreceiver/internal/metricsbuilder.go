@@ -26,6 +26,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/pkg/value"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -48,6 +49,7 @@ var (
 type metricBuilder struct {
 	hasData              bool
 	hasInternalMetric    bool
+	hasRecordedValue     bool
 	mc                   MetadataCache
 	metrics              []*metricspb.Metric
 	numTimeseries        int
@@ -106,6 +108,7 @@ func (b *metricBuilder) AddDataPoint(ls labels.Labels, t int64, v float64) error
 	}
 
 	metricName := ls.Get(model.MetricNameLabel)
+	stale := value.IsStaleNaN(v)
 	switch {
 	case metricName == "":
 		b.numTimeseries++
@@ -115,8 +118,9 @@ func (b *metricBuilder) AddDataPoint(ls labels.Labels, t int64, v float64) error
 		b.hasInternalMetric = true
 		lm := ls.Map()
 		// See https://www.prometheus.io/docs/concepts/jobs_instances/#automatically-generated-labels-and-time-series
-		// up: 1 if the instance is healthy, i.e. reachable, or 0 if the scrape failed.
-		if metricName == scrapeUpMetricName && v != 1.0 {
+		// up: 1 if the instance is healthy, i.e. reachable, or 0 if the scrape failed. up is stale once the target
+		// disappeared.
+		if metricName == scrapeUpMetricName && v != 1.0 && !stale {
 			if v == 0.0 {
 				b.logger.Warn("Failed to scrape Prometheus endpoint",
 					zap.Int64("scrape_timestamp", t),
@@ -128,11 +132,14 @@ func (b *metricBuilder) AddDataPoint(ls labels.Labels, t int64, v float64) error
 					zap.String("target_labels", fmt.Sprintf("%v", lm)))
 			}
 		}
-	case b.useStartTimeMetric && b.matchStartTimeMetric(metricName):
+	case b.useStartTimeMetric && b.matchStartTimeMetric(metricName) && !stale:
 		b.startTime = v
 	}
 
 	b.hasData = true
+	if !stale {
+		b.hasRecordedValue = true
+	}
 
 	if b.currentMf != nil && !b.currentMf.IsSameFamily(metricName) {
 		m, ts, dts := b.currentMf.ToMetric()
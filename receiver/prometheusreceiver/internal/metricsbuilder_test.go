@@ -15,6 +15,7 @@
 package internal
 
 import (
+	"math"
 	"reflect"
 	"runtime"
 	"testing"
@@ -23,6 +24,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/scrape"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1467,3 +1469,64 @@ func TestMetricBuilderDuplicateLabelKeysAreRejected(t *testing.T) {
 	require.NotNil(t, err)
 	require.Contains(t, err.Error(), `invalid sample: non-unique label names: ["a" "z"]`)
 }
+
+func Test_metricBuilder_staleness(t *testing.T) {
+	staleNaN := math.Float64frombits(value.StaleNaN)
+	mc := newMockMetadataCache(testMetadata)
+	b := newMetricBuilder(mc, true, "", testLogger)
+	for _, pt := range []*testDataPoint{
+		createDataPoint("counter_test", staleNaN, "foo", "bar"),
+		createDataPoint("gauge_test", staleNaN, "foo", "bar"),
+		createDataPoint("hist_test", staleNaN, "foo", "bar", "le", "10"),
+		createDataPoint("hist_test", staleNaN, "foo", "bar", "le", "+inf"),
+		createDataPoint("hist_test_sum", staleNaN, "foo", "bar"),
+		createDataPoint("hist_test_count", staleNaN, "foo", "bar"),
+		// The stale buckets of a histogram with recorded values are ignored.
+		createDataPoint("hist_test2", 5, "foo", "bar", "le", "10"),
+		createDataPoint("hist_test2", staleNaN, "foo", "bar", "le", "20"),
+		createDataPoint("hist_test2", 10, "foo", "bar", "le", "+inf"),
+		createDataPoint("hist_test2_sum", 99, "foo", "bar"),
+		createDataPoint("hist_test2_count", 10, "foo", "bar"),
+		createDataPoint("summary_test", staleNaN, "foo", "bar", "quantile", "0.5"),
+		createDataPoint("summary_test_sum", staleNaN, "foo", "bar"),
+		createDataPoint("summary_test_count", staleNaN, "foo", "bar"),
+		createDataPoint("up", staleNaN),
+	} {
+		require.NoError(t, b.AddDataPoint(pt.lb, startTs, pt.v))
+	}
+	metrics, _, _, err := b.Build()
+	require.NoError(t, err)
+	assert.True(t, b.hasRecordedValue, "hist_test2 has recorded values")
+	require.Len(t, metrics, 6)
+
+	point := func(i int) *metricspb.Point {
+		require.Len(t, metrics[i].Timeseries, 1)
+		require.Len(t, metrics[i].Timeseries[0].Points, 1)
+		return metrics[i].Timeseries[0].Points[0]
+	}
+	assert.Equal(t, "counter_test", metrics[0].MetricDescriptor.Name)
+	assert.True(t, value.IsStaleNaN(point(0).GetDoubleValue()))
+	assert.Equal(t, "gauge_test", metrics[1].MetricDescriptor.Name)
+	assert.True(t, value.IsStaleNaN(point(1).GetDoubleValue()))
+
+	assert.Equal(t, "hist_test", metrics[2].MetricDescriptor.Name)
+	hist := point(2).GetDistributionValue()
+	assert.True(t, value.IsStaleNaN(hist.Sum))
+	assert.Zero(t, hist.Count)
+	assert.Empty(t, hist.Buckets)
+
+	assert.Equal(t, "hist_test2", metrics[3].MetricDescriptor.Name)
+	hist = point(3).GetDistributionValue()
+	assert.Equal(t, 99.0, hist.Sum)
+	assert.Equal(t, int64(10), hist.Count)
+	assert.Equal(t, []float64{10}, hist.BucketOptions.GetExplicit().Bounds)
+
+	assert.Equal(t, "summary_test", metrics[4].MetricDescriptor.Name)
+	summary := point(4).GetSummaryValue()
+	assert.True(t, value.IsStaleNaN(summary.Sum.Value))
+	assert.Zero(t, summary.Count.Value)
+	assert.Nil(t, summary.Snapshot)
+
+	assert.Equal(t, "up", metrics[5].MetricDescriptor.Name)
+	assert.True(t, value.IsStaleNaN(point(5).GetDoubleValue()))
+}
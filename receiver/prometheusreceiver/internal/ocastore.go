@@ -48,6 +48,7 @@ type OcaStore struct {
 	jobsMap              *JobsMap
 	useStartTimeMetric   bool
 	startTimeMetricRegex string
+	reportStaleness      bool
 	receiverID           config.ComponentID
 	externalLabels       labels.Labels
 
@@ -62,6 +63,7 @@ func NewOcaStore(
 	jobsMap *JobsMap,
 	useStartTimeMetric bool,
 	startTimeMetricRegex string,
+	reportStaleness bool,
 	receiverID config.ComponentID,
 	externalLabels labels.Labels) *OcaStore {
 	return &OcaStore{
@@ -72,6 +74,7 @@ func NewOcaStore(
 		jobsMap:              jobsMap,
 		useStartTimeMetric:   useStartTimeMetric,
 		startTimeMetricRegex: startTimeMetricRegex,
+		reportStaleness:      reportStaleness,
 		receiverID:           receiverID,
 		externalLabels:       externalLabels,
 	}
@@ -93,6 +96,7 @@ func (o *OcaStore) Appender(context.Context) storage.Appender {
 			o.jobsMap,
 			o.useStartTimeMetric,
 			o.startTimeMetricRegex,
+			o.reportStaleness,
 			o.receiverID,
 			o.mc,
 			o.sink,
@@ -106,6 +110,14 @@ func (o *OcaStore) Appender(context.Context) storage.Appender {
 	return noop
 }
 
+// InvalidateTargets discards the targets cached by the internal metadataService, once the scrape configs
+// of the scrape manager are reloaded.
+func (o *OcaStore) InvalidateTargets() {
+	if o.mc != nil {
+		o.mc.Invalidate()
+	}
+}
+
 // Close OcaStore as well as the internal metadataService.
 func (o *OcaStore) Close() {
 	if atomic.CompareAndSwapInt32(&o.running, runningStateReady, runningStateStop) {
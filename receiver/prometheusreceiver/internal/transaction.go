@@ -27,6 +27,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/exemplar"
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/storage"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -68,6 +69,7 @@ type transaction struct {
 	jobsMap              *JobsMap
 	useStartTimeMetric   bool
 	startTimeMetricRegex string
+	reportStaleness      bool
 	receiverID           config.ComponentID
 	ms                   *metadataService
 	node                 *commonpb.Node
@@ -83,6 +85,7 @@ func newTransaction(
 	jobsMap *JobsMap,
 	useStartTimeMetric bool,
 	startTimeMetricRegex string,
+	reportStaleness bool,
 	receiverID config.ComponentID,
 	ms *metadataService,
 	sink consumer.Metrics,
@@ -96,6 +99,7 @@ func newTransaction(
 		jobsMap:              jobsMap,
 		useStartTimeMetric:   useStartTimeMetric,
 		startTimeMetricRegex: startTimeMetricRegex,
+		reportStaleness:      reportStaleness,
 		receiverID:           receiverID,
 		ms:                   ms,
 		externalLabels:       externalLabels,
@@ -110,10 +114,11 @@ var _ storage.Appender = (*transaction)(nil)
 // Append always returns 0 to disable label caching.
 func (tr *transaction) Append(ref uint64, ls labels.Labels, t int64, v float64) (uint64, error) {
 	// Important, must handle. prometheus will still try to feed the appender some data even if it failed to
-	// scrape the remote target,  if the previous scrape was success and some data were cached internally
-	// in our case, we don't need these data, simply drop them shall be good enough. more details:
+	// scrape the remote target, if the previous scrape was success and some data were cached internally.
+	// These are staleness markers, telling that the series has no recorded value anymore, which are kept
+	// for the consumers to end the series when reportStaleness is set. Other NaN values are dropped. more details:
 	// https://github.com/prometheus/prometheus/blob/851131b0740be7291b98f295567a97f32fffc655/scrape/scrape.go#L933-L935
-	if math.IsNaN(v) {
+	if math.IsNaN(v) && !(tr.reportStaleness && value.IsStaleNaN(v)) {
 		return 0, nil
 	}
 
@@ -181,8 +186,9 @@ func (tr *transaction) Commit() error {
 
 	if tr.useStartTimeMetric {
 		// startTime is mandatory in this case, but may be zero when the
-		// process_start_time_seconds metric is missing from the target endpoint.
-		if tr.metricBuilder.startTime == 0.0 {
+		// process_start_time_seconds metric is missing from the target endpoint,
+		// or when all the series are stale.
+		if tr.metricBuilder.startTime == 0.0 && tr.metricBuilder.hasRecordedValue {
 			// Since we are unable to adjust metrics properly, we will drop them
 			// and return an error.
 			err = errNoStartTimeMetrics
@@ -23,6 +23,7 @@ import (
 	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/scrape"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
@@ -66,7 +67,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Commit Without Adding", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, nomc, nil, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", false, rID, ms, nomc, nil, testLogger)
 		if got := tr.Commit(); got != nil {
 			t.Errorf("expecting nil from Commit() but got err %v", got)
 		}
@@ -74,7 +75,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Rollback dose nothing", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, nomc, nil, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", false, rID, ms, nomc, nil, testLogger)
 		if got := tr.Rollback(); got != nil {
 			t.Errorf("expecting nil from Rollback() but got err %v", got)
 		}
@@ -83,7 +84,7 @@ func Test_transaction(t *testing.T) {
 	badLabels := labels.Labels([]labels.Label{{Name: "foo", Value: "bar"}})
 	t.Run("Add One No Target", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, nomc, nil, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", false, rID, ms, nomc, nil, testLogger)
 		if _, got := tr.Append(0, badLabels, time.Now().Unix()*1000, 1.0); got == nil {
 			t.Errorf("expecting error from Add() but got nil")
 		}
@@ -95,7 +96,7 @@ func Test_transaction(t *testing.T) {
 		{Name: "foo", Value: "bar"}})
 	t.Run("Add One Job not found", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, nomc, nil, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", false, rID, ms, nomc, nil, testLogger)
 		if _, got := tr.Append(0, jobNotFoundLb, time.Now().Unix()*1000, 1.0); got == nil {
 			t.Errorf("expecting error from Add() but got nil")
 		}
@@ -106,7 +107,7 @@ func Test_transaction(t *testing.T) {
 		{Name: "__name__", Value: "foo"}})
 	t.Run("Add One Good", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, sink, nil, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", false, rID, ms, sink, nil, testLogger)
 		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, 1.0); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
@@ -140,7 +141,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Error when start time is zero", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, sink, nil, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", false, rID, ms, sink, nil, testLogger)
 		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, 1.0); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
@@ -155,7 +156,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Drop NaN value", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, sink, nil, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", false, rID, ms, sink, nil, testLogger)
 		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, math.NaN()); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
@@ -167,4 +168,28 @@ func Test_transaction(t *testing.T) {
 		}
 	})
 
+	t.Run("Drop staleness markers", func(t *testing.T) {
+		sink := new(consumertest.MetricsSink)
+		tr := newTransaction(context.Background(), nil, true, "", false, rID, ms, sink, nil, testLogger)
+		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, math.Float64frombits(value.StaleNaN)); got != nil {
+			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
+		}
+		if got := tr.Commit(); got != nil {
+			t.Errorf("expecting nil from Commit() but got err %v", got)
+		}
+		require.Len(t, sink.AllMetrics(), 0)
+	})
+
+	t.Run("Keep staleness markers", func(t *testing.T) {
+		sink := new(consumertest.MetricsSink)
+		tr := newTransaction(context.Background(), nil, true, "", true, rID, ms, sink, nil, testLogger)
+		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, math.Float64frombits(value.StaleNaN)); got != nil {
+			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
+		}
+		// The start time metric is stale too, the markers are not adjusted.
+		if got := tr.Commit(); got != nil {
+			t.Errorf("expecting nil from Commit() but got err %v", got)
+		}
+		require.Len(t, sink.AllMetrics(), 1)
+	})
 }
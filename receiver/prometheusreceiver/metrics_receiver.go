@@ -15,9 +15,15 @@
 package prometheusreceiver
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
 	"time"
 
+	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/scrape"
 	"go.uber.org/zap"
@@ -36,9 +42,17 @@ type pReceiver struct {
 	consumer   consumer.Metrics
 	cancelFunc context.CancelFunc
 
-	logger        *zap.Logger
-	scrapeManager *scrape.Manager
-	ocaStore      *internal.OcaStore
+	logger           *zap.Logger
+	discoveryManager *discovery.Manager
+	scrapeManager    *scrape.Manager
+	ocaStore         *internal.OcaStore
+
+	// mu protects scrapeManager from the zPages requests.
+	mu sync.Mutex
+	// configFileContent is the content of the config file the scrape configs were last loaded from.
+	configFileContent []byte
+	// watcherDone is closed when the config file watcher is stopped.
+	watcherDone chan struct{}
 }
 
 // New creates a new prometheus.Receiver reference.
@@ -54,25 +68,27 @@ func newPrometheusReceiver(logger *zap.Logger, cfg *Config, next consumer.Metric
 // Start is the method that starts Prometheus scraping and it
 // is controlled by having previously defined a Configuration using perhaps New.
 func (r *pReceiver) Start(_ context.Context, host component.Host) error {
+	promCfg := r.cfg.PrometheusConfig
+	if r.cfg.ConfigFile != "" {
+		content, err := ioutil.ReadFile(r.cfg.ConfigFile)
+		if err != nil {
+			return fmt.Errorf("failed to read config_file: %w", err)
+		}
+		if promCfg, err = r.loadConfigFile(content); err != nil {
+			return err
+		}
+		r.configFileContent = content
+	}
+	if promCfg == nil {
+		return errNilScrapeConfig
+	}
+
 	discoveryCtx, cancel := context.WithCancel(context.Background())
 	r.cancelFunc = cancel
 
 	logger := internal.NewZapToGokitLogAdapter(r.logger)
 
-	discoveryManager := discovery.NewManager(discoveryCtx, logger)
-	discoveryCfg := make(map[string]discovery.Configs)
-	for _, scrapeConfig := range r.cfg.PrometheusConfig.ScrapeConfigs {
-		discoveryCfg[scrapeConfig.JobName] = scrapeConfig.ServiceDiscoveryConfigs
-	}
-	if err := discoveryManager.ApplyConfig(discoveryCfg); err != nil {
-		return err
-	}
-	go func() {
-		if err := discoveryManager.Run(); err != nil {
-			r.logger.Error("Discovery manager failed", zap.Error(err))
-			host.ReportFatalError(err)
-		}
-	}()
+	r.discoveryManager = discovery.NewManager(discoveryCtx, logger)
 
 	var jobsMap *internal.JobsMap
 	if !r.cfg.UseStartTimeMetric {
@@ -88,26 +104,119 @@ func (r *pReceiver) Start(_ context.Context, host component.Host) error {
 		jobsMap,
 		r.cfg.UseStartTimeMetric,
 		r.cfg.StartTimeMetricRegex,
+		r.cfg.ReportStalenessMarkers,
 		r.cfg.ID(),
-		r.cfg.PrometheusConfig.GlobalConfig.ExternalLabels,
+		promCfg.GlobalConfig.ExternalLabels,
 	)
-	r.scrapeManager = scrape.NewManager(logger, r.ocaStore)
-	r.ocaStore.SetScrapeManager(r.scrapeManager)
-	if err := r.scrapeManager.ApplyConfig(r.cfg.PrometheusConfig); err != nil {
+	scrapeManager := scrape.NewManager(logger, r.ocaStore)
+	r.ocaStore.SetScrapeManager(scrapeManager)
+	r.mu.Lock()
+	r.scrapeManager = scrapeManager
+	r.mu.Unlock()
+
+	if err := r.applyConfig(promCfg); err != nil {
 		return err
 	}
 	go func() {
-		if err := r.scrapeManager.Run(discoveryManager.SyncCh()); err != nil {
+		if err := r.discoveryManager.Run(); err != nil {
+			r.logger.Error("Discovery manager failed", zap.Error(err))
+			host.ReportFatalError(err)
+		}
+	}()
+	go func() {
+		if err := r.scrapeManager.Run(r.discoveryManager.SyncCh()); err != nil {
 			r.logger.Error("Scrape manager failed", zap.Error(err))
 			host.ReportFatalError(err)
 		}
 	}()
+
+	if r.cfg.ConfigFile != "" {
+		r.watcherDone = make(chan struct{})
+		go r.watchConfigFile(discoveryCtx)
+	}
+	return nil
+}
+
+// applyConfig applies the scrape configs to the discovery and scrape managers. The scrape pools
+// of the jobs that were removed are stopped, and the ones of the jobs that changed are reloaded.
+func (r *pReceiver) applyConfig(promCfg *promconfig.Config) error {
+	discoveryCfg := make(map[string]discovery.Configs)
+	for _, scrapeConfig := range promCfg.ScrapeConfigs {
+		discoveryCfg[scrapeConfig.JobName] = scrapeConfig.ServiceDiscoveryConfigs
+	}
+	if err := r.discoveryManager.ApplyConfig(discoveryCfg); err != nil {
+		return err
+	}
+	if err := r.scrapeManager.ApplyConfig(promCfg); err != nil {
+		return err
+	}
+	// The targets of the reloaded scrape pools are new ones, with their own metadata.
+	r.ocaStore.InvalidateTargets()
 	return nil
 }
 
+// loadConfigFile parses the given content of the config file.
+func (r *pReceiver) loadConfigFile(content []byte) (*promconfig.Config, error) {
+	promCfg, err := promconfig.Load(string(content), false, internal.NewZapToGokitLogAdapter(r.logger))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config_file %s: %w", r.cfg.ConfigFile, err)
+	}
+	// Relative paths in the file, e.g. of file_sd_configs or credentials, are relative to its directory.
+	promCfg.SetDirectory(filepath.Dir(r.cfg.ConfigFile))
+	if err = validatePromConfig(promCfg); err != nil {
+		return nil, fmt.Errorf("invalid config_file %s: %w", r.cfg.ConfigFile, err)
+	}
+	return promCfg, nil
+}
+
+// watchConfigFile checks the config file for changes every ConfigReloadInterval until the context is done.
+func (r *pReceiver) watchConfigFile(ctx context.Context) {
+	defer close(r.watcherDone)
+	ticker := time.NewTicker(r.cfg.ConfigReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reloadConfigFile()
+		}
+	}
+}
+
+// reloadConfigFile applies the scrape configs of the config file if its content changed. The current
+// scrape configs are kept if the file cannot be read or is invalid.
+func (r *pReceiver) reloadConfigFile() {
+	content, err := ioutil.ReadFile(r.cfg.ConfigFile)
+	if err != nil {
+		r.logger.Error("Failed to read config_file, keeping the current scrape configs", zap.Error(err))
+		return
+	}
+	if bytes.Equal(content, r.configFileContent) {
+		return
+	}
+	// The content is recorded even if invalid to only report the error once.
+	r.configFileContent = content
+
+	promCfg, err := r.loadConfigFile(content)
+	if err != nil {
+		r.logger.Error("Failed to load config_file, keeping the current scrape configs", zap.Error(err))
+		return
+	}
+	if err = r.applyConfig(promCfg); err != nil {
+		r.logger.Error("Failed to apply the scrape configs of config_file", zap.Error(err))
+		return
+	}
+	r.logger.Info("Reloaded the scrape configs of config_file", zap.String("config_file", r.cfg.ConfigFile),
+		zap.Int("scrape_configs", len(promCfg.ScrapeConfigs)))
+}
+
 // Shutdown stops and cancels the underlying Prometheus scrapers.
 func (r *pReceiver) Shutdown(context.Context) error {
 	r.cancelFunc()
+	if r.watcherDone != nil {
+		<-r.watcherDone
+	}
 	// ocaStore (and internally metadataService) needs to stop first to prevent deadlocks.
 	// When stopping scrapeManager it waits for all scrapes to terminate. However during
 	// scraping metadataService calls scrapeManager.AllTargets() which acquires
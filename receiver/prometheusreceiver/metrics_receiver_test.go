@@ -17,15 +17,20 @@ package prometheusreceiver
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
@@ -33,6 +38,7 @@ import (
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	gokitlog "github.com/go-kit/kit/log"
 	promcfg "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/scrape"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,6 +49,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/translator/internaldata"
 )
 
@@ -201,8 +208,9 @@ func doCompare(name string, t *testing.T, want, got *agentmetricspb.ExportMetric
 func getValidScrapes(t *testing.T, mds []*agentmetricspb.ExportMetricsServiceRequest) []*agentmetricspb.ExportMetricsServiceRequest {
 	out := make([]*agentmetricspb.ExportMetricsServiceRequest, 0)
 	for _, md := range mds {
-		// mds will include scrapes that received no metrics but have internal scrape metrics, filter those out
-		if expectedScrapeMetricCount < len(md.Metrics) && countScrapeMetrics(md) == expectedScrapeMetricCount {
+		// mds will include failed scrapes, that have internal scrape metrics and the staleness markers of the
+		// metrics of the previous scrape, filter those out
+		if !isStale(t, md) && expectedScrapeMetricCount < len(md.Metrics) && countScrapeMetrics(md) == expectedScrapeMetricCount {
 			assertUp(t, 1, md)
 			out = append(out, md)
 		} else {
@@ -212,6 +220,30 @@ func getValidScrapes(t *testing.T, mds []*agentmetricspb.ExportMetricsServiceReq
 	return out
 }
 
+// isStale returns whether all the points of the metrics, other than the internal scrape metrics, are staleness markers.
+func isStale(t *testing.T, md *agentmetricspb.ExportMetricsServiceRequest) bool {
+	stale, recorded := 0, 0
+	for _, m := range md.Metrics {
+		if countScrapeMetrics(&agentmetricspb.ExportMetricsServiceRequest{Metrics: []*metricspb.Metric{m}}) != 0 {
+			continue
+		}
+		for _, ts := range m.Timeseries {
+			for _, p := range ts.Points {
+				switch {
+				case value.IsStaleNaN(p.GetDoubleValue()),
+					value.IsStaleNaN(p.GetDistributionValue().GetSum()),
+					value.IsStaleNaN(p.GetSummaryValue().GetSum().GetValue()):
+					stale++
+				default:
+					recorded++
+				}
+			}
+		}
+	}
+	assert.False(t, stale > 0 && recorded > 0, "scrape with both stale and recorded values")
+	return stale > 0
+}
+
 func assertUp(t *testing.T, expected float64, md *agentmetricspb.ExportMetricsServiceRequest) {
 	for _, m := range md.Metrics {
 		if m.GetMetricDescriptor().Name == "up" {
@@ -1543,3 +1575,159 @@ func testEndToEndRegex(t *testing.T, targets []*testData, useStartTimeMetric boo
 		target.validateFunc(t, target, results[target.name])
 	}
 }
+
+func writeConfigFile(t *testing.T, file string, host string, jobs ...string) {
+	var sb strings.Builder
+	sb.WriteString("scrape_configs:\n")
+	for _, job := range jobs {
+		fmt.Fprintf(&sb, "  - job_name: %s\n    scrape_interval: 100ms\n    metrics_path: /%s/metrics\n    static_configs:\n      - targets: ['%s']\n", job, job, host)
+	}
+	require.NoError(t, ioutil.WriteFile(file, []byte(sb.String()), 0600))
+}
+
+func activeJobs(rcvr *pReceiver) []string {
+	var jobs []string
+	for job, targets := range rcvr.scrapeManager.TargetsActive() {
+		if len(targets) > 0 {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Strings(jobs)
+	return jobs
+}
+
+func TestConfigFileReload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("# TYPE test_gauge gauge\ntest_gauge 1\n"))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "prometheus.yaml")
+	writeJobs := func(jobs ...string) {
+		writeConfigFile(t, file, u.Host, jobs...)
+	}
+	writeJobs("job1")
+
+	cms := new(consumertest.MetricsSink)
+	rcvr := newPrometheusReceiver(logger, &Config{
+		ReceiverSettings:     config.NewReceiverSettings(config.NewID(typeStr)),
+		ConfigFile:           file,
+		ConfigReloadInterval: 50 * time.Millisecond,
+	}, cms)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, rcvr.Shutdown(context.Background())) })
+
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(activeJobs(rcvr), []string{"job1"})
+	}, 30*time.Second, 10*time.Millisecond)
+
+	// New and removed jobs are applied without restarting the receiver.
+	writeJobs("job2", "job3")
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(activeJobs(rcvr), []string{"job2", "job3"})
+	}, 30*time.Second, 10*time.Millisecond)
+
+	// The current scrape configs are kept when the file becomes invalid.
+	require.NoError(t, ioutil.WriteFile(file, []byte("scrape_configs: [invalid"), 0600))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, []string{"job2", "job3"}, activeJobs(rcvr))
+
+	writeJobs("job1")
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(activeJobs(rcvr), []string{"job1"})
+	}, 30*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return cms.MetricsCount() > 0
+	}, 30*time.Second, 10*time.Millisecond)
+
+	// The targets page lists the targets of the current scrape configs.
+	assert.Eventually(t, func() bool {
+		rr := httptest.NewRecorder()
+		rcvr.handleTargetzRequest(rr, httptest.NewRequest(http.MethodGet, "/debug/targetz/prometheus", nil))
+		return strings.Contains(rr.Body.String(), "job1 (1/1 up)") && !strings.Contains(rr.Body.String(), "job2")
+	}, 30*time.Second, 10*time.Millisecond)
+}
+
+func TestConfigFileStartErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, ioutil.WriteFile(invalid, []byte("scrape_configs: [invalid"), 0600))
+	empty := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, ioutil.WriteFile(empty, []byte("global:\n  scrape_interval: 1s\n"), 0600))
+
+	for _, file := range []string{filepath.Join(dir, "missing.yaml"), invalid, empty} {
+		rcvr := newPrometheusReceiver(logger, &Config{
+			ReceiverSettings:     config.NewReceiverSettings(config.NewID(typeStr)),
+			ConfigFile:           file,
+			ConfigReloadInterval: time.Second,
+		}, consumertest.NewNop())
+		assert.Error(t, rcvr.Start(context.Background(), componenttest.NewNopHost()), file)
+	}
+}
+
+// TestStalenessMarkers checks the points received by the next consumer, e.g. an exporter, once the target stops
+// exposing its metrics.
+func TestStalenessMarkers(t *testing.T) {
+	for _, report := range []bool{false, true} {
+		t.Run(fmt.Sprintf("report_staleness_markers=%v", report), func(t *testing.T) {
+			mp, cfg, err := setupMockPrometheus(&testData{
+				name:  "target1",
+				pages: []mockPrometheusResponse{{code: 200, data: "# TYPE test_gauge gauge\ntest_gauge 1\n"}},
+			})
+			require.NoError(t, err)
+			defer mp.Close()
+
+			cms := new(consumertest.MetricsSink)
+			rcvr := newPrometheusReceiver(logger, &Config{
+				ReceiverSettings:       config.NewReceiverSettings(config.NewID(typeStr)),
+				PrometheusConfig:       cfg,
+				ReportStalenessMarkers: report,
+			}, cms)
+			require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+			t.Cleanup(func() { require.NoError(t, rcvr.Shutdown(context.Background())) })
+
+			// The scrapes fail once the page is served, and the series of the gauge become stale.
+			mp.wg.Wait()
+			assert.Eventually(t, func() bool {
+				return len(gaugeValues(cms, "up")) >= 2
+			}, 30*time.Second, 10*time.Millisecond)
+
+			up := gaugeValues(cms, "up")
+			assert.Equal(t, []float64{1, 0}, up[:2])
+			values := gaugeValues(cms, "test_gauge")
+			require.NotEmpty(t, values)
+			assert.Equal(t, 1.0, values[0])
+			if !report {
+				assert.Len(t, values, 1)
+				return
+			}
+			require.Len(t, values, 2)
+			assert.True(t, value.IsStaleNaN(values[1]))
+		})
+	}
+}
+
+// gaugeValues returns the values of the points of the gauges named name received by cms.
+func gaugeValues(cms *consumertest.MetricsSink, name string) []float64 {
+	var values []float64
+	for _, md := range cms.AllMetrics() {
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			ilms := rms.At(i).InstrumentationLibraryMetrics()
+			for j := 0; j < ilms.Len(); j++ {
+				ms := ilms.At(j).Metrics()
+				for k := 0; k < ms.Len(); k++ {
+					if m := ms.At(k); m.Name() == name && m.DataType() == pdata.MetricDataTypeDoubleGauge {
+						dps := m.DoubleGauge().DataPoints()
+						for l := 0; l < dps.Len(); l++ {
+							values = append(values, dps.At(l).Value())
+						}
+					}
+				}
+			}
+		}
+	}
+	return values
+}
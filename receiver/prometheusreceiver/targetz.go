@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"html/template"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/prometheus/prometheus/scrape"
+	"go.uber.org/zap"
+)
+
+const targetzPath = "targetz"
+
+var targetzTemplate = template.Must(template.New("targetz").Funcs(template.FuncMap{
+	"even": func(i int) bool { return i%2 == 0 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en"><head><meta charset="utf-8"><title>{{.Receiver}} targets</title></head>
+<body>
+<h1>{{.Receiver}} targets</h1>
+{{if not .Started}}<p>The receiver is not started.</p>{{end}}
+{{range .Jobs}}
+<h2>{{.Name}} ({{.Up}}/{{len .Targets}} up{{if .Dropped}}, {{.Dropped}} dropped{{end}})</h2>
+<table style="border-spacing: 0">
+<tr><th>Endpoint</th><th>State</th><th>Labels</th><th>Last Scrape</th><th>Scrape Duration</th><th>Error</th></tr>
+{{range $index, $target := .Targets}}
+<tr{{if even $index}} style="background: #eee"{{end}}>
+<td>{{$target.Endpoint}}</td>
+<td>{{$target.State}}</td>
+<td>{{$target.Labels}}</td>
+<td>{{$target.LastScrape}}</td>
+<td>{{$target.ScrapeDuration}}</td>
+<td>{{$target.Error}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+</body></html>
+`))
+
+type targetzData struct {
+	Receiver string
+	Started  bool
+	Jobs     []targetzJobData
+}
+
+type targetzJobData struct {
+	Name    string
+	Up      int
+	Dropped int
+	Targets []targetzTargetData
+}
+
+type targetzTargetData struct {
+	Endpoint       string
+	State          string
+	Labels         string
+	LastScrape     string
+	ScrapeDuration string
+	Error          string
+}
+
+// RegisterZPages registers the page listing the scrape targets of the receiver, like the
+// Prometheus /targets page, under pathPrefix/targetz/<receiver id>.
+func (r *pReceiver) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
+	mux.HandleFunc(path.Join(pathPrefix, targetzPath, r.cfg.ID().String()), r.handleTargetzRequest)
+}
+
+func (r *pReceiver) handleTargetzRequest(w http.ResponseWriter, _ *http.Request) {
+	r.mu.Lock()
+	scrapeManager := r.scrapeManager
+	r.mu.Unlock()
+
+	data := targetzData{Receiver: r.cfg.ID().String()}
+	if scrapeManager != nil {
+		data.Started = true
+		data.Jobs = getTargetzJobsData(scrapeManager.TargetsActive(), scrapeManager.TargetsDropped(), time.Now())
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := targetzTemplate.Execute(w, data); err != nil {
+		r.logger.Warn("Failed to write the targets zPage", zap.Error(err))
+	}
+}
+
+// getTargetzJobsData returns the targets of each job, sorted by job name and target endpoint.
+func getTargetzJobsData(active, dropped map[string][]*scrape.Target, now time.Time) []targetzJobData {
+	jobs := make([]targetzJobData, 0, len(active))
+	for name, targets := range active {
+		job := targetzJobData{
+			Name:    name,
+			Dropped: len(dropped[name]),
+			Targets: make([]targetzTargetData, 0, len(targets)),
+		}
+		for _, t := range targets {
+			health := t.Health()
+			if health == scrape.HealthGood {
+				job.Up++
+			}
+			td := targetzTargetData{
+				Endpoint: t.URL().String(),
+				State:    string(health),
+				Labels:   t.Labels().String(),
+			}
+			if lastScrape := t.LastScrape(); !lastScrape.IsZero() {
+				td.LastScrape = now.Sub(lastScrape).Truncate(time.Millisecond).String() + " ago"
+				td.ScrapeDuration = t.LastScrapeDuration().String()
+			}
+			if err := t.LastError(); err != nil {
+				td.Error = err.Error()
+			}
+			job.Targets = append(job.Targets, td)
+		}
+		sort.Slice(job.Targets, func(i, j int) bool { return job.Targets[i].Endpoint < job.Targets[j].Endpoint })
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
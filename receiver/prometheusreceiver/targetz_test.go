@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func newTestTarget(job, instance string) *scrape.Target {
+	return scrape.NewTarget(labels.FromStrings(
+		model.AddressLabel, instance,
+		model.SchemeLabel, "http",
+		model.MetricsPathLabel, "/metrics",
+		model.JobLabel, job,
+		model.InstanceLabel, instance,
+	), nil, nil)
+}
+
+func TestGetTargetzJobsData(t *testing.T) {
+	now := time.Now()
+	up := newTestTarget("job1", "localhost:8080")
+	up.Report(now.Add(-2*time.Second), 15*time.Millisecond, nil)
+	down := newTestTarget("job1", "localhost:8081")
+	down.Report(now.Add(-time.Second), 20*time.Millisecond, errors.New("connection refused"))
+	unknown := newTestTarget("job0", "localhost:9090")
+
+	jobs := getTargetzJobsData(
+		map[string][]*scrape.Target{"job1": {down, up}, "job0": {unknown}},
+		map[string][]*scrape.Target{"job1": {newTestTarget("job1", "localhost:8082")}},
+		now)
+	assert.Equal(t, []targetzJobData{
+		{
+			Name: "job0",
+			Targets: []targetzTargetData{{
+				Endpoint: "http://localhost:9090/metrics",
+				State:    "unknown",
+				Labels:   `{instance="localhost:9090", job="job0"}`,
+			}},
+		},
+		{
+			Name:    "job1",
+			Up:      1,
+			Dropped: 1,
+			Targets: []targetzTargetData{
+				{
+					Endpoint:       "http://localhost:8080/metrics",
+					State:          "up",
+					Labels:         `{instance="localhost:8080", job="job1"}`,
+					LastScrape:     "2s ago",
+					ScrapeDuration: "15ms",
+				},
+				{
+					Endpoint:       "http://localhost:8081/metrics",
+					State:          "down",
+					Labels:         `{instance="localhost:8081", job="job1"}`,
+					LastScrape:     "1s ago",
+					ScrapeDuration: "20ms",
+					Error:          "connection refused",
+				},
+			},
+		},
+	}, jobs)
+}
+
+func TestTargetzPage(t *testing.T) {
+	rcvr := newPrometheusReceiver(logger, &Config{
+		ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "customname")),
+	}, consumertest.NewNop())
+
+	mux := http.NewServeMux()
+	rcvr.RegisterZPages(mux, "/debug")
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/targetz/prometheus/customname", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "prometheus/customname targets")
+	assert.Contains(t, rr.Body.String(), "The receiver is not started.")
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// Config defines configuration for the Prometheus remote write receiver.
+type Config struct {
+	config.ReceiverSettings       `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// URLPath is the URL path the write requests are received on, "/api/v1/write" by default.
+	URLPath string `mapstructure:"url_path"`
+
+	// MaxDecodedRequestSize is the maximum size, in bytes, of the write requests once decoded. The requests
+	// claiming a larger decoded size are refused before being decoded.
+	MaxDecodedRequestSize int64 `mapstructure:"max_decoded_request_size"`
+}
+
+var _ config.Receiver = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if !strings.HasPrefix(cfg.URLPath, "/") {
+		return fmt.Errorf("url_path %q must start with \"/\"", cfg.URLPath)
+	}
+	if cfg.MaxDecodedRequestSize <= 0 {
+		return fmt.Errorf("max_decoded_request_size must be positive")
+	}
+	return cfg.HTTPServerSettings.Validate()
+}
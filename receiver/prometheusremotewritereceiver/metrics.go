@@ -0,0 +1,439 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/prompb"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	bucketSuffix = "_bucket"
+	sumSuffix    = "_sum"
+	countSuffix  = "_count"
+	totalSuffix  = "_total"
+
+	leLabel       = model.BucketLabel
+	quantileLabel = model.QuantileLabel
+)
+
+// staleNaN is the value of the Prometheus staleness markers. Like the prometheus receiver, the markers of the
+// histograms and summaries are points without count, buckets nor quantiles, and a stale NaN sum.
+var staleNaN = math.Float64frombits(value.StaleNaN)
+
+type metricKind int
+
+const (
+	gaugeKind metricKind = iota
+	counterKind
+	histogramKind
+	summaryKind
+)
+
+type resourceKey struct {
+	job      string
+	instance string
+}
+
+type familyKey struct {
+	name string
+	kind metricKind
+}
+
+type pointKey struct {
+	signature string
+	timestamp int64
+}
+
+// point holds the samples of a point, i.e. of the series of a family that have the same labels and timestamp.
+type point struct {
+	labels    []prompb.Label
+	timestamp int64
+	stale     bool
+
+	// value is the value of gauges and counters.
+	value float64
+
+	// sum, count and their presence are the sum and count of histograms and summaries.
+	sum      float64
+	hasSum   bool
+	count    float64
+	hasCount bool
+	// buckets are the cumulative counts of the histogram buckets, by upper bound.
+	buckets map[float64]float64
+	// quantiles are the values of the summary quantiles, by quantile.
+	quantiles map[float64]float64
+}
+
+type family struct {
+	points map[pointKey]*point
+	order  []pointKey
+}
+
+type resourceFamilies struct {
+	families map[familyKey]*family
+	order    []familyKey
+}
+
+// metricsBuilder groups the samples of the time series by resource, metric family and point.
+type metricsBuilder struct {
+	types map[string]prompb.MetricMetadata_MetricType
+	// histograms and summaries are the names of the families recognized as histograms and summaries by the labels
+	// of their series, for the series without metadata.
+	histograms map[string]bool
+	summaries  map[string]bool
+
+	resources map[resourceKey]*resourceFamilies
+	order     []resourceKey
+}
+
+// metricsFromTimeSeries converts the samples of the time series to metrics, one resource per job and instance.
+// The types are the metric types of the families by name, the families without metadata are typed by the names
+// and labels of their series: the "_bucket" series with a "le" label are histograms, the series with a "quantile"
+// label are summaries, and the "_total" series are counters.
+func metricsFromTimeSeries(series []prompb.TimeSeries, types map[string]prompb.MetricMetadata_MetricType) pdata.Metrics {
+	b := &metricsBuilder{
+		types:      types,
+		histograms: map[string]bool{},
+		summaries:  map[string]bool{},
+		resources:  map[resourceKey]*resourceFamilies{},
+	}
+	for _, ts := range series {
+		name := labelValue(ts.Labels, model.MetricNameLabel)
+		switch {
+		case strings.HasSuffix(name, bucketSuffix) && hasLabel(ts.Labels, leLabel):
+			b.histograms[strings.TrimSuffix(name, bucketSuffix)] = true
+		case hasLabel(ts.Labels, quantileLabel):
+			b.summaries[name] = true
+		}
+	}
+	for _, ts := range series {
+		b.addTimeSeries(ts)
+	}
+	return b.metrics()
+}
+
+func (b *metricsBuilder) addTimeSeries(ts prompb.TimeSeries) {
+	name := labelValue(ts.Labels, model.MetricNameLabel)
+	if name == "" || len(ts.Samples) == 0 {
+		return
+	}
+	familyName, kind := b.familyOf(name, ts.Labels)
+
+	rk := resourceKey{job: labelValue(ts.Labels, model.JobLabel), instance: labelValue(ts.Labels, model.InstanceLabel)}
+	rf, ok := b.resources[rk]
+	if !ok {
+		rf = &resourceFamilies{families: map[familyKey]*family{}}
+		b.resources[rk] = rf
+		b.order = append(b.order, rk)
+	}
+	fk := familyKey{name: familyName, kind: kind}
+	f, ok := rf.families[fk]
+	if !ok {
+		f = &family{points: map[pointKey]*point{}}
+		rf.families[fk] = f
+		rf.order = append(rf.order, fk)
+	}
+
+	labels := pointLabels(ts.Labels)
+	signature := labelsSignature(labels)
+	for _, s := range ts.Samples {
+		pk := pointKey{signature: signature, timestamp: s.Timestamp}
+		p, ok := f.points[pk]
+		if !ok {
+			p = &point{labels: labels, timestamp: s.Timestamp}
+			f.points[pk] = p
+			f.order = append(f.order, pk)
+		}
+		p.add(kind, familyName, name, ts.Labels, s.Value)
+	}
+}
+
+// familyOf returns the name and kind of the metric family of the series named name.
+func (b *metricsBuilder) familyOf(name string, labels []prompb.Label) (string, metricKind) {
+	if t, ok := b.types[name]; ok {
+		switch t {
+		case prompb.MetricMetadata_COUNTER:
+			return name, counterKind
+		case prompb.MetricMetadata_SUMMARY:
+			if hasLabel(labels, quantileLabel) {
+				return name, summaryKind
+			}
+		case prompb.MetricMetadata_HISTOGRAM, prompb.MetricMetadata_GAUGEHISTOGRAM:
+		default:
+			return name, gaugeKind
+		}
+	}
+
+	for _, suffix := range []string{bucketSuffix, sumSuffix, countSuffix} {
+		if !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		base := strings.TrimSuffix(name, suffix)
+		t := b.types[base]
+		isHistogram := b.histograms[base] || t == prompb.MetricMetadata_HISTOGRAM || t == prompb.MetricMetadata_GAUGEHISTOGRAM
+		if isHistogram && (suffix != bucketSuffix || hasLabel(labels, leLabel)) {
+			return base, histogramKind
+		}
+		isSummary := b.summaries[base] || t == prompb.MetricMetadata_SUMMARY
+		if isSummary && suffix != bucketSuffix {
+			return base, summaryKind
+		}
+	}
+
+	switch {
+	case b.summaries[name]:
+		return name, summaryKind
+	case strings.HasSuffix(name, totalSuffix):
+		return name, counterKind
+	}
+	return name, gaugeKind
+}
+
+// add adds the sample v of the series named name, with the labels, to the point.
+func (p *point) add(kind metricKind, familyName, name string, labels []prompb.Label, v float64) {
+	if value.IsStaleNaN(v) {
+		p.stale = true
+	}
+	switch kind {
+	case gaugeKind, counterKind:
+		p.value = v
+	case histogramKind:
+		switch name {
+		case familyName + sumSuffix:
+			p.sum, p.hasSum = v, true
+		case familyName + countSuffix:
+			p.count, p.hasCount = v, true
+		default:
+			le, err := strconv.ParseFloat(labelValue(labels, leLabel), 64)
+			if err != nil {
+				return
+			}
+			if p.buckets == nil {
+				p.buckets = map[float64]float64{}
+			}
+			p.buckets[le] = v
+		}
+	case summaryKind:
+		switch name {
+		case familyName + sumSuffix:
+			p.sum, p.hasSum = v, true
+		case familyName + countSuffix:
+			p.count, p.hasCount = v, true
+		default:
+			q, err := strconv.ParseFloat(labelValue(labels, quantileLabel), 64)
+			if err != nil {
+				return
+			}
+			if p.quantiles == nil {
+				p.quantiles = map[float64]float64{}
+			}
+			p.quantiles[q] = v
+		}
+	}
+}
+
+func (b *metricsBuilder) metrics() pdata.Metrics {
+	md := pdata.NewMetrics()
+	for _, rk := range b.order {
+		rm := md.ResourceMetrics().AppendEmpty()
+		attrs := rm.Resource().Attributes()
+		if rk.job != "" {
+			attrs.InsertString(model.JobLabel, rk.job)
+		}
+		if rk.instance != "" {
+			attrs.InsertString(model.InstanceLabel, rk.instance)
+		}
+		ms := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+		rf := b.resources[rk]
+		for _, fk := range rf.order {
+			fillMetric(ms.AppendEmpty(), fk, rf.families[fk])
+		}
+	}
+	return md
+}
+
+func fillMetric(m pdata.Metric, fk familyKey, f *family) {
+	m.SetName(fk.name)
+	switch fk.kind {
+	case gaugeKind:
+		m.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		dps := m.DoubleGauge().DataPoints()
+		for _, pk := range f.order {
+			fillDoubleDataPoint(dps.AppendEmpty(), f.points[pk])
+		}
+	case counterKind:
+		m.SetDataType(pdata.MetricDataTypeDoubleSum)
+		m.DoubleSum().SetIsMonotonic(true)
+		m.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		dps := m.DoubleSum().DataPoints()
+		for _, pk := range f.order {
+			fillDoubleDataPoint(dps.AppendEmpty(), f.points[pk])
+		}
+	case histogramKind:
+		m.SetDataType(pdata.MetricDataTypeHistogram)
+		m.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		dps := m.Histogram().DataPoints()
+		for _, pk := range f.order {
+			fillHistogramDataPoint(dps.AppendEmpty(), f.points[pk])
+		}
+	case summaryKind:
+		m.SetDataType(pdata.MetricDataTypeSummary)
+		dps := m.Summary().DataPoints()
+		for _, pk := range f.order {
+			fillSummaryDataPoint(dps.AppendEmpty(), f.points[pk])
+		}
+	}
+}
+
+func fillDoubleDataPoint(dp pdata.DoubleDataPoint, p *point) {
+	fillLabels(dp.LabelsMap(), p.labels)
+	dp.SetTimestamp(timestampFromMs(p.timestamp))
+	dp.SetValue(p.value)
+}
+
+func fillHistogramDataPoint(dp pdata.HistogramDataPoint, p *point) {
+	fillLabels(dp.LabelsMap(), p.labels)
+	dp.SetTimestamp(timestampFromMs(p.timestamp))
+	if p.stale {
+		dp.SetSum(staleNaN)
+		return
+	}
+	dp.SetSum(p.sum)
+
+	bounds := make([]float64, 0, len(p.buckets))
+	for le := range p.buckets {
+		if !math.IsInf(le, 1) {
+			bounds = append(bounds, le)
+		}
+	}
+	sort.Float64s(bounds)
+
+	count := p.count
+	if inf, ok := p.buckets[math.Inf(1)]; ok && !p.hasCount {
+		count = inf
+	}
+	dp.SetCount(uint64(count))
+	if len(p.buckets) == 0 {
+		return
+	}
+
+	// The Prometheus buckets are cumulative, the OTLP ones aren't. The last bucket counts the values greater than
+	// the last bound.
+	counts := make([]uint64, 0, len(bounds)+1)
+	var previous float64
+	for _, le := range bounds {
+		counts = append(counts, bucketCount(p.buckets[le], previous))
+		previous = p.buckets[le]
+	}
+	counts = append(counts, bucketCount(count, previous))
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(counts)
+}
+
+func fillSummaryDataPoint(dp pdata.SummaryDataPoint, p *point) {
+	fillLabels(dp.LabelsMap(), p.labels)
+	dp.SetTimestamp(timestampFromMs(p.timestamp))
+	if p.stale {
+		dp.SetSum(staleNaN)
+		return
+	}
+	dp.SetSum(p.sum)
+	dp.SetCount(uint64(p.count))
+
+	quantiles := make([]float64, 0, len(p.quantiles))
+	for q := range p.quantiles {
+		quantiles = append(quantiles, q)
+	}
+	sort.Float64s(quantiles)
+	for _, q := range quantiles {
+		vq := dp.QuantileValues().AppendEmpty()
+		vq.SetQuantile(q)
+		vq.SetValue(p.quantiles[q])
+	}
+}
+
+// bucketCount returns the count of the bucket with the cumulative count, after the bucket with the cumulative
+// count previous.
+func bucketCount(cumulative, previous float64) uint64 {
+	if cumulative <= previous {
+		return 0
+	}
+	return uint64(cumulative - previous)
+}
+
+func fillLabels(dest pdata.StringMap, labels []prompb.Label) {
+	for _, l := range labels {
+		dest.Insert(l.Name, l.Value)
+	}
+}
+
+// pointLabels returns the labels of the series that are point labels, i.e. all but the metric name, the job and
+// instance of the resource, and the bucket bound and quantile of the histograms and summaries, sorted by name.
+func pointLabels(labels []prompb.Label) []prompb.Label {
+	pls := make([]prompb.Label, 0, len(labels))
+	for _, l := range labels {
+		switch l.Name {
+		case model.MetricNameLabel, model.JobLabel, model.InstanceLabel, leLabel, quantileLabel:
+			continue
+		}
+		pls = append(pls, l)
+	}
+	sort.Slice(pls, func(i, j int) bool { return pls[i].Name < pls[j].Name })
+	return pls
+}
+
+func labelsSignature(labels []prompb.Label) string {
+	var sb strings.Builder
+	for _, l := range labels {
+		sb.WriteString(l.Name)
+		sb.WriteByte(model.SeparatorByte)
+		sb.WriteString(l.Value)
+		sb.WriteByte(model.SeparatorByte)
+	}
+	return sb.String()
+}
+
+func labelValue(labels []prompb.Label, name string) string {
+	for _, l := range labels {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func hasLabel(labels []prompb.Label, name string) bool {
+	for _, l := range labels {
+		if l.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// timestampFromMs converts the Prometheus timestamp in ms to a timestamp.
+func timestampFromMs(ms int64) pdata.Timestamp {
+	return pdata.Timestamp(ms * int64(time.Millisecond))
+}
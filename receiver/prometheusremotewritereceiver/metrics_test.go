@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func timeSeries(ls map[string]string, samples ...prompb.Sample) prompb.TimeSeries {
+	ts := prompb.TimeSeries{Samples: samples}
+	for name, value := range ls {
+		ts.Labels = append(ts.Labels, prompb.Label{Name: name, Value: value})
+	}
+	return ts
+}
+
+func sample(v float64, ms int64) prompb.Sample {
+	return prompb.Sample{Value: v, Timestamp: ms}
+}
+
+func assertResource(t *testing.T, resource pdata.Resource, job, instance string) {
+	attrs := resource.Attributes()
+	assert.Equal(t, 2, attrs.Len())
+	v, _ := attrs.Get("job")
+	assert.Equal(t, job, v.StringVal())
+	v, _ = attrs.Get("instance")
+	assert.Equal(t, instance, v.StringVal())
+}
+
+func TestMetricsFromTimeSeriesResources(t *testing.T) {
+	series := []prompb.TimeSeries{
+		timeSeries(map[string]string{"__name__": "up", "job": "node", "instance": "host1:9100"}, sample(1, 1000)),
+		timeSeries(map[string]string{"__name__": "up", "job": "node", "instance": "host2:9100"}, sample(0, 1000)),
+		timeSeries(map[string]string{"__name__": "temperature", "job": "node", "instance": "host1:9100", "zone": "a"}, sample(21.5, 1000), sample(22, 2000)),
+		timeSeries(map[string]string{"__name__": "no_job"}, sample(3, 1000)),
+	}
+	md := metricsFromTimeSeries(series, nil)
+
+	rms := md.ResourceMetrics()
+	require.Equal(t, 3, rms.Len())
+	assertResource(t, rms.At(0).Resource(), "node", "host1:9100")
+	assertResource(t, rms.At(1).Resource(), "node", "host2:9100")
+	assert.Equal(t, 0, rms.At(2).Resource().Attributes().Len())
+
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, ms.Len())
+	assert.Equal(t, "up", ms.At(0).Name())
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, ms.At(0).DataType())
+
+	temperature := ms.At(1)
+	assert.Equal(t, "temperature", temperature.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, temperature.DataType())
+	dps := temperature.DoubleGauge().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, 1, dps.At(0).LabelsMap().Len())
+	zone, _ := dps.At(0).LabelsMap().Get("zone")
+	assert.Equal(t, "a", zone)
+	assert.Equal(t, 21.5, dps.At(0).Value())
+	assert.Equal(t, pdata.Timestamp(1000_000_000), dps.At(0).Timestamp())
+	assert.Equal(t, 22.0, dps.At(1).Value())
+	assert.Equal(t, pdata.Timestamp(2000_000_000), dps.At(1).Timestamp())
+}
+
+func TestMetricsFromTimeSeriesTypes(t *testing.T) {
+	series := []prompb.TimeSeries{
+		timeSeries(map[string]string{"__name__": "http_requests_total", "code": "200"}, sample(10, 1000)),
+		timeSeries(map[string]string{"__name__": "http_requests_total", "code": "500"}, sample(2, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_bucket", "le": "0.1"}, sample(3, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_bucket", "le": "1"}, sample(5, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_bucket", "le": "+Inf"}, sample(6, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_sum"}, sample(4.2, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_count"}, sample(6, 1000)),
+		timeSeries(map[string]string{"__name__": "rpc", "quantile": "0.5"}, sample(0.2, 1000)),
+		timeSeries(map[string]string{"__name__": "rpc", "quantile": "0.99"}, sample(0.9, 1000)),
+		timeSeries(map[string]string{"__name__": "rpc_sum"}, sample(12, 1000)),
+		timeSeries(map[string]string{"__name__": "rpc_count"}, sample(30, 1000)),
+		timeSeries(map[string]string{"__name__": "queue_count"}, sample(7, 1000)),
+	}
+	ms := metricsFromTimeSeries(series, nil).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 4, ms.Len())
+
+	requests := ms.At(0)
+	assert.Equal(t, "http_requests_total", requests.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleSum, requests.DataType())
+	assert.True(t, requests.DoubleSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, requests.DoubleSum().AggregationTemporality())
+	assert.Equal(t, 2, requests.DoubleSum().DataPoints().Len())
+
+	latency := ms.At(1)
+	assert.Equal(t, "latency", latency.Name())
+	require.Equal(t, pdata.MetricDataTypeHistogram, latency.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, latency.Histogram().AggregationTemporality())
+	require.Equal(t, 1, latency.Histogram().DataPoints().Len())
+	hdp := latency.Histogram().DataPoints().At(0)
+	assert.Equal(t, 4.2, hdp.Sum())
+	assert.Equal(t, uint64(6), hdp.Count())
+	assert.Equal(t, []float64{0.1, 1}, hdp.ExplicitBounds())
+	assert.Equal(t, []uint64{3, 2, 1}, hdp.BucketCounts())
+
+	rpc := ms.At(2)
+	assert.Equal(t, "rpc", rpc.Name())
+	require.Equal(t, pdata.MetricDataTypeSummary, rpc.DataType())
+	require.Equal(t, 1, rpc.Summary().DataPoints().Len())
+	sdp := rpc.Summary().DataPoints().At(0)
+	assert.Equal(t, 12.0, sdp.Sum())
+	assert.Equal(t, uint64(30), sdp.Count())
+	require.Equal(t, 2, sdp.QuantileValues().Len())
+	assert.Equal(t, 0.5, sdp.QuantileValues().At(0).Quantile())
+	assert.Equal(t, 0.2, sdp.QuantileValues().At(0).Value())
+	assert.Equal(t, 0.99, sdp.QuantileValues().At(1).Quantile())
+	assert.Equal(t, 0.9, sdp.QuantileValues().At(1).Value())
+
+	// Without buckets nor quantiles, the _count series is a gauge.
+	queue := ms.At(3)
+	assert.Equal(t, "queue_count", queue.Name())
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, queue.DataType())
+}
+
+func TestMetricsFromTimeSeriesMetadata(t *testing.T) {
+	types := map[string]prompb.MetricMetadata_MetricType{
+		"requests":      prompb.MetricMetadata_COUNTER,
+		"temperature":   prompb.MetricMetadata_GAUGE,
+		"latency":       prompb.MetricMetadata_HISTOGRAM,
+		"rpc":           prompb.MetricMetadata_SUMMARY,
+		"errors_total":  prompb.MetricMetadata_GAUGE,
+		"pending_count": prompb.MetricMetadata_COUNTER,
+	}
+	series := []prompb.TimeSeries{
+		timeSeries(map[string]string{"__name__": "requests"}, sample(10, 1000)),
+		timeSeries(map[string]string{"__name__": "temperature"}, sample(21, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_sum"}, sample(4.2, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_count"}, sample(6, 1000)),
+		timeSeries(map[string]string{"__name__": "rpc_count"}, sample(30, 1000)),
+		timeSeries(map[string]string{"__name__": "errors_total"}, sample(1, 1000)),
+		timeSeries(map[string]string{"__name__": "pending_count"}, sample(3, 1000)),
+	}
+	ms := metricsFromTimeSeries(series, types).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+
+	got := map[string]pdata.MetricDataType{}
+	for i := 0; i < ms.Len(); i++ {
+		got[ms.At(i).Name()] = ms.At(i).DataType()
+	}
+	assert.Equal(t, map[string]pdata.MetricDataType{
+		"requests":      pdata.MetricDataTypeDoubleSum,
+		"temperature":   pdata.MetricDataTypeDoubleGauge,
+		"latency":       pdata.MetricDataTypeHistogram,
+		"rpc":           pdata.MetricDataTypeSummary,
+		"errors_total":  pdata.MetricDataTypeDoubleGauge,
+		"pending_count": pdata.MetricDataTypeDoubleSum,
+	}, got)
+}
+
+func TestMetricsFromTimeSeriesStaleness(t *testing.T) {
+	stale := math.Float64frombits(value.StaleNaN)
+	series := []prompb.TimeSeries{
+		timeSeries(map[string]string{"__name__": "up"}, sample(stale, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_bucket", "le": "1"}, sample(stale, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_bucket", "le": "+Inf"}, sample(stale, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_sum"}, sample(stale, 1000)),
+		timeSeries(map[string]string{"__name__": "latency_count"}, sample(stale, 1000)),
+	}
+	ms := metricsFromTimeSeries(series, nil).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, ms.Len())
+
+	assert.True(t, value.IsStaleNaN(ms.At(0).DoubleGauge().DataPoints().At(0).Value()))
+
+	hdp := ms.At(1).Histogram().DataPoints().At(0)
+	assert.True(t, value.IsStaleNaN(hdp.Sum()))
+	assert.Equal(t, uint64(0), hdp.Count())
+	assert.Len(t, hdp.BucketCounts(), 0)
+	assert.Len(t, hdp.ExplicitBounds(), 0)
+}
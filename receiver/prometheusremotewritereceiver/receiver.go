@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/prometheus/prompb"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/middleware"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	receiverTransport = "http"
+	receiverFormat    = "prometheus_remote_write"
+)
+
+// maxTypes is the maximum number of metric families whose types are kept. As the Prometheus servers send
+// the metadata of all their families periodically, the evicted types are recorded again by the next requests.
+const maxTypes = 10000
+
+// remoteWriteReceiver receives the samples written by Prometheus servers, and sends them to the next consumer
+// as metrics.
+type remoteWriteReceiver struct {
+	cfg          *Config
+	logger       *zap.Logger
+	nextConsumer consumer.Metrics
+	obsrecv      *obsreport.Receiver
+
+	shutdownWG sync.WaitGroup
+	server     *http.Server
+
+	// typesMu protects types.
+	typesMu sync.RWMutex
+	// types are the metric types of the families, by family name, sent in the metadata of the write requests.
+	types map[string]prompb.MetricMetadata_MetricType
+}
+
+var _ http.Handler = (*remoteWriteReceiver)(nil)
+
+func newReceiver(cfg *Config, logger *zap.Logger, nextConsumer consumer.Metrics) (*remoteWriteReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	return &remoteWriteReceiver{
+		cfg:          cfg,
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: cfg.ID(), Transport: receiverTransport}),
+		types:        map[string]prompb.MetricMetadata_MetricType{},
+	}, nil
+}
+
+// Start spins up the receiver's HTTP server.
+func (r *remoteWriteReceiver) Start(_ context.Context, host component.Host) error {
+	if host == nil {
+		return errors.New("nil host")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(r.cfg.URLPath, r)
+	var err error
+	r.server, err = r.cfg.HTTPServerSettings.ToServer(
+		host.GetExtensions(),
+		mux,
+		confighttp.WithObsReport(r.obsrecv),
+	)
+	if err != nil {
+		return err
+	}
+	r.server.Handler = keepSnappyEncoding(r.server.Handler)
+
+	var listener net.Listener
+	listener, err = r.cfg.HTTPServerSettings.ToListener()
+	if err != nil {
+		return err
+	}
+	r.shutdownWG.Add(1)
+	go func() {
+		defer r.shutdownWG.Done()
+
+		if errHTTP := r.server.Serve(listener); errHTTP != http.ErrServerClosed {
+			host.ReportFatalError(errHTTP)
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown stops the HTTP server.
+func (r *remoteWriteReceiver) Shutdown(context.Context) error {
+	if r.server == nil {
+		return nil
+	}
+	err := r.server.Close()
+	r.shutdownWG.Wait()
+	return err
+}
+
+// ServeHTTP decodes the snappy compressed write request, and sends its samples to the next consumer. As expected
+// by the Prometheus remote write senders, the requests that must not be retried are refused with a 4xx status,
+// and the others with a 5xx status.
+func (r *remoteWriteReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := req.Context()
+	if c, ok := client.FromHTTP(req); ok {
+		ctx = client.NewContext(ctx, c)
+	}
+
+	wr, err := r.decodeWriteRequest(req)
+	if middleware.IsBodyTooLarge(err) {
+		r.obsrecv.RecordRefusedRequest(ctx, confighttp.RefusedReasonRequestBodyTooLarge)
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.updateTypes(wr.Metadata)
+	r.typesMu.RLock()
+	md := metricsFromTimeSeries(wr.Timeseries, r.types)
+	r.typesMu.RUnlock()
+
+	dataPointCount := md.DataPointCount()
+	if dataPointCount == 0 {
+		// The metadata are sent in their own requests.
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	ctx = r.obsrecv.StartMetricsOp(obsreport.ReceiverContext(ctx, r.cfg.ID(), receiverTransport))
+	err = r.nextConsumer.ConsumeMetrics(ctx, md)
+	r.obsrecv.EndMetricsOp(ctx, receiverFormat, dataPointCount, err)
+
+	if err != nil {
+		if consumererror.IsPermanent(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeWriteRequest decodes the snappy compressed write request of req, refusing the requests claiming a decoded
+// size over the maximum, or over max_request_body_size if it is lower, before decoding them.
+func (r *remoteWriteReceiver) decodeWriteRequest(req *http.Request) (*prompb.WriteRequest, error) {
+	maxSize := r.cfg.MaxDecodedRequestSize
+	if r.cfg.MaxRequestBodySize > 0 && r.cfg.MaxRequestBodySize < maxSize {
+		maxSize = r.cfg.MaxRequestBodySize
+	}
+	data, err := middleware.DecodeSnappy(req.Body, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	var wr prompb.WriteRequest
+	if err := wr.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return &wr, nil
+}
+
+// keepSnappyEncoding removes the snappy "Content-Encoding" header of the requests, which all the remote write
+// senders set, for the server not to decompress the write requests before they are decoded by the receiver.
+func keepSnappyEncoding(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Content-Encoding") == "snappy" {
+			req.Header.Del("Content-Encoding")
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// updateTypes records the metric types of the families in metadata, evicting the types of other families
+// when maxTypes are recorded.
+func (r *remoteWriteReceiver) updateTypes(metadata []prompb.MetricMetadata) {
+	if len(metadata) == 0 {
+		return
+	}
+	r.typesMu.Lock()
+	defer r.typesMu.Unlock()
+	for _, m := range metadata {
+		if _, ok := r.types[m.MetricFamilyName]; !ok && len(r.types) >= maxTypes {
+			// Evict any type, to keep recording the families of all the senders.
+			for name := range r.types {
+				delete(r.types, name)
+				break
+			}
+		}
+		r.types[m.MetricFamilyName] = m.Type
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func startReceiver(t *testing.T, nextConsumer consumer.Metrics) string {
+	return startReceiverWithConfig(t, createDefaultConfig().(*Config), nextConsumer)
+}
+
+func startReceiverWithConfig(t *testing.T, cfg *Config, nextConsumer consumer.Metrics) string {
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	r, err := newReceiver(cfg, zap.NewNop(), nextConsumer)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+	return "http://" + cfg.Endpoint + defaultURLPath
+}
+
+func postWriteRequest(t *testing.T, url string, wr *prompb.WriteRequest) *http.Response {
+	data, err := wr.Marshal()
+	require.NoError(t, err)
+	return postSnappy(t, url, snappy.Encode(nil, data))
+}
+
+// postSnappy posts body with the headers set by Prometheus.
+func postSnappy(t *testing.T, url string, body []byte) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func TestReceiveWriteRequests(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	url := startReceiver(t, sink)
+
+	resp := postWriteRequest(t, url, &prompb.WriteRequest{
+		Metadata: []prompb.MetricMetadata{{MetricFamilyName: "requests", Type: prompb.MetricMetadata_COUNTER}},
+	})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Len(t, sink.AllMetrics(), 0)
+
+	resp = postWriteRequest(t, url, &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			timeSeries(map[string]string{"__name__": "requests", "job": "api", "instance": "host:8080"}, sample(10, 1000), sample(12, 2000)),
+		},
+	})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	assert.Equal(t, 2, mds[0].DataPointCount())
+	m := mds[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "requests", m.Name())
+	assert.Equal(t, pdata.MetricDataTypeDoubleSum, m.DataType())
+}
+
+func TestReceiveInvalidRequests(t *testing.T) {
+	url := startReceiver(t, consumertest.NewNop())
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, http.MethodPost, resp.Header.Get("Allow"))
+
+	resp, err = http.Post(url, "application/x-protobuf", bytes.NewReader([]byte("not snappy")))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Post(url+"/other", "application/x-protobuf", bytes.NewReader(nil))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestReceiveTooLargeRequests(t *testing.T) {
+	// A tiny snappy block claiming 1 GiB of decoded data.
+	header := make([]byte, binary.MaxVarintLen64)
+	hugeBody := header[:binary.PutUvarint(header, 1<<30)]
+
+	url := startReceiver(t, consumertest.NewNop())
+	resp := postSnappy(t, url, hugeBody)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// The lower of max_decoded_request_size and max_request_body_size applies.
+	wr := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{timeSeries(map[string]string{"__name__": "up"}, sample(1, 1000))},
+	}
+	data, err := wr.Marshal()
+	require.NoError(t, err)
+	for _, limits := range []struct{ maxDecodedRequestSize, maxRequestBodySize int64 }{
+		{maxDecodedRequestSize: int64(len(data)) - 1},
+		{maxDecodedRequestSize: defaultMaxDecodedRequestSize, maxRequestBodySize: int64(len(data)) - 1},
+	} {
+		cfg := createDefaultConfig().(*Config)
+		cfg.MaxDecodedRequestSize = limits.maxDecodedRequestSize
+		cfg.MaxRequestBodySize = limits.maxRequestBodySize
+		resp = postWriteRequest(t, startReceiverWithConfig(t, cfg, consumertest.NewNop()), wr)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	}
+}
+
+func TestReceiveConsumerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{
+			name:       "permanent",
+			err:        consumererror.Permanent(errors.New("invalid metrics")),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "transient",
+			err:        errors.New("queue is full"),
+			statusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := startReceiver(t, consumertest.NewErr(tt.err))
+			resp := postWriteRequest(t, url, &prompb.WriteRequest{
+				Timeseries: []prompb.TimeSeries{timeSeries(map[string]string{"__name__": "up"}, sample(1, 1000))},
+			})
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestUpdateTypesIsBounded(t *testing.T) {
+	r, err := newReceiver(createDefaultConfig().(*Config), zap.NewNop(), consumertest.NewNop())
+	require.NoError(t, err)
+
+	metadata := make([]prompb.MetricMetadata, maxTypes+10)
+	for i := range metadata {
+		metadata[i] = prompb.MetricMetadata{MetricFamilyName: fmt.Sprintf("family_%d", i), Type: prompb.MetricMetadata_COUNTER}
+	}
+	r.updateTypes(metadata)
+	assert.Len(t, r.types, maxTypes)
+
+	// The last families are recorded.
+	last := metadata[len(metadata)-1].MetricFamilyName
+	assert.Equal(t, prompb.MetricMetadata_COUNTER, r.types[last])
+
+	// The types of the recorded families are updated without eviction.
+	r.updateTypes([]prompb.MetricMetadata{{MetricFamilyName: last, Type: prompb.MetricMetadata_GAUGE}})
+	assert.Len(t, r.types, maxTypes)
+	assert.Equal(t, prompb.MetricMetadata_GAUGE, r.types[last])
+}
@@ -15,10 +15,29 @@
 package zipkinreceiver
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
 )
 
+// ScribeConfig defines the Zipkin v1 Scribe protocol server, receiving the spans as base64 encoded Thrift messages
+// of Scribe Log calls, over Thrift framed transport.
+type ScribeConfig struct {
+	// Endpoint is the host:port the Scribe server listens on, the Scribe protocol is disabled if empty.
+	Endpoint string `mapstructure:"endpoint"`
+	// Category is the Scribe category of the span messages, the messages of the other categories are ignored.
+	Category string `mapstructure:"category"`
+	// IdleTimeout is the maximum amount of time to wait for the next call of a connection, the connections idle
+	// for longer are closed. No timeout if 0.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	// MaxConnections is the maximum number of connections open at once, the additional connections are closed
+	// as soon as they are accepted. Unlimited if 0.
+	MaxConnections int `mapstructure:"max_connections"`
+}
+
 // Config defines configuration for Zipkin receiver.
 type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -27,11 +46,39 @@ type Config struct {
 	// If enabled the zipkin receiver will attempt to parse string tags/binary annotations into int/bool/float.
 	// Disabled by default
 	ParseStringTags bool `mapstructure:"parse_string_tags"`
+	// MaxSpansPerRequest is the maximum number of spans of the HTTP requests and Scribe Log calls, the larger
+	// ones are refused. Unlimited if 0.
+	MaxSpansPerRequest int `mapstructure:"max_spans_per_request"`
+	// Scribe configures the Zipkin v1 Scribe protocol.
+	Scribe ScribeConfig `mapstructure:"scribe"`
 }
 
 var _ config.Receiver = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if err := cfg.HTTPServerSettings.Validate(); err != nil {
+		return err
+	}
+	if cfg.MaxSpansPerRequest < 0 {
+		return errors.New("max_spans_per_request can't be negative")
+	}
+	if err := cfg.Scribe.Validate(); err != nil {
+		return fmt.Errorf("invalid scribe settings: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the Scribe configuration is valid
+func (cfg *ScribeConfig) Validate() error {
+	if cfg.Endpoint != "" && cfg.Category == "" {
+		return errors.New("category must be specified")
+	}
+	if cfg.IdleTimeout < 0 {
+		return errors.New("idle_timeout can't be negative")
+	}
+	if cfg.MaxConnections < 0 {
+		return errors.New("max_connections can't be negative")
+	}
 	return nil
 }
@@ -17,6 +17,7 @@ package zipkinreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,6 +51,13 @@ func TestLoadConfig(t *testing.T) {
 			HTTPServerSettings: confighttp.HTTPServerSettings{
 				Endpoint: "localhost:8765",
 			},
+			MaxSpansPerRequest: 1000,
+			Scribe: ScribeConfig{
+				Endpoint:       "localhost:9410",
+				Category:       "zipkin",
+				IdleTimeout:    30 * time.Second,
+				MaxConnections: 100,
+			},
 		})
 
 	r2 := cfg.Receivers[config.NewIDWithName(typeStr, "parse_strings")].(*Config)
@@ -60,5 +68,29 @@ func TestLoadConfig(t *testing.T) {
 				Endpoint: "0.0.0.0:9411",
 			},
 			ParseStringTags: true,
+			Scribe: ScribeConfig{
+				Category:    "zipkin",
+				IdleTimeout: time.Minute,
+			},
 		})
 }
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MaxSpansPerRequest = -1
+	assert.EqualError(t, cfg.Validate(), "max_spans_per_request can't be negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Scribe = ScribeConfig{Endpoint: "localhost:9410"}
+	assert.EqualError(t, cfg.Validate(), "invalid scribe settings: category must be specified")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Scribe.IdleTimeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "invalid scribe settings: idle_timeout can't be negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Scribe.MaxConnections = -1
+	assert.EqualError(t, cfg.Validate(), "invalid scribe settings: max_connections can't be negative")
+}
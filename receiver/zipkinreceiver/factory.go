@@ -16,6 +16,7 @@ package zipkinreceiver
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -30,6 +31,9 @@ const (
 	typeStr = "zipkin"
 
 	defaultBindEndpoint = "0.0.0.0:9411"
+
+	defaultScribeCategory    = "zipkin"
+	defaultScribeIdleTimeout = time.Minute
 )
 
 // NewFactory creates a new Zipkin receiver factory
@@ -49,16 +53,25 @@ func createDefaultConfig() config.Receiver {
 			Endpoint: defaultBindEndpoint,
 		},
 		ParseStringTags: false,
+		Scribe: ScribeConfig{
+			Category:    defaultScribeCategory,
+			IdleTimeout: defaultScribeIdleTimeout,
+		},
 	}
 }
 
 // createTracesReceiver creates a trace receiver based on provided config.
 func createTracesReceiver(
 	_ context.Context,
-	_ component.ReceiverCreateSettings,
+	set component.ReceiverCreateSettings,
 	cfg config.Receiver,
 	nextConsumer consumer.Traces,
 ) (component.TracesReceiver, error) {
 	rCfg := cfg.(*Config)
-	return New(rCfg, nextConsumer)
+	zr, err := New(rCfg, nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	zr.logger = set.Logger
+	return zr, nil
 }
@@ -0,0 +1,395 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinreceiver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/trace/zipkinv1"
+)
+
+const (
+	receiverTransportScribe = "scribe_v1_thrift"
+
+	// scribeLogMethod is the method of the Scribe service, Log(1: list<LogEntry> messages) -> ResultCode.
+	scribeLogMethod = "Log"
+
+	// The Scribe result codes.
+	scribeResultOK       = 0
+	scribeResultTryLater = 1
+)
+
+// scribeLogEntry is a Scribe LogEntry{1: string category, 2: string message}.
+type scribeLogEntry struct {
+	category string
+	message  string
+}
+
+// scribeServer receives the Zipkin v1 spans of Scribe Log calls, as sent by Finagle and the Zipkin Scribe
+// clients. The calls are answered with TRY_LATER when the spans are refused by the next consumer, so that the
+// clients retry them, and the messages that are not valid spans are dropped. The calls with more than
+// max_spans_per_request spans are dropped as well, Scribe having no result code for refusing them for good.
+type scribeServer struct {
+	zr       *ZipkinReceiver
+	listener net.Listener
+	obsrecv  *obsreport.Receiver
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newScribeServer(zr *ZipkinReceiver) *scribeServer {
+	return &scribeServer{
+		zr:      zr,
+		obsrecv: obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: zr.id, Transport: receiverTransportScribe}),
+		conns:   map[net.Conn]struct{}{},
+	}
+}
+
+// start listens on the Scribe endpoint, and serves the connections.
+func (s *scribeServer) start(host component.Host) error {
+	var err error
+	s.listener, err = net.Listen("tcp", s.zr.config.Scribe.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to bind to the scribe address %q: %w", s.zr.config.Scribe.Endpoint, err)
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				if !s.isClosed() {
+					host.ReportFatalError(err)
+				}
+				return
+			}
+			if err = s.track(conn); err != nil {
+				_ = conn.Close()
+				if errors.Is(err, errScribeServerClosed) {
+					return
+				}
+				s.zr.logger.Debug("Closing the scribe connection", zap.Stringer("remote", conn.RemoteAddr()), zap.Error(err))
+				continue
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer s.untrack(conn)
+				s.serve(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// shutdown closes the listener and the connections, and waits for the calls being handled.
+func (s *scribeServer) shutdown() error {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	s.wg.Wait()
+	return err
+}
+
+func (s *scribeServer) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+var (
+	errScribeServerClosed = errors.New("server closed")
+	errTooManyScribeConns = errors.New("too many connections")
+)
+
+// track records the connection, unless the server is closed or has max_connections connections open.
+func (s *scribeServer) track(conn net.Conn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errScribeServerClosed
+	}
+	if max := s.zr.config.Scribe.MaxConnections; max > 0 && len(s.conns) >= max {
+		return errTooManyScribeConns
+	}
+	s.conns[conn] = struct{}{}
+	return nil
+}
+
+func (s *scribeServer) untrack(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	_ = conn.Close()
+}
+
+// serve handles the calls of the connection until it is closed, idle for idle_timeout, or a call is malformed.
+func (s *scribeServer) serve(conn net.Conn) {
+	// The Scribe clients write the strict binary protocol, with the message version. The socket timeout
+	// applies to each read, so that the connections are closed when no call is received for idle_timeout.
+	cfg := &thrift.TConfiguration{
+		TBinaryStrictRead: thrift.BoolPtr(true),
+		SocketTimeout:     s.zr.config.Scribe.IdleTimeout,
+	}
+	transport := thrift.NewTFramedTransportConf(thrift.NewTSocketFromConnConf(conn, cfg), cfg)
+	protocol := thrift.NewTBinaryProtocolConf(transport, cfg)
+	ctx := context.Background()
+	if c, ok := clientFromConn(conn); ok {
+		ctx = client.NewContext(ctx, c)
+	}
+	for {
+		if err := s.handleCall(ctx, protocol); err != nil {
+			if !errors.Is(err, errScribeConnClosed) && !s.isClosed() {
+				s.zr.logger.Debug("Closing the scribe connection", zap.Stringer("remote", conn.RemoteAddr()), zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+var errScribeConnClosed = errors.New("connection closed")
+
+// handleCall reads a call, and writes its reply.
+func (s *scribeServer) handleCall(ctx context.Context, protocol thrift.TProtocol) error {
+	name, typeID, seqID, err := protocol.ReadMessageBegin(ctx)
+	if err != nil {
+		var tErr thrift.TTransportException
+		if errors.As(err, &tErr) && tErr.TypeId() == thrift.END_OF_FILE {
+			return errScribeConnClosed
+		}
+		return err
+	}
+	if name != scribeLogMethod || typeID != thrift.CALL {
+		if err = protocol.Skip(ctx, thrift.STRUCT); err != nil {
+			return err
+		}
+		if err = protocol.ReadMessageEnd(ctx); err != nil {
+			return err
+		}
+		appErr := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "unknown function "+name)
+		if err = protocol.WriteMessageBegin(ctx, name, thrift.EXCEPTION, seqID); err != nil {
+			return err
+		}
+		if err = appErr.Write(ctx, protocol); err != nil {
+			return err
+		}
+		if err = protocol.WriteMessageEnd(ctx); err != nil {
+			return err
+		}
+		return protocol.Flush(ctx)
+	}
+
+	entries, err := readScribeLogArgs(ctx, protocol)
+	if err != nil {
+		return err
+	}
+	if err = protocol.ReadMessageEnd(ctx); err != nil {
+		return err
+	}
+
+	result := int32(scribeResultOK)
+	if err = s.consume(ctx, entries); err != nil {
+		result = scribeResultTryLater
+	}
+
+	if err = protocol.WriteMessageBegin(ctx, scribeLogMethod, thrift.REPLY, seqID); err != nil {
+		return err
+	}
+	if err = protocol.WriteStructBegin(ctx, "Log_result"); err != nil {
+		return err
+	}
+	if err = protocol.WriteFieldBegin(ctx, "success", thrift.I32, 0); err != nil {
+		return err
+	}
+	if err = protocol.WriteI32(ctx, result); err != nil {
+		return err
+	}
+	if err = protocol.WriteFieldEnd(ctx); err != nil {
+		return err
+	}
+	if err = protocol.WriteFieldStop(ctx); err != nil {
+		return err
+	}
+	if err = protocol.WriteStructEnd(ctx); err != nil {
+		return err
+	}
+	if err = protocol.WriteMessageEnd(ctx); err != nil {
+		return err
+	}
+	return protocol.Flush(ctx)
+}
+
+// consume sends the spans of the entries of the Zipkin category to the next consumer. The entries that are not
+// valid spans are dropped.
+func (s *scribeServer) consume(ctx context.Context, entries []scribeLogEntry) error {
+	var spans []*zipkincore.Span
+	for _, entry := range entries {
+		if entry.category != s.zr.config.Scribe.Category {
+			continue
+		}
+		span, err := decodeScribeSpan(entry.message)
+		if err != nil {
+			s.zr.logger.Debug("Dropping an invalid scribe message", zap.Error(err))
+			continue
+		}
+		spans = append(spans, span)
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	ctx = s.obsrecv.StartTracesOp(obsreport.ReceiverContext(ctx, s.zr.id, receiverTransportScribe))
+	if max := s.zr.config.MaxSpansPerRequest; max > 0 && len(spans) > max {
+		err := fmt.Errorf("the call has %d spans, more than the maximum of %d", len(spans), max)
+		s.obsrecv.EndTracesOp(ctx, zipkinV1TagValue, len(spans), err)
+		s.zr.logger.Debug("Dropping the spans of a scribe call", zap.Error(err))
+		return nil
+	}
+	td, err := zipkinv1.ThriftSpansToInternalTraces(spans)
+	if err != nil {
+		s.obsrecv.EndTracesOp(ctx, zipkinV1TagValue, len(spans), err)
+		s.zr.logger.Debug("Dropping invalid scribe spans", zap.Error(err))
+		return nil
+	}
+	err = s.zr.nextConsumer.ConsumeTraces(ctx, td)
+	s.obsrecv.EndTracesOp(ctx, zipkinV1TagValue, td.SpanCount(), err)
+	return err
+}
+
+// readScribeLogArgs reads the Log_args{1: list<LogEntry> messages} struct.
+func readScribeLogArgs(ctx context.Context, protocol thrift.TProtocol) ([]scribeLogEntry, error) {
+	var entries []scribeLogEntry
+	if _, err := protocol.ReadStructBegin(ctx); err != nil {
+		return nil, err
+	}
+	for {
+		_, fieldType, fieldID, err := protocol.ReadFieldBegin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if fieldType == thrift.STOP {
+			break
+		}
+		if fieldID == 1 && fieldType == thrift.LIST {
+			if entries, err = readScribeLogEntries(ctx, protocol); err != nil {
+				return nil, err
+			}
+		} else if err = protocol.Skip(ctx, fieldType); err != nil {
+			return nil, err
+		}
+		if err = protocol.ReadFieldEnd(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return entries, protocol.ReadStructEnd(ctx)
+}
+
+func readScribeLogEntries(ctx context.Context, protocol thrift.TProtocol) ([]scribeLogEntry, error) {
+	elemType, size, err := protocol.ReadListBegin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if elemType != thrift.STRUCT {
+		return nil, fmt.Errorf("expected a list of log entries, got a list of %v", elemType)
+	}
+	// The size isn't used to preallocate the entries, it is only bounded by the frame size.
+	var entries []scribeLogEntry
+	for i := 0; i < size; i++ {
+		entry, err := readScribeLogEntry(ctx, protocol)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, protocol.ReadListEnd(ctx)
+}
+
+func readScribeLogEntry(ctx context.Context, protocol thrift.TProtocol) (scribeLogEntry, error) {
+	var entry scribeLogEntry
+	if _, err := protocol.ReadStructBegin(ctx); err != nil {
+		return entry, err
+	}
+	for {
+		_, fieldType, fieldID, err := protocol.ReadFieldBegin(ctx)
+		if err != nil {
+			return entry, err
+		}
+		if fieldType == thrift.STOP {
+			break
+		}
+		switch {
+		case fieldID == 1 && fieldType == thrift.STRING:
+			entry.category, err = protocol.ReadString(ctx)
+		case fieldID == 2 && fieldType == thrift.STRING:
+			entry.message, err = protocol.ReadString(ctx)
+		default:
+			err = protocol.Skip(ctx, fieldType)
+		}
+		if err != nil {
+			return entry, err
+		}
+		if err = protocol.ReadFieldEnd(ctx); err != nil {
+			return entry, err
+		}
+	}
+	return entry, protocol.ReadStructEnd(ctx)
+}
+
+// decodeScribeSpan decodes the base64 encoded Thrift span of a Scribe message.
+func decodeScribeSpan(message string) (*zipkincore.Span, error) {
+	buf, err := base64.StdEncoding.DecodeString(strings.TrimSpace(message))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 message: %w", err)
+	}
+	transport := thrift.NewTMemoryBufferLen(len(buf))
+	_, _ = transport.Write(buf)
+	protocol := thrift.NewTBinaryProtocolConf(transport, &thrift.TConfiguration{MaxMessageSize: int32(len(buf))})
+	span := &zipkincore.Span{}
+	if err = span.Read(context.Background(), protocol); err != nil {
+		return nil, fmt.Errorf("invalid thrift span: %w", err)
+	}
+	return span, nil
+}
+
+func clientFromConn(conn net.Conn) (*client.Client, bool) {
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return nil, false
+	}
+	return &client.Client{IP: addr.IP.String()}, true
+}
@@ -0,0 +1,275 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinreceiver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+// scribeClient sends Scribe Log calls.
+type scribeClient struct {
+	conn     net.Conn
+	protocol thrift.TProtocol
+	seqID    int32
+}
+
+func newScribeClient(t *testing.T, endpoint string) *scribeClient {
+	conn, err := net.Dial("tcp", endpoint)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	cfg := &thrift.TConfiguration{}
+	transport := thrift.NewTFramedTransportConf(thrift.NewTSocketFromConnConf(conn, cfg), cfg)
+	return &scribeClient{conn: conn, protocol: thrift.NewTBinaryProtocolConf(transport, cfg)}
+}
+
+// log calls method with the entries, and returns the result code.
+func (c *scribeClient) log(t *testing.T, method string, entries []scribeLogEntry) (int32, error) {
+	ctx := context.Background()
+	p := c.protocol
+	c.seqID++
+	require.NoError(t, p.WriteMessageBegin(ctx, method, thrift.CALL, c.seqID))
+	require.NoError(t, p.WriteStructBegin(ctx, "Log_args"))
+	require.NoError(t, p.WriteFieldBegin(ctx, "messages", thrift.LIST, 1))
+	require.NoError(t, p.WriteListBegin(ctx, thrift.STRUCT, len(entries)))
+	for _, entry := range entries {
+		require.NoError(t, p.WriteStructBegin(ctx, "LogEntry"))
+		require.NoError(t, p.WriteFieldBegin(ctx, "category", thrift.STRING, 1))
+		require.NoError(t, p.WriteString(ctx, entry.category))
+		require.NoError(t, p.WriteFieldEnd(ctx))
+		require.NoError(t, p.WriteFieldBegin(ctx, "message", thrift.STRING, 2))
+		require.NoError(t, p.WriteString(ctx, entry.message))
+		require.NoError(t, p.WriteFieldEnd(ctx))
+		require.NoError(t, p.WriteFieldStop(ctx))
+		require.NoError(t, p.WriteStructEnd(ctx))
+	}
+	require.NoError(t, p.WriteListEnd(ctx))
+	require.NoError(t, p.WriteFieldEnd(ctx))
+	require.NoError(t, p.WriteFieldStop(ctx))
+	require.NoError(t, p.WriteStructEnd(ctx))
+	require.NoError(t, p.WriteMessageEnd(ctx))
+	require.NoError(t, p.Flush(ctx))
+
+	name, typeID, seqID, err := p.ReadMessageBegin(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, method, name)
+	assert.Equal(t, c.seqID, seqID)
+	if typeID == thrift.EXCEPTION {
+		appErr := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "")
+		require.NoError(t, appErr.Read(ctx, p))
+		require.NoError(t, p.ReadMessageEnd(ctx))
+		return 0, appErr
+	}
+	require.Equal(t, thrift.REPLY, typeID)
+
+	var result int32
+	_, err = p.ReadStructBegin(ctx)
+	require.NoError(t, err)
+	for {
+		_, fieldType, fieldID, err := p.ReadFieldBegin(ctx)
+		require.NoError(t, err)
+		if fieldType == thrift.STOP {
+			break
+		}
+		require.Equal(t, int16(0), fieldID)
+		result, err = p.ReadI32(ctx)
+		require.NoError(t, err)
+		require.NoError(t, p.ReadFieldEnd(ctx))
+	}
+	require.NoError(t, p.ReadStructEnd(ctx))
+	require.NoError(t, p.ReadMessageEnd(ctx))
+	return result, nil
+}
+
+func scribeMessage(t *testing.T, span *zipkincore.Span) string {
+	buf, err := thrift.NewTSerializer().Write(context.Background(), span)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+func startScribeReceiver(t *testing.T, next consumer.Traces) string {
+	return startScribeReceiverWithConfig(t, createDefaultConfig().(*Config), next)
+}
+
+func startScribeReceiverWithConfig(t *testing.T, cfg *Config, next consumer.Traces) string {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.Scribe.Endpoint = endpoint
+	zr, err := New(cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, zr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, zr.Shutdown(context.Background())) })
+	return endpoint
+}
+
+func TestScribeReception(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	client := newScribeClient(t, startScribeReceiver(t, sink))
+
+	result, err := client.log(t, scribeLogMethod, []scribeLogEntry{
+		{category: "zipkin", message: scribeMessage(t, &zipkincore.Span{TraceID: 1, ID: 1, Name: "first"})},
+		{category: "other", message: scribeMessage(t, &zipkincore.Span{TraceID: 1, ID: 2, Name: "other"})},
+		{category: "zipkin", message: "not base64"},
+		{category: "zipkin", message: base64.StdEncoding.EncodeToString([]byte{0x0b})},
+		{category: "zipkin", message: scribeMessage(t, &zipkincore.Span{TraceID: 1, ID: 3, Name: "second"}) + "\n"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultOK), result)
+
+	require.Len(t, sink.AllTraces(), 1)
+	spans := sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	require.Equal(t, 2, spans.Len())
+	assert.Equal(t, "first", spans.At(0).Name())
+	assert.Equal(t, "second", spans.At(1).Name())
+
+	// the connection serves the following calls
+	result, err = client.log(t, scribeLogMethod, []scribeLogEntry{
+		{category: "zipkin", message: scribeMessage(t, &zipkincore.Span{TraceID: 2, ID: 1, Name: "third"})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultOK), result)
+	assert.Equal(t, 3, sink.SpansCount())
+}
+
+func TestScribeConsumerError(t *testing.T) {
+	client := newScribeClient(t, startScribeReceiver(t, consumertest.NewErr(errors.New("consumer error"))))
+
+	result, err := client.log(t, scribeLogMethod, []scribeLogEntry{
+		{category: "zipkin", message: scribeMessage(t, &zipkincore.Span{TraceID: 1, ID: 1, Name: "span"})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultTryLater), result)
+}
+
+func TestScribeMaxSpansPerRequest(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxSpansPerRequest = 1
+	sink := new(consumertest.TracesSink)
+	client := newScribeClient(t, startScribeReceiverWithConfig(t, cfg, sink))
+
+	// the calls with too many spans are dropped, without being retried
+	result, err := client.log(t, scribeLogMethod, []scribeLogEntry{
+		{category: "zipkin", message: scribeMessage(t, &zipkincore.Span{TraceID: 1, ID: 1, Name: "first"})},
+		{category: "zipkin", message: scribeMessage(t, &zipkincore.Span{TraceID: 1, ID: 2, Name: "second"})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultOK), result)
+	assert.Equal(t, 0, sink.SpansCount())
+
+	result, err = client.log(t, scribeLogMethod, []scribeLogEntry{
+		{category: "zipkin", message: scribeMessage(t, &zipkincore.Span{TraceID: 2, ID: 1, Name: "third"})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultOK), result)
+	assert.Equal(t, 1, sink.SpansCount())
+}
+
+func TestScribeIdleTimeout(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Scribe.IdleTimeout = 100 * time.Millisecond
+	client := newScribeClient(t, startScribeReceiverWithConfig(t, cfg, consumertest.NewNop()))
+
+	result, err := client.log(t, scribeLogMethod, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultOK), result)
+
+	// the idle connection is closed by the server
+	requireConnClosed(t, client.conn)
+}
+
+func TestScribeMaxConnections(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Scribe.MaxConnections = 1
+	endpoint := startScribeReceiverWithConfig(t, cfg, consumertest.NewNop())
+
+	first := newScribeClient(t, endpoint)
+	result, err := first.log(t, scribeLogMethod, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultOK), result)
+
+	// the connections above the limit are closed as soon as accepted
+	second := newScribeClient(t, endpoint)
+	requireConnClosed(t, second.conn)
+
+	// the first connection keeps serving its calls
+	result, err = first.log(t, scribeLogMethod, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultOK), result)
+}
+
+// requireConnClosed checks that the connection is closed by the server.
+func requireConnClosed(t *testing.T, conn net.Conn) {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err := conn.Read(make([]byte, 1))
+	require.Error(t, err)
+	var netErr net.Error
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "the connection must be closed")
+}
+
+func TestScribeUnknownMethod(t *testing.T) {
+	client := newScribeClient(t, startScribeReceiver(t, consumertest.NewNop()))
+
+	_, err := client.log(t, "Unknown", nil)
+	var appErr thrift.TApplicationException
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, int32(thrift.UNKNOWN_METHOD), appErr.TypeId())
+
+	result, err := client.log(t, scribeLogMethod, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(scribeResultOK), result)
+}
+
+func TestScribeMalformedCall(t *testing.T) {
+	client := newScribeClient(t, startScribeReceiver(t, consumertest.NewNop()))
+
+	// a frame that isn't a thrift message closes the connection
+	_, err := client.conn.Write([]byte{0, 0, 0, 4, 0x12, 0x34, 0x56, 0x78})
+	require.NoError(t, err)
+	requireConnClosed(t, client.conn)
+}
+
+func TestScribeEndpointInUse(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	cfg := &Config{
+		ReceiverSettings: config.NewReceiverSettings(zipkinReceiverID),
+		Scribe: ScribeConfig{
+			Endpoint: l.Addr().String(),
+			Category: defaultScribeCategory,
+		},
+	}
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	zr, err := New(cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, zr.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, zr.Shutdown(context.Background()))
+}
@@ -18,19 +18,26 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"strings"
 	"sync"
 
+	"go.uber.org/zap"
+
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/trace/zipkinv1"
@@ -42,10 +49,26 @@ const (
 	receiverTransportV1JSON   = "http_v1_json"
 	receiverTransportV2JSON   = "http_v2_json"
 	receiverTransportV2PROTO  = "http_v2_proto"
+	// receiverTransportHTTP is used for the requests refused before their format is known.
+	receiverTransportHTTP = "http"
 )
 
+// formatNames are the names of the formats of the transports, in the error responses.
+var formatNames = map[string]string{
+	receiverTransportV1Thrift: "Zipkin v1 Thrift",
+	receiverTransportV1JSON:   "Zipkin v1 JSON",
+	receiverTransportV2JSON:   "Zipkin v2 JSON",
+	receiverTransportV2PROTO:  "Zipkin v2 Protobuf",
+}
+
 var errNextConsumerRespBody = []byte(`"Internal Server Error"`)
 
+// errorResponse is the body of the responses to the requests refused because of their content.
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
 // ZipkinReceiver type is used to handle spans received in the Zipkin format.
 type ZipkinReceiver struct {
 	// addr is the address onto which the HTTP server will be bound
@@ -55,7 +78,9 @@ type ZipkinReceiver struct {
 
 	shutdownWG sync.WaitGroup
 	server     *http.Server
+	scribe     *scribeServer
 	config     *Config
+	logger     *zap.Logger
 
 	v1ThriftUnmarshaler      pdata.TracesUnmarshaler
 	v1JSONUnmarshaler        pdata.TracesUnmarshaler
@@ -76,6 +101,7 @@ func New(config *Config, nextConsumer consumer.Traces) (*ZipkinReceiver, error)
 		nextConsumer:             nextConsumer,
 		id:                       config.ID(),
 		config:                   config,
+		logger:                   zap.NewNop(),
 		v1ThriftUnmarshaler:      zipkinv1.NewThriftTracesUnmarshaler(),
 		v1JSONUnmarshaler:        zipkinv1.NewJSONTracesUnmarshaler(config.ParseStringTags),
 		jsonUnmarshaler:          zipkinv2.NewJSONTracesUnmarshaler(config.ParseStringTags),
@@ -92,9 +118,19 @@ func (zr *ZipkinReceiver) Start(_ context.Context, host component.Host) error {
 	}
 
 	zr.host = host
-	zr.server = zr.config.HTTPServerSettings.ToServer(zr)
+	var err error
+	zr.server, err = zr.config.HTTPServerSettings.ToServer(
+		host.GetExtensions(),
+		zr,
+		confighttp.WithObsReport(obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: zr.id, Transport: receiverTransportHTTP})),
+		confighttp.WithErrorHandler(writeError),
+	)
+	if err != nil {
+		return err
+	}
+
 	var listener net.Listener
-	listener, err := zr.config.HTTPServerSettings.ToListener()
+	listener, err = zr.config.HTTPServerSettings.ToListener()
 	if err != nil {
 		return err
 	}
@@ -107,12 +143,19 @@ func (zr *ZipkinReceiver) Start(_ context.Context, host component.Host) error {
 		}
 	}()
 
+	if zr.config.Scribe.Endpoint != "" {
+		zr.scribe = newScribeServer(zr)
+		if err = zr.scribe.start(host); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // v1ToTraceSpans parses Zipkin v1 JSON traces and converts them to OpenCensus Proto spans.
 func (zr *ZipkinReceiver) v1ToTraceSpans(blob []byte, hdr http.Header) (reqs pdata.Traces, err error) {
-	if hdr.Get("Content-Type") == "application/x-thrift" {
+	if contentType(hdr) == "application/x-thrift" {
 		return zr.v1ThriftUnmarshaler.UnmarshalTraces(blob)
 	}
 	return zr.v1JSONUnmarshaler.UnmarshalTraces(blob)
@@ -128,7 +171,7 @@ func (zr *ZipkinReceiver) v2ToTraceSpans(blob []byte, hdr http.Header) (reqs pda
 	unmarshaler := zr.jsonUnmarshaler
 
 	// Zipkin can send protobuf via http
-	if hdr.Get("Content-Type") == "application/x-protobuf" {
+	if contentType(hdr) == "application/x-protobuf" {
 		// TODO: (@odeke-em) record the unique types of Content-Type uploads
 		if debugWasSet {
 			unmarshaler = zr.protobufDebugUnmarshaler
@@ -144,9 +187,19 @@ func (zr *ZipkinReceiver) v2ToTraceSpans(blob []byte, hdr http.Header) (reqs pda
 // giving it a chance to perform any necessary clean-up and shutting down
 // its HTTP server.
 func (zr *ZipkinReceiver) Shutdown(context.Context) error {
-	err := zr.server.Close()
+	var errs []error
+	if zr.server != nil {
+		if err := zr.server.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if zr.scribe != nil {
+		if err := zr.scribe.shutdown(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	zr.shutdownWG.Wait()
-	return err
+	return consumererror.Combine(errs)
 }
 
 // processBodyIfNecessary checks the "Content-Encoding" HTTP header and if
@@ -205,15 +258,24 @@ func (zr *ZipkinReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	obsrecv := obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: zr.id, Transport: transportTag})
 	ctx = obsrecv.StartTracesOp(obsreport.ReceiverContext(ctx, zr.id, transportTag))
 
+	receiverTagValue := zipkinV2TagValue
+	if asZipkinv1 {
+		receiverTagValue = zipkinV1TagValue
+	}
+
 	pr := processBodyIfNecessary(r)
-	slurp, _ := ioutil.ReadAll(pr)
+	slurp, err := ioutil.ReadAll(pr)
 	if c, ok := pr.(io.Closer); ok {
 		_ = c.Close()
 	}
 	_ = r.Body.Close()
+	if err != nil {
+		obsrecv.EndTracesOp(ctx, receiverTagValue, 0, err)
+		writeError(w, r, fmt.Sprintf("failed to read the request body: %v", err), http.StatusBadRequest)
+		return
+	}
 
 	var td pdata.Traces
-	var err error
 	if asZipkinv1 {
 		td, err = zr.v1ToTraceSpans(slurp, r.Header)
 	} else {
@@ -221,16 +283,20 @@ func (zr *ZipkinReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		obsrecv.EndTracesOp(ctx, receiverTagValue, 0, err)
+		writeError(w, r, fmt.Sprintf("failed to parse the %s spans: %v", formatNames[transportTag], err), http.StatusBadRequest)
+		return
+	}
+
+	if zr.config.MaxSpansPerRequest > 0 && td.SpanCount() > zr.config.MaxSpansPerRequest {
+		err = fmt.Errorf("the request has %d spans, more than the maximum of %d", td.SpanCount(), zr.config.MaxSpansPerRequest)
+		obsrecv.EndTracesOp(ctx, receiverTagValue, td.SpanCount(), err)
+		writeError(w, r, err.Error(), http.StatusRequestEntityTooLarge)
 		return
 	}
 
 	consumerErr := zr.nextConsumer.ConsumeTraces(ctx, td)
 
-	receiverTagValue := zipkinV2TagValue
-	if asZipkinv1 {
-		receiverTagValue = zipkinV1TagValue
-	}
 	obsrecv.EndTracesOp(ctx, receiverTagValue, td.SpanCount(), consumerErr)
 
 	if consumerErr != nil {
@@ -247,13 +313,31 @@ func (zr *ZipkinReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func transportType(r *http.Request, asZipkinv1 bool) string {
 	if asZipkinv1 {
-		if r.Header.Get("Content-Type") == "application/x-thrift" {
+		if contentType(r.Header) == "application/x-thrift" {
 			return receiverTransportV1Thrift
 		}
 		return receiverTransportV1JSON
 	}
-	if r.Header.Get("Content-Type") == "application/x-protobuf" {
+	if contentType(r.Header) == "application/x-protobuf" {
 		return receiverTransportV2PROTO
 	}
 	return receiverTransportV2JSON
 }
+
+// contentType returns the media type of the Content-Type header, without its parameters.
+func contentType(hdr http.Header) string {
+	mediaType, _, err := mime.ParseMediaType(hdr.Get("Content-Type"))
+	if err != nil {
+		return hdr.Get("Content-Type")
+	}
+	return mediaType
+}
+
+// writeError writes the error message as an errorResponse.
+func writeError(w http.ResponseWriter, _ *http.Request, errMsg string, statusCode int) {
+	body, _ := json.Marshal(errorResponse{Code: statusCode, Message: errMsg})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
@@ -424,7 +424,8 @@ func TestReceiverInvalidContentType(t *testing.T) {
 	zr.ServeHTTP(req, r)
 
 	require.Equal(t, 400, req.Code)
-	require.Equal(t, "invalid character 'i' looking for beginning of object key string\n", req.Body.String())
+	require.Equal(t, "application/json", req.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"code":400,"message":"failed to parse the Zipkin v2 JSON spans: invalid character 'i' looking for beginning of object key string"}`, req.Body.String())
 }
 
 func TestReceiverConsumerError(t *testing.T) {
@@ -450,6 +451,71 @@ func TestReceiverConsumerError(t *testing.T) {
 	require.Equal(t, "\"Internal Server Error\"", req.Body.String())
 }
 
+func TestReceiverMaxSpansPerRequest(t *testing.T) {
+	body, err := ioutil.ReadFile(zipkinV1SingleBatch)
+	require.NoError(t, err)
+
+	cfg := &Config{
+		ReceiverSettings:   config.NewReceiverSettings(zipkinReceiverID),
+		MaxSpansPerRequest: 4,
+	}
+	sink := new(consumertest.TracesSink)
+	zr, err := New(cfg, sink)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest("POST", "/api/v1/spans", bytes.NewBuffer(body))
+	r.Header.Add("content-type", "application/json")
+	req := httptest.NewRecorder()
+	zr.ServeHTTP(req, r)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, req.Code)
+	require.JSONEq(t, `{"code":413,"message":"the request has 5 spans, more than the maximum of 4"}`, req.Body.String())
+	assert.Equal(t, 0, sink.SpansCount())
+
+	cfg.MaxSpansPerRequest = 5
+	r = httptest.NewRequest("POST", "/api/v1/spans", bytes.NewBuffer(body))
+	r.Header.Add("content-type", "application/json")
+	req = httptest.NewRecorder()
+	zr.ServeHTTP(req, r)
+
+	require.Equal(t, http.StatusAccepted, req.Code)
+	assert.Equal(t, 5, sink.SpansCount())
+}
+
+func TestReceiverInvalidThrift(t *testing.T) {
+	cfg := &Config{
+		ReceiverSettings: config.NewReceiverSettings(zipkinReceiverID),
+	}
+	zr, err := New(cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	// a list of strings
+	r := httptest.NewRequest("POST", "/api/v1/spans", bytes.NewBuffer([]byte{0x0b, 0, 0, 0, 1, 0, 0, 0, 0}))
+	r.Header.Add("content-type", "application/x-thrift; charset=binary")
+	req := httptest.NewRecorder()
+	zr.ServeHTTP(req, r)
+
+	require.Equal(t, http.StatusBadRequest, req.Code)
+	require.JSONEq(t, `{"code":400,"message":"failed to parse the Zipkin v1 Thrift spans: expected a list of spans, got a list of STRING"}`, req.Body.String())
+}
+
+func TestReceiverThriftContentTypeParameters(t *testing.T) {
+	cfg := &Config{
+		ReceiverSettings: config.NewReceiverSettings(zipkinReceiverID),
+	}
+	sink := new(consumertest.TracesSink)
+	zr, err := New(cfg, sink)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest("POST", "/api/v1/spans", bytes.NewBuffer(thriftExample()))
+	r.Header.Add("content-type", "application/x-thrift; charset=binary")
+	req := httptest.NewRecorder()
+	zr.ServeHTTP(req, r)
+
+	require.Equal(t, http.StatusAccepted, req.Code)
+	assert.Equal(t, 1, sink.SpansCount())
+}
+
 func thriftExample() []byte {
 	now := time.Now().Unix()
 	zSpans := []*zipkincore.Span{
@@ -44,6 +44,7 @@ const (
 	servicezPath   = "servicez"
 	pipelinezPath  = "pipelinez"
 	extensionzPath = "extensionz"
+	throttlezPath  = "throttlez"
 )
 
 // State defines Collector's state.
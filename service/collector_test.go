@@ -226,6 +226,7 @@ func assertZPages(t *testing.T) {
 		"/debug/pipelinez",
 		"/debug/servicez",
 		"/debug/extensionz",
+		"/debug/throttlez",
 	}
 
 	const defaultZPagesPort = "55679"
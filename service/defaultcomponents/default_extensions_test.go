@@ -16,6 +16,8 @@ package defaultcomponents
 
 import (
 	"context"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,8 +26,11 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/extension/apikeyauthextension"
+	"go.opentelemetry.io/collector/extension/basicauthextension"
 	"go.opentelemetry.io/collector/extension/bearertokenauthextension"
 	"go.opentelemetry.io/collector/extension/healthcheckextension"
+	"go.opentelemetry.io/collector/extension/oauth2clientauthextension"
 	"go.opentelemetry.io/collector/extension/pprofextension"
 	"go.opentelemetry.io/collector/extension/zpagesextension"
 	"go.opentelemetry.io/collector/testutil"
@@ -37,6 +42,8 @@ func TestDefaultExtensions(t *testing.T) {
 
 	extFactories := allFactories.Extensions
 	endpoint := testutil.GetAvailableLocalAddress(t)
+	keysFile := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, ioutil.WriteFile(keysFile, []byte("somekey\n"), 0600))
 
 	tests := []struct {
 		extension   config.Type
@@ -74,6 +81,32 @@ func TestDefaultExtensions(t *testing.T) {
 				return cfg
 			},
 		},
+		{
+			extension: "basicauth",
+			getConfigFn: func() config.Extension {
+				cfg := extFactories["basicauth"].CreateDefaultConfig().(*basicauthextension.Config)
+				cfg.ClientAuth = &basicauthextension.ClientAuthSettings{Username: "username", Password: "password"}
+				return cfg
+			},
+		},
+		{
+			extension: "apikeyauth",
+			getConfigFn: func() config.Extension {
+				cfg := extFactories["apikeyauth"].CreateDefaultConfig().(*apikeyauthextension.Config)
+				cfg.KeysFile = keysFile
+				return cfg
+			},
+		},
+		{
+			extension: "oauth2client",
+			getConfigFn: func() config.Extension {
+				cfg := extFactories["oauth2client"].CreateDefaultConfig().(*oauth2clientauthextension.Config)
+				cfg.ClientID = "someclientid"
+				cfg.ClientSecret = "someclientsecret"
+				cfg.TokenURL = "https://example.com/oauth2/default/v1/token"
+				return cfg
+			},
+		},
 	}
 
 	// we have one more extension that we can't test here: the OIDC Auth extension requires
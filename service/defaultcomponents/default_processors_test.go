@@ -61,6 +61,9 @@ func TestDefaultProcessors(t *testing.T) {
 		{
 			processor: "filter",
 		},
+		{
+			processor: "logparser",
+		},
 		{
 			processor: "memory_limiter",
 			getConfigFn: func() config.Processor {
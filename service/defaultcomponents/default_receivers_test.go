@@ -71,6 +71,9 @@ func TestDefaultReceivers(t *testing.T) {
 				return cfg
 			},
 		},
+		{
+			receiver: "prometheusremotewrite",
+		},
 		{
 			receiver: "zipkin",
 		},
@@ -51,6 +51,15 @@ func (rcv *builtReceiver) Shutdown(ctx context.Context) error {
 // Receivers is a map of receivers created from receiver configs.
 type Receivers map[config.Receiver]*builtReceiver
 
+// ToMap returns the receivers by their component ID.
+func (rcvs Receivers) ToMap() map[config.ComponentID]component.Receiver {
+	result := make(map[config.ComponentID]component.Receiver, len(rcvs))
+	for k, v := range rcvs {
+		result[k.ID()] = v.receiver
+	}
+	return result
+}
+
 // ShutdownAll stops all receivers.
 func (rcvs Receivers) ShutdownAll(ctx context.Context) error {
 	var errs []error
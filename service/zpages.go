@@ -18,8 +18,11 @@ import (
 	"net/http"
 	"path"
 	"sort"
+	"strconv"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/internal/version"
 	"go.opentelemetry.io/collector/service/internal/zpages"
 )
@@ -37,6 +40,16 @@ func (srv *service) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
 	mux.HandleFunc(path.Join(pathPrefix, extensionzPath), func(w http.ResponseWriter, r *http.Request) {
 		handleExtensionzRequest(srv, w, r)
 	})
+	mux.HandleFunc(path.Join(pathPrefix, throttlezPath), handleThrottlezRequest)
+
+	// Receivers can register their own pages, e.g. the scrape targets of the prometheus receiver.
+	for _, rcv := range srv.builtReceivers.ToMap() {
+		if rcvZPages, ok := rcv.(interface {
+			RegisterZPages(mux *http.ServeMux, pathPrefix string)
+		}); ok {
+			rcvZPages.RegisterZPages(mux, pathPrefix)
+		}
+	}
 }
 
 func (srv *service) handleServicezRequest(w http.ResponseWriter, r *http.Request) {
@@ -53,6 +66,11 @@ func (srv *service) handleServicezRequest(w http.ResponseWriter, r *http.Request
 		ComponentEndpoint: extensionzPath,
 		Link:              true,
 	})
+	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+		Name:              "Throttled Peers",
+		ComponentEndpoint: throttlezPath,
+		Link:              true,
+	})
 	zpages.WriteHTMLPropertiesTable(w, zpages.PropertiesTableData{Name: "Build And Runtime", Properties: version.RuntimeVar()})
 	zpages.WriteHTMLFooter(w)
 }
@@ -147,3 +165,29 @@ func getExtensionsSummaryTableData(host component.Host) zpages.SummaryExtensions
 	})
 	return data
 }
+
+// handleThrottlezRequest lists, for each gRPC server, the clients refused because of its rate or connection limits.
+func handleThrottlezRequest(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm() // nolint:errcheck
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	zpages.WriteHTMLHeader(w, zpages.HeaderData{Title: "Throttled Peers"})
+	throttled := configgrpc.ThrottledPeers()
+	endpoints := make([]string, 0, len(throttled))
+	for endpoint := range throttled {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		data := zpages.PropertiesTableData{Name: endpoint}
+		for _, p := range throttled[endpoint] {
+			data.Properties = append(data.Properties, [2]string{
+				p.Peer,
+				strconv.FormatUint(p.RefusedRequests, 10) + " requests, " +
+					strconv.FormatUint(p.RefusedConnections, 10) + " connections refused, last at " +
+					p.LastRefused.UTC().Format(time.RFC3339),
+			})
+		}
+		zpages.WriteHTMLPropertiesTable(w, data)
+	}
+	zpages.WriteHTMLFooter(w)
+}
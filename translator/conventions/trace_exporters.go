@@ -20,4 +20,7 @@ package conventions
 const (
 	InstrumentationLibraryName    = "otel.library.name"
 	InstrumentationLibraryVersion = "otel.library.version"
+	DroppedAttributesCount        = "otel.dropped_attributes_count"
+	DroppedEventsCount            = "otel.dropped_events_count"
+	DroppedLinksCount             = "otel.dropped_links_count"
 )
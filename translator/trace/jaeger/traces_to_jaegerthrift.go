@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaeger
+
+import (
+	"github.com/jaegertracing/jaeger/model"
+	jaegerconverter "github.com/jaegertracing/jaeger/model/converter/thrift/jaeger"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// ResourceSpansToJaegerThrift translates the spans of a resource into a Jaeger Thrift batch. Returns nil if the
+// resource has neither attributes nor spans.
+func ResourceSpansToJaegerThrift(rs pdata.ResourceSpans) (*jaeger.Batch, error) {
+	pb, err := resourceSpansToJaegerProto(rs)
+	if err != nil || pb == nil {
+		return nil, err
+	}
+	return &jaeger.Batch{
+		Process: jProtoProcessToThrift(pb.Process),
+		Spans:   jaegerconverter.FromDomain(pb.Spans),
+	}, nil
+}
+
+func jProtoProcessToThrift(process *model.Process) *jaeger.Process {
+	if process == nil {
+		return nil
+	}
+	jProcess := &jaeger.Process{
+		ServiceName: process.ServiceName,
+	}
+	if len(process.Tags) == 0 {
+		return jProcess
+	}
+	jProcess.Tags = make([]*jaeger.Tag, 0, len(process.Tags))
+	for _, kv := range process.Tags {
+		jProcess.Tags = append(jProcess.Tags, jProtoTagToThrift(kv))
+	}
+	return jProcess
+}
+
+func jProtoTagToThrift(kv model.KeyValue) *jaeger.Tag {
+	tag := &jaeger.Tag{Key: kv.Key}
+	switch kv.VType {
+	case model.Int64Type:
+		v := kv.Int64()
+		tag.VType = jaeger.TagType_LONG
+		tag.VLong = &v
+	case model.Float64Type:
+		v := kv.Float64()
+		tag.VType = jaeger.TagType_DOUBLE
+		tag.VDouble = &v
+	case model.BoolType:
+		v := kv.Bool()
+		tag.VType = jaeger.TagType_BOOL
+		tag.VBool = &v
+	case model.BinaryType:
+		tag.VType = jaeger.TagType_BINARY
+		tag.VBinary = kv.Binary()
+	default:
+		v := kv.AsString()
+		tag.VType = jaeger.TagType_STRING
+		tag.VStr = &v
+	}
+	return tag
+}
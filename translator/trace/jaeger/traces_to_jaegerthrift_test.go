@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaeger
+
+import (
+	"testing"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/internal/goldendataset"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestResourceSpansToJaegerThrift(t *testing.T) {
+	batch, err := ResourceSpansToJaegerThrift(pdata.NewResourceSpans())
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+
+	batch, err = ResourceSpansToJaegerThrift(generateTracesResourceOnly().ResourceSpans().At(0))
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+	intVal := int64(123)
+	assert.Equal(t, &jaeger.Process{
+		ServiceName: "service-1",
+		Tags: []*jaeger.Tag{
+			{
+				Key:   "int-attr-1",
+				VType: jaeger.TagType_LONG,
+				VLong: &intVal,
+			},
+		},
+	}, batch.Process)
+	assert.Len(t, batch.Spans, 0)
+
+	batch, err = ResourceSpansToJaegerThrift(generateTracesTwoSpansChildParent().ResourceSpans().At(0))
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+	require.Len(t, batch.Spans, 2)
+	parent, child := batch.Spans[0], batch.Spans[1]
+	assert.Equal(t, generateThriftSpan().TraceIdLow, parent.TraceIdLow)
+	assert.Equal(t, generateThriftSpan().TraceIdHigh, parent.TraceIdHigh)
+	assert.Equal(t, generateThriftSpan().SpanId, parent.SpanId)
+	assert.Equal(t, parent.SpanId, child.ParentSpanId)
+	assert.Equal(t, generateThriftChildSpan().OperationName, child.OperationName)
+	assert.Equal(t, generateThriftChildSpan().StartTime, child.StartTime)
+	assert.Equal(t, generateThriftChildSpan().Duration, child.Duration)
+}
+
+func TestResourceSpansToJaegerThriftBatchesAndBack(t *testing.T) {
+	tds, err := goldendataset.GenerateTraces(
+		"../../../internal/goldendataset/testdata/generated_pict_pairs_traces.txt",
+		"../../../internal/goldendataset/testdata/generated_pict_pairs_spans.txt")
+	assert.NoError(t, err)
+	for _, td := range tds {
+		spanCount := 0
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			batch, err := ResourceSpansToJaegerThrift(td.ResourceSpans().At(i))
+			assert.NoError(t, err)
+			if batch != nil {
+				spanCount += ThriftBatchToInternalTraces(batch).SpanCount()
+			}
+		}
+		assert.Equal(t, td.SpanCount(), spanCount)
+	}
+}
@@ -16,6 +16,7 @@ package zipkinv1
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/binary"
 	"errors"
@@ -23,8 +24,8 @@ import (
 	"math"
 	"net"
 
+	"github.com/apache/thrift/lib/go/thrift"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
-	jaegerzipkin "github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
 	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -36,20 +37,62 @@ type thriftUnmarshaler struct{}
 
 // UnmarshalTraces from Thrift bytes.
 func (t thriftUnmarshaler) UnmarshalTraces(buf []byte) (pdata.Traces, error) {
-	spans, err := jaegerzipkin.DeserializeThrift(buf)
+	spans, err := deserializeThrift(buf)
 	if err != nil {
 		return pdata.Traces{}, err
 	}
-	tds, err := v1ThriftBatchToOCProto(spans)
+	return ThriftSpansToInternalTraces(spans)
+}
+
+// NewThriftTracesUnmarshaler returns an unmarshaler for Zipkin Thrift.
+func NewThriftTracesUnmarshaler() pdata.TracesUnmarshaler {
+	return thriftUnmarshaler{}
+}
+
+// ThriftSpansToInternalTraces converts Zipkin v1 Thrift spans to pdata.Traces.
+func ThriftSpansToInternalTraces(zSpans []*zipkincore.Span) (pdata.Traces, error) {
+	tds, err := v1ThriftBatchToOCProto(zSpans)
 	if err != nil {
 		return pdata.Traces{}, err
 	}
 	return toTraces(tds)
 }
 
-// NewThriftTracesUnmarshaler returns an unmarshaler for Zipkin Thrift.
-func NewThriftTracesUnmarshaler() pdata.TracesUnmarshaler {
-	return thriftUnmarshaler{}
+// deserializeThrift decodes a Thrift binary list of Zipkin v1 spans. Unlike the Jaeger deserializer, the list must
+// be a list of structs, and the sizes of the lists and strings are bounded by the size of buf, so that a malformed
+// payload can't allocate more memory than its size.
+func deserializeThrift(buf []byte) ([]*zipkincore.Span, error) {
+	ctx := context.Background()
+	transport := thrift.NewTMemoryBufferLen(len(buf))
+	_, _ = transport.Write(buf)
+	protocol := thrift.NewTBinaryProtocolConf(transport, &thrift.TConfiguration{MaxMessageSize: int32(len(buf))})
+
+	elemType, size, err := protocol.ReadListBegin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if elemType != thrift.STRUCT {
+		return nil, fmt.Errorf("expected a list of spans, got a list of %v", elemType)
+	}
+	// Each span is at least one byte, the field stop.
+	if size > transport.Len() {
+		return nil, fmt.Errorf("list of %d spans larger than the %d remaining bytes", size, transport.Len())
+	}
+	spans := make([]*zipkincore.Span, 0, size)
+	for i := 0; i < size; i++ {
+		zs := &zipkincore.Span{}
+		if err = zs.Read(ctx, protocol); err != nil {
+			return nil, err
+		}
+		spans = append(spans, zs)
+	}
+	if err = protocol.ReadListEnd(ctx); err != nil {
+		return nil, err
+	}
+	if transport.Len() != 0 {
+		return nil, fmt.Errorf("%d unexpected bytes after the list of spans", transport.Len())
+	}
+	return spans, nil
 }
 
 // v1ThriftBatchToOCProto converts Zipkin v1 spans to OC Proto.
@@ -56,6 +56,55 @@ func TestV1ThriftToTraces(t *testing.T) {
 	assert.Equal(t, 5, td.SpanCount())
 }
 
+func TestV1ThriftMalformedBatch(t *testing.T) {
+	span := zipkin.SerializeThrift([]*zipkincore.Span{{Name: "span"}})
+	tests := []struct {
+		name string
+		buf  []byte
+		err  string
+	}{
+		{
+			name: "empty",
+			buf:  []byte{},
+			err:  "EOF",
+		},
+		{
+			name: "list of strings",
+			buf:  []byte{0x0b, 0, 0, 0, 1, 0, 0, 0, 0},
+			err:  "expected a list of spans, got a list of STRING",
+		},
+		{
+			name: "list larger than the payload",
+			buf:  []byte{0x0c, 0x7f, 0xff, 0xff, 0xff, 0},
+			err:  "size exceeded max allowed",
+		},
+		{
+			name: "list of more spans than bytes",
+			buf:  []byte{0x0c, 0, 0, 0, 3, 0},
+			err:  "list of 3 spans larger than the 1 remaining bytes",
+		},
+		{
+			name: "trailing bytes",
+			buf:  append(span, 0),
+			err:  "1 unexpected bytes after the list of spans",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := thriftUnmarshaler{}.UnmarshalTraces(tt.buf)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestThriftSpansToInternalTraces(t *testing.T) {
+	td, err := ThriftSpansToInternalTraces([]*zipkincore.Span{{TraceID: 1, ID: 2, Name: "span"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, td.SpanCount())
+	assert.Equal(t, "span", td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+}
+
 func TestZipkinThriftFallbackToLocalComponent(t *testing.T) {
 	blob, err := ioutil.ReadFile("./testdata/zipkin_v1_thrift_local_component.json")
 	require.NoError(t, err, "Failed to load test data")
@@ -79,10 +79,15 @@ func resourceSpansToZipkinSpans(rs pdata.ResourceSpans, estSpanCount int) ([]*zi
 	zSpans := make([]*zipkinmodel.SpanModel, 0, estSpanCount)
 	for i := 0; i < ilss.Len(); i++ {
 		ils := ilss.At(i)
-		extractInstrumentationLibraryTags(ils.InstrumentationLibrary(), zTags)
+		// Copy the resource tags so that the library tags don't leak to the spans of the next libraries.
+		ilTags := make(map[string]string, len(zTags)+2)
+		for key, val := range zTags {
+			ilTags[key] = val
+		}
+		extractInstrumentationLibraryTags(ils.InstrumentationLibrary(), ilTags)
 		spans := ils.Spans()
 		for j := 0; j < spans.Len(); j++ {
-			zSpan, err := spanToZipkinSpan(spans.At(j), localServiceName, zTags)
+			zSpan, err := spanToZipkinSpan(spans.At(j), localServiceName, ilTags)
 			if err != nil {
 				return zSpans, err
 			}
@@ -172,6 +177,7 @@ func spanToZipkinSpan(
 	if err := spanLinksToZipkinTags(span.Links(), tags); err != nil {
 		return nil, err
 	}
+	droppedCountsToZipkinTags(span, tags)
 
 	zs.Tags = tags
 
@@ -231,6 +237,19 @@ func spanLinksToZipkinTags(links pdata.SpanLinkSlice, zTags map[string]string) e
 	return nil
 }
 
+// droppedCountsToZipkinTags adds the non-zero dropped attributes, events and links counts of the span to the tags.
+func droppedCountsToZipkinTags(span pdata.Span, zTags map[string]string) {
+	if count := span.DroppedAttributesCount(); count > 0 {
+		zTags[conventions.DroppedAttributesCount] = strconv.FormatUint(uint64(count), 10)
+	}
+	if count := span.DroppedEventsCount(); count > 0 {
+		zTags[conventions.DroppedEventsCount] = strconv.FormatUint(uint64(count), 10)
+	}
+	if count := span.DroppedLinksCount(); count > 0 {
+		zTags[conventions.DroppedLinksCount] = strconv.FormatUint(uint64(count), 10)
+	}
+}
+
 func attributeMapToStringMap(attrMap pdata.AttributeMap) map[string]string {
 	rawMap := make(map[string]string)
 	attrMap.Range(func(k string, v pdata.AttributeValue) bool {
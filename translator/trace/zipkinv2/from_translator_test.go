@@ -20,10 +20,12 @@ import (
 
 	zipkinmodel "github.com/openzipkin/zipkin-go/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/internal/goldendataset"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 )
 
 func TestInternalTracesToZipkinSpans(t *testing.T) {
@@ -116,6 +118,92 @@ func TestInternalTracesToZipkinSpansAndBack(t *testing.T) {
 	}
 }
 
+func TestInternalTracesToZipkinSpansAndBackPreservesOTLPFields(t *testing.T) {
+	tds, err := goldendataset.GenerateTraces(
+		"../../../internal/goldendataset/testdata/generated_pict_pairs_traces.txt",
+		"../../../internal/goldendataset/testdata/generated_pict_pairs_spans.txt")
+	require.NoError(t, err)
+	for _, td := range tds {
+		addOTLPOnlyFields(td)
+		zipkinSpans, err := FromTranslator{}.FromTraces(td)
+		require.NoError(t, err)
+		tdFromZS, err := ToTranslator{}.ToTraces(zipkinSpans)
+		require.NoError(t, err)
+		require.Equal(t, td.SpanCount(), tdFromZS.SpanCount())
+
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			ilss := td.ResourceSpans().At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				library := ilss.At(j).InstrumentationLibrary()
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					span := spans.At(k)
+					libraryFromZS, spanFromZS := findLibraryAndSpanByID(tdFromZS.ResourceSpans(), span.SpanID())
+					require.NotNil(t, spanFromZS)
+
+					assert.Equal(t, library.Name(), libraryFromZS.Name())
+					assert.Equal(t, library.Version(), libraryFromZS.Version())
+					assert.Equal(t, span.TraceState(), spanFromZS.TraceState())
+					assert.Equal(t, span.DroppedAttributesCount(), spanFromZS.DroppedAttributesCount())
+					assert.Equal(t, span.DroppedEventsCount(), spanFromZS.DroppedEventsCount())
+					assert.Equal(t, span.DroppedLinksCount(), spanFromZS.DroppedLinksCount())
+					require.Equal(t, span.Links().Len(), spanFromZS.Links().Len())
+					for l := 0; l < span.Links().Len(); l++ {
+						link, linkFromZS := span.Links().At(l), spanFromZS.Links().At(l)
+						link.Attributes().Sort()
+						linkFromZS.Attributes().Sort()
+						assert.Equal(t, link, linkFromZS)
+					}
+					_, ok := spanFromZS.Attributes().Get(conventions.DroppedAttributesCount)
+					assert.False(t, ok)
+				}
+			}
+		}
+	}
+}
+
+// addOTLPOnlyFields sets the dropped counts and an array link attribute on the golden dataset spans, which don't
+// have any, and a version to the second library of the resources.
+func addOTLPOnlyFields(td pdata.Traces) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		ilss := td.ResourceSpans().At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			if j == 1 {
+				ilss.At(j).InstrumentationLibrary().SetVersion("1.2.3")
+			}
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				span.SetDroppedAttributesCount(uint32(k))
+				span.SetDroppedEventsCount(uint32(k + 1))
+				span.SetDroppedLinksCount(uint32(k + 2))
+				for l := 0; l < span.Links().Len(); l++ {
+					values := pdata.NewAttributeValueArray()
+					values.ArrayVal().AppendEmpty().SetStringVal("a")
+					values.ArrayVal().AppendEmpty().SetIntVal(1)
+					span.Links().At(l).Attributes().Upsert("link-values", values)
+				}
+			}
+		}
+	}
+}
+
+func findLibraryAndSpanByID(rs pdata.ResourceSpansSlice, spanID pdata.SpanID) (pdata.InstrumentationLibrary, *pdata.Span) {
+	for i := 0; i < rs.Len(); i++ {
+		instSpans := rs.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < instSpans.Len(); j++ {
+			spans := instSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.SpanID() == spanID {
+					return instSpans.At(j).InstrumentationLibrary(), &span
+				}
+			}
+		}
+	}
+	return pdata.NewInstrumentationLibrary(), nil
+}
+
 func findSpanByID(rs pdata.ResourceSpansSlice, spanID pdata.SpanID) *pdata.Span {
 	for i := 0; i < rs.Len(); i++ {
 		instSpans := rs.At(i).InstrumentationLibrarySpans()
@@ -167,9 +255,11 @@ func zipkinOneSpan() *zipkinmodel.SpanModel {
 			},
 		},
 		Tags: map[string]string{
-			"resource-attr":  "resource-attr-val-1",
-			"status.code":    "STATUS_CODE_ERROR",
-			"status.message": "status-cancelled",
+			"resource-attr":                 "resource-attr-val-1",
+			"status.code":                   "STATUS_CODE_ERROR",
+			"status.message":                "status-cancelled",
+			"otel.dropped_attributes_count": "1",
+			"otel.dropped_events_count":     "1",
 		},
 		Name:      "operationA",
 		Timestamp: testdata.TestSpanStartTime,
@@ -149,6 +149,7 @@ func zSpanToInternal(zspan *zipkinmodel.SpanModel, tags map[string]string, dest
 	if err := zTagsToSpanLinks(tags, dest.Links()); err != nil {
 		return err
 	}
+	populateDroppedCounts(tags, dest)
 
 	attrs := dest.Attributes()
 	attrs.Clear()
@@ -181,6 +182,27 @@ func populateSpanStatus(tags map[string]string, status pdata.SpanStatus) {
 	}
 }
 
+// populateDroppedCounts restores the dropped attributes, events and links counts of the span from the tags. The
+// tags whose value is not a count are kept as attributes.
+func populateDroppedCounts(tags map[string]string, dest pdata.Span) {
+	for key, set := range map[string]func(uint32){
+		conventions.DroppedAttributesCount: dest.SetDroppedAttributesCount,
+		conventions.DroppedEventsCount:     dest.SetDroppedEventsCount,
+		conventions.DroppedLinksCount:      dest.SetDroppedLinksCount,
+	} {
+		value, ok := tags[key]
+		if !ok {
+			continue
+		}
+		count, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			continue
+		}
+		set(uint32(count))
+		delete(tags, key)
+	}
+}
+
 func zipkinKindToSpanKind(kind zipkinmodel.Kind, tags map[string]string) pdata.SpanKind {
 	switch kind {
 	case zipkinmodel.Client:
@@ -302,21 +324,42 @@ func populateSpanEvents(zspan *zipkinmodel.SpanModel, events pdata.SpanEventSlic
 
 func jsonMapToAttributeMap(attrs map[string]interface{}, dest pdata.AttributeMap) error {
 	for key, val := range attrs {
-		if s, ok := val.(string); ok {
-			dest.InsertString(key, s)
-		} else if d, ok := val.(float64); ok {
-			if math.Mod(d, 1.0) == 0.0 {
-				dest.InsertInt(key, int64(d))
-			} else {
-				dest.InsertDouble(key, d)
-			}
-		} else if b, ok := val.(bool); ok {
-			dest.InsertBool(key, b)
+		if av, ok := jsonValueToAttributeValue(val); ok {
+			dest.Insert(key, av)
 		}
 	}
 	return nil
 }
 
+// jsonValueToAttributeValue converts the decoded JSON value to an attribute value, the arrays and objects to array
+// and map values. It returns false for the null values.
+func jsonValueToAttributeValue(val interface{}) (pdata.AttributeValue, bool) {
+	switch v := val.(type) {
+	case string:
+		return pdata.NewAttributeValueString(v), true
+	case float64:
+		if math.Mod(v, 1.0) == 0.0 {
+			return pdata.NewAttributeValueInt(int64(v)), true
+		}
+		return pdata.NewAttributeValueDouble(v), true
+	case bool:
+		return pdata.NewAttributeValueBool(v), true
+	case []interface{}:
+		av := pdata.NewAttributeValueArray()
+		for _, elem := range v {
+			if elemVal, ok := jsonValueToAttributeValue(elem); ok {
+				elemVal.CopyTo(av.ArrayVal().AppendEmpty())
+			}
+		}
+		return av, true
+	case map[string]interface{}:
+		av := pdata.NewAttributeValueMap()
+		_ = jsonMapToAttributeMap(v, av.MapVal())
+		return av, true
+	}
+	return pdata.AttributeValue{}, false
+}
+
 func zTagsToInternalAttrs(zspan *zipkinmodel.SpanModel, tags map[string]string, dest pdata.AttributeMap, parseStringTags bool) error {
 	parseErr := tagsToAttributeMap(tags, dest, parseStringTags)
 	if zspan.LocalEndpoint != nil {
@@ -404,8 +447,8 @@ func populateResourceFromZipkinSpan(tags map[string]string, localServiceName str
 	}
 }
 
-func populateILFromZipkinSpan(tags map[string]string, instrLibName string, library pdata.InstrumentationLibrary) {
-	if instrLibName == "" {
+func populateILFromZipkinSpan(tags map[string]string, instrLibKey string, library pdata.InstrumentationLibrary) {
+	if instrLibKey == "" {
 		return
 	}
 	if value, ok := tags[conventions.InstrumentationLibraryName]; ok {
@@ -433,11 +476,17 @@ func extractLocalServiceName(zspan *zipkinmodel.SpanModel) string {
 	return zspan.LocalEndpoint.ServiceName
 }
 
+// extractInstrumentationLibrary returns the key of the instrumentation library of the span, made of its name and
+// version, so that the spans of the different versions of a library are kept in their own library spans.
 func extractInstrumentationLibrary(zspan *zipkinmodel.SpanModel) string {
 	if zspan == nil || len(zspan.Tags) == 0 {
 		return ""
 	}
-	return zspan.Tags[conventions.InstrumentationLibraryName]
+	name := zspan.Tags[conventions.InstrumentationLibraryName]
+	if version := zspan.Tags[conventions.InstrumentationLibraryVersion]; version != "" {
+		return name + "\xff" + version
+	}
+	return name
 }
 
 func setTimestampsV2(zspan *zipkinmodel.SpanModel, dest pdata.Span, destAttrs pdata.AttributeMap) {
@@ -20,6 +20,7 @@ import (
 
 	zipkinmodel "github.com/openzipkin/zipkin-go/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
@@ -181,3 +182,54 @@ func TestV2SpanWithoutTimestampGetsTag(t *testing.T) {
 	assert.True(t, mapContainedKey)
 	assert.True(t, wasAbsent.BoolVal())
 }
+
+func TestZipkinSpansWithOTLPTagsToInternalTraces(t *testing.T) {
+	newSpan := func(id byte, tags map[string]string) *zipkinmodel.SpanModel {
+		return &zipkinmodel.SpanModel{
+			SpanContext: zipkinmodel.SpanContext{
+				TraceID: convertTraceID(pdata.NewTraceID([16]byte{0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8, 0xF9, 0xFA, 0xFB, 0xFC, 0xFD, 0xFE, 0xFF, 0x80})),
+				ID:      convertSpanID(pdata.NewSpanID([8]byte{0xAF, 0xAE, 0xAD, 0xAC, 0xAB, 0xAA, 0xA9, id})),
+			},
+			LocalEndpoint: &zipkinmodel.Endpoint{ServiceName: "service"},
+			Timestamp:     time.Unix(1600000000, 0),
+			Tags:          tags,
+		}
+	}
+	spans := []*zipkinmodel.SpanModel{
+		newSpan(1, map[string]string{
+			conventions.InstrumentationLibraryName:    "library",
+			conventions.InstrumentationLibraryVersion: "1.0.0",
+			conventions.DroppedAttributesCount:        "1",
+			conventions.DroppedEventsCount:            "2",
+			conventions.DroppedLinksCount:             "3",
+		}),
+		newSpan(2, map[string]string{
+			conventions.InstrumentationLibraryName:    "library",
+			conventions.InstrumentationLibraryVersion: "2.0.0",
+			conventions.DroppedAttributesCount:        "many",
+		}),
+	}
+
+	td, err := ToTranslator{}.ToTraces(spans)
+	require.NoError(t, err)
+	require.Equal(t, 1, td.ResourceSpans().Len())
+	ilss := td.ResourceSpans().At(0).InstrumentationLibrarySpans()
+	require.Equal(t, 2, ilss.Len())
+
+	assert.Equal(t, "library", ilss.At(0).InstrumentationLibrary().Name())
+	assert.Equal(t, "1.0.0", ilss.At(0).InstrumentationLibrary().Version())
+	span := ilss.At(0).Spans().At(0)
+	assert.EqualValues(t, 1, span.DroppedAttributesCount())
+	assert.EqualValues(t, 2, span.DroppedEventsCount())
+	assert.EqualValues(t, 3, span.DroppedLinksCount())
+	assert.Equal(t, 0, span.Attributes().Len())
+
+	assert.Equal(t, "library", ilss.At(1).InstrumentationLibrary().Name())
+	assert.Equal(t, "2.0.0", ilss.At(1).InstrumentationLibrary().Version())
+	span = ilss.At(1).Spans().At(0)
+	assert.EqualValues(t, 0, span.DroppedAttributesCount())
+	// The tags that aren't counts are kept as attributes.
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.DroppedAttributesCount: pdata.NewAttributeValueString("many"),
+	}), span.Attributes())
+}